@@ -7,6 +7,7 @@ require (
 	github.com/fzipp/gocyclo v0.6.0
 	github.com/spf13/cobra v1.8.1
 	github.com/uudashr/gocognit v1.2.0
+	golang.org/x/mod v0.22.0
 	golang.org/x/tools v0.27.0
 )
 
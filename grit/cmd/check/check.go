@@ -0,0 +1,276 @@
+package check
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vbvictor/grit/grit/cmd/flag"
+	"github.com/vbvictor/grit/pkg/check"
+	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/coverage"
+	"github.com/vbvictor/grit/pkg/git"
+	"github.com/vbvictor/grit/pkg/report"
+	"github.com/vbvictor/grit/pkg/testfiles"
+)
+
+var (
+	excludeRegex string
+	since        string
+	until        string
+	testsFilter  testfiles.Filter
+	thresholds   check.Thresholds
+	withCoverage bool
+)
+
+var churnOpts = &git.ChurnOptions{
+	SortBy:       git.Commits,
+	Top:          0,
+	Extensions:   nil,
+	Since:        time.Time{},
+	Until:        time.Time{},
+	Path:         "",
+	ExcludeRegex: nil,
+}
+
+var complexityOpts = &complexity.Options{
+	Engine:       complexity.Gocyclo,
+	ExcludeRegex: nil,
+	Top:          0,
+	OnWarning:    flag.WarnComplexity,
+}
+
+var coverageOpts = &coverage.Options{
+	SortBy:           coverage.Worst,
+	Top:              0,
+	ExcludeRegex:     nil,
+	RunCoverage:      flag.Auto,
+	CoverageFilename: "coverage.out",
+	OnUnresolved:     flag.WarnUnresolvedCoverage,
+	OnTestFailure:    flag.WarnTestFailure,
+}
+
+var reportOpts = report.Options{
+	PerfectCoverage: 100.0, //nolint:mnd // default value
+}
+
+var CheckCmd = &cobra.Command{
+	Use:   "check [flags] <repository>",
+	Short: "Checks code metrics against quality thresholds",
+	Long: `
+Checks code metrics against quality thresholds and fails when any threshold is exceeded.
+Every violation is printed on a separate line, grit exits with code 2 if violations are found.`,
+	Args:          cobra.ExactArgs(1),
+	SilenceErrors: true,
+	RunE: func(_ *cobra.Command, args []string) error {
+		path := filepath.Clean(args[0])
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return fmt.Errorf("repository does not exist: %w", err)
+		}
+
+		if thresholds.Empty() {
+			return fmt.Errorf("no thresholds specified, use --%s, --%s, --%s, --%s or --%s", flag.LongMaxFuncComplex,
+				flag.LongMaxFileComplex, flag.LongMaxScore, flag.LongMinCoverage, flag.LongMinTotalCov)
+		}
+
+		flag.LogIfVerbose("Processing directory: %s\n", path)
+
+		churnOpts.Tests = testsFilter
+		complexityOpts.Tests = testsFilter
+		coverageOpts.Tests = testsFilter
+
+		if err := complexity.PopulateOpts(complexityOpts, excludeRegex); err != nil {
+			return fmt.Errorf("failed to create options: %w", err)
+		}
+
+		if err := coverage.PopulateOpts(coverageOpts, excludeRegex); err != nil {
+			return fmt.Errorf("failed to create options: %w", err)
+		}
+
+		violations, err := checkFunctions(path)
+		if err != nil {
+			return err
+		}
+
+		fileViolations, err := checkFiles(path)
+		if err != nil {
+			return err
+		}
+
+		violations = append(violations, fileViolations...)
+
+		check.Sort(violations)
+		check.PrintViolations(violations, os.Stdout)
+
+		if len(violations) > 0 {
+			return &flag.ExitError{
+				Code: flag.ExitViolations,
+				Err:  fmt.Errorf("quality check failed: %d violation(s)", len(violations)),
+			}
+		}
+
+		return nil
+	},
+}
+
+func checkFunctions(path string) ([]check.Violation, error) {
+	violations := make([]check.Violation, 0)
+
+	var functions []*coverage.FunctionCoverage
+
+	if withCoverage && len(thresholds.MaxFunctionComplexity) > 0 {
+		flag.LogIfVerbose("Analyzing function coverage data...\n")
+
+		var err error
+		if functions, err = coverage.GetFunctionCoverageData(path, coverageOpts); err != nil {
+			return nil, fmt.Errorf("failed to get coverage data: %w", err)
+		}
+	}
+
+	for engine, maxComplexity := range thresholds.MaxFunctionComplexity {
+		flag.LogIfVerbose("Analyzing complexity with %s...\n", engine)
+
+		engineOpts := *complexityOpts
+		engineOpts.Engine = engine
+
+		complexityStats, err := complexity.RunComplexity(path, &engineOpts)
+		if err != nil {
+			return nil, fmt.Errorf("error running complexity analysis: %w", err)
+		}
+
+		coverage.AttachFunctionCoverage(complexityStats, functions)
+		violations = append(violations, check.CheckFunctions(complexityStats, engine, maxComplexity)...)
+	}
+
+	return violations, nil
+}
+
+func checkFiles(path string) ([]check.Violation, error) {
+	metrics, err := collectFileMetrics(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return evaluateFiles(metrics), nil
+}
+
+// fileMetrics holds metrics of files required by thresholds, metrics that aren't required are nil.
+type fileMetrics struct {
+	churns          []*git.ChurnChunk
+	complexityStats []*complexity.FileStat
+	covData         []*coverage.FileCoverage
+}
+
+func collectFileMetrics(path string) (*fileMetrics, error) {
+	var (
+		metrics fileMetrics
+		err     error
+	)
+
+	if thresholds.NeedsComplexity() {
+		flag.LogIfVerbose("Analyzing complexity data...\n")
+
+		if metrics.complexityStats, err = complexity.RunComplexity(path, complexityOpts); err != nil {
+			return nil, fmt.Errorf("error running complexity analysis: %w", err)
+		}
+	}
+
+	if thresholds.NeedsCoverage() {
+		flag.LogIfVerbose("Analyzing coverage data...\n")
+
+		if metrics.covData, err = coverage.GetCoverageData(path, coverageOpts); err != nil {
+			return nil, fmt.Errorf("failed to get coverage data: %w", err)
+		}
+	}
+
+	if thresholds.NeedsChurn() {
+		flag.LogIfVerbose("Analyzing churn data...\n")
+
+		if err = git.PopulateOpts(churnOpts, []string{"go"}, since, until, path, excludeRegex); err != nil {
+			return nil, fmt.Errorf("failed to create options: %w", err)
+		}
+
+		if metrics.churns, err = git.ReadGitChurn(path, churnOpts); err != nil {
+			return nil, fmt.Errorf("error getting churn metrics: %w", err)
+		}
+	}
+
+	return &metrics, nil
+}
+
+func evaluateFiles(metrics *fileMetrics) []check.Violation {
+	violations := make([]check.Violation, 0)
+
+	if thresholds.MaxFileComplexity > 0 {
+		violations = append(violations,
+			check.CheckFiles(metrics.complexityStats, complexityOpts.Engine, thresholds.MaxFileComplexity)...)
+	}
+
+	if thresholds.MinCoverage > 0 {
+		violations = append(violations, check.CheckCoverage(metrics.covData, thresholds.MinCoverage)...)
+	}
+
+	if thresholds.MinTotalCoverage > 0 {
+		violations = append(violations, check.CheckTotalCoverage(metrics.covData, thresholds.MinTotalCoverage)...)
+	}
+
+	if thresholds.MaxScore > 0 {
+		if churnOpts.SortBy == git.Commits {
+			for _, churn := range metrics.churns {
+				churn.Churn = churn.Commits
+			}
+		}
+
+		fileScores := report.CalculateScores(
+			report.CombineMetrics(metrics.churns, metrics.complexityStats, metrics.covData), reportOpts)
+		violations = append(violations, check.CheckScores(fileScores, thresholds.MaxScore)...)
+	}
+
+	return violations
+}
+
+func init() {
+	flags := CheckCmd.PersistentFlags()
+
+	// Common flags
+	flag.ExcludeRegexFlag(flags, &excludeRegex)
+	flag.VerboseFlag(flags, &flag.Verbose)
+	flag.TestsFlag(flags, &testsFilter, testfiles.Include)
+
+	// Threshold flags
+	flags.StringToIntVar(&thresholds.MaxFunctionComplexity, flag.LongMaxFuncComplex, nil,
+		"Maximum function complexity per engine in 'engine=value' form, e.g. 'gocyclo=15,gocognit=20'")
+	flags.Float64Var(&thresholds.MaxFileComplexity, flag.LongMaxFileComplex, 0,
+		"Maximum average file complexity calculated by --"+flag.LongEngine)
+	flags.Float64Var(&thresholds.MaxScore, flag.LongMaxScore, 0, "Maximum file maintainability score from report")
+	flags.Float64Var(&thresholds.MinCoverage, flag.LongMinCoverage, 0,
+		"Minimum file coverage percentage, files without statements are not checked")
+	flags.Float64Var(&thresholds.MinTotalCoverage, flag.LongMinTotalCov, 0,
+		"Minimum coverage percentage of all statements of the repository")
+
+	// Churn flags
+	flag.SinceFlag(flags, &since)
+	flag.UntilFlag(flags, &until)
+	flag.ChurnTypeFlag(flags, &churnOpts.SortBy, git.Commits)
+
+	// Complexity flags
+	flag.ComplexityEngineFlag(flags, &complexityOpts.Engine)
+	flag.ExternalEngineFlag(flags, &complexityOpts.ExternalEngines)
+	flag.GoFilesFlags(flags, complexityOpts)
+	flag.CSVEngineFlags(flags, complexityOpts)
+
+	// Coverage flags
+	flag.RunCoverageFlag(flags, &coverageOpts.RunCoverage)
+	flag.TestRunnerFlags(flags, &coverageOpts.Runner)
+	flag.CoverageFilenameFlag(flags, &coverageOpts.CoverageFilename)
+	flag.MergeCoverageFlag(flags, &coverageOpts.Profiles)
+	flag.UntestedFlag(flags, &coverageOpts.NoUntested)
+	flag.CoverageFormatFlag(flags, &coverageOpts.Format, coverage.Formats)
+	flag.PerfectCoverageFlag(flags, &reportOpts.PerfectCoverage)
+	flag.WithCoverageFlag(flags, &withCoverage, "Add coverage to function complexity violations")
+
+	CheckCmd.Flag(flag.LongUntil).DefValue = flag.DefaultUntil
+	CheckCmd.Flag(flag.LongSince).DefValue = flag.DefaultSince
+}
@@ -0,0 +1,16 @@
+package diff
+
+import (
+	"github.com/spf13/cobra"
+	diff "github.com/vbvictor/grit/grit/cmd/diff/subcommands"
+)
+
+var DiffCmd = &cobra.Command{ //nolint:exhaustruct // no need to set all fields
+	Use:   "diff",
+	Short: "Compare code metrics between git revisions",
+}
+
+func init() {
+	DiffCmd.AddCommand(diff.ComplexityCmd)
+	DiffCmd.AddCommand(diff.CoverageCmd)
+}
@@ -0,0 +1,127 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/vbvictor/grit/grit/cmd/flag"
+	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/git"
+	"github.com/vbvictor/grit/pkg/testfiles"
+)
+
+var complexityOpts = complexity.Options{
+	Engine:       complexity.Gocyclo,
+	ExcludeRegex: nil,
+	Top:          flag.DefaultTop,
+	OutputFormat: "",
+	OnWarning:    flag.WarnComplexity,
+}
+
+var (
+	excludeComplexityRegex string
+	baseRef                string
+	headRef                string
+)
+
+var ComplexityCmd = &cobra.Command{ //nolint:exhaustruct // no need to set all fields
+	Use:   "complexity [flags] <path>",
+	Short: "Finds functions whose complexity changed between two revisions",
+	Long: `
+Calculates function complexity in base and head revisions and reports functions that were added,
+removed, got more complex or got simpler. Revisions are checked out into temporary git worktrees,
+the working tree of <path> is analyzed as head revision when --head is not given.
+Functions are matched by directory, package, receiver and name.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		path := filepath.Clean(args[0])
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return fmt.Errorf("repository does not exist: %w", err)
+		}
+
+		if err := complexity.PopulateOpts(&complexityOpts, excludeComplexityRegex); err != nil {
+			return fmt.Errorf("failed to create options: %w", err)
+		}
+
+		baseStats, err := complexityAt(path, baseRef)
+		if err != nil {
+			return err
+		}
+
+		headStats, err := complexityAt(path, headRef)
+		if err != nil {
+			return err
+		}
+
+		diffs := complexity.DiffFunctions(baseStats, headStats, complexityOpts)
+		flag.LogIfVerbose("Got %d changed functions\n", len(diffs))
+
+		return printComplexityDiff(diffs, os.Stdout, &complexityOpts)
+	},
+}
+
+// complexityAt calculates complexity of path at given revision, empty ref stands for the working tree.
+func complexityAt(path, ref string) ([]*complexity.FileStat, error) {
+	if ref == "" {
+		flag.LogIfVerbose("Analyzing complexity of working tree...\n")
+
+		return runComplexity(path)
+	}
+
+	flag.LogIfVerbose("Analyzing complexity at %s...\n", ref)
+
+	worktree, err := git.AddWorktree(path, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := worktree.Remove(); err != nil {
+			flag.PrintWarning("%v\n", err)
+		}
+	}()
+
+	return runComplexity(worktree.Path)
+}
+
+func runComplexity(path string) ([]*complexity.FileStat, error) {
+	fileStat, err := complexity.RunComplexity(path, &complexityOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error running complexity analysis: %w", err)
+	}
+
+	return fileStat, nil
+}
+
+func init() {
+	flags := ComplexityCmd.PersistentFlags()
+
+	flag.BaseFlag(flags, &baseRef)
+	flag.HeadFlag(flags, &headRef)
+	flag.ComplexityEngineFlag(flags, &complexityOpts.Engine)
+	flag.ExternalEngineFlag(flags, &complexityOpts.ExternalEngines)
+	flag.GoFilesFlags(flags, &complexityOpts)
+	flag.TestsFlag(flags, &complexityOpts.Tests, testfiles.Include)
+	flag.TopFlag(flags, &complexityOpts.Top)
+	flag.VerboseFlag(flags, &flag.Verbose)
+	flag.ExcludeRegexFlag(flags, &excludeComplexityRegex)
+	flag.OutputFormatFlag(flags, &complexityOpts.OutputFormat)
+
+	_ = ComplexityCmd.MarkPersistentFlagRequired(flag.LongBase)
+}
+
+func printComplexityDiff(results []complexity.FunctionDiff, out io.Writer, opts *complexity.Options) error {
+	switch opts.OutputFormat {
+	case flag.CSV:
+		complexity.PrintDiffCSV(results, out)
+	case flag.Tabular:
+		complexity.PrintDiffTabular(results, out)
+	default:
+		return fmt.Errorf("unsupported output format: %s", opts.OutputFormat)
+	}
+
+	return nil
+}
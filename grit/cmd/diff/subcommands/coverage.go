@@ -0,0 +1,149 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/vbvictor/grit/grit/cmd/flag"
+	"github.com/vbvictor/grit/pkg/coverage"
+	"github.com/vbvictor/grit/pkg/git"
+	"github.com/vbvictor/grit/pkg/module"
+	"github.com/vbvictor/grit/pkg/testfiles"
+)
+
+var coverageOpts = &coverage.Options{
+	RunCoverage:      flag.Auto,
+	CoverageFilename: "coverage.out",
+	OutputFormat:     "",
+	OnUnresolved:     flag.WarnUnresolvedCoverage,
+	OnTestFailure:    flag.WarnTestFailure,
+}
+
+var (
+	excludeCoverageRegex string
+	coverageBaseRef      string
+	minPatchCoverage     float64
+	coverageByModule     bool
+)
+
+var CoverageCmd = &cobra.Command{ //nolint:exhaustruct // no need to set all fields
+	Use:   "coverage [flags] <path>",
+	Short: "Reports coverage of lines changed since base revision",
+	Long: `
+Joins lines added or modified in the working tree since it diverged from base revision with coverage
+profile of the working tree and reports changed lines that aren't covered. Only changed lines holding
+statements are counted, untracked files aren't part of the diff and must be added to the index first.
+Changed Go files missing from coverage data are reported with a warning and their statements aren't covered.
+With --by-module, changed lines of every Go module are summed into one entry without uncovered line numbers.
+Exits with code 2 when patch coverage is below --min-coverage.`,
+	Args:          cobra.ExactArgs(1),
+	SilenceErrors: true,
+	RunE: func(_ *cobra.Command, args []string) error {
+		path := filepath.Clean(args[0])
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return fmt.Errorf("repository does not exist: %w", err)
+		}
+
+		if err := coverage.PopulateOpts(coverageOpts, excludeCoverageRegex); err != nil {
+			return fmt.Errorf("failed to create options: %w", err)
+		}
+
+		if coverageByModule {
+			modules, err := module.Discover(path)
+			if err != nil {
+				return fmt.Errorf("failed to discover modules: %w", err)
+			}
+
+			coverageOpts.Modules = modules
+		}
+
+		flag.LogIfVerbose("Reading lines changed since %s...\n", coverageBaseRef)
+
+		changed, err := git.ChangedLines(path, coverageBaseRef)
+		if err != nil {
+			return err
+		}
+
+		flag.LogIfVerbose("Got %d changed files\n", len(changed))
+
+		profiles, err := coverage.GetProfiles(path, coverageOpts)
+		if err != nil {
+			return fmt.Errorf("failed to get coverage data: %w", err)
+		}
+
+		unprofiled, err := coverage.MissingProfiles(path, profiles, changed, coverageOpts)
+		if err != nil {
+			return fmt.Errorf("failed to get coverage data: %w", err)
+		}
+
+		for _, profile := range unprofiled {
+			flag.PrintWarning("coverage data has no statements of changed %s, they are counted as not covered\n",
+				profile.FileName)
+		}
+
+		profiles = append(profiles, unprofiled...)
+		files := coverage.PatchCoverage(profiles, changed)
+		summary := coverage.SummarizePatch(files)
+
+		if coverageByModule {
+			files = coverage.RollupPatchByModule(files, coverageOpts.Modules)
+		}
+
+		if err := printPatchCoverage(files, summary, os.Stdout, coverageOpts); err != nil {
+			return err
+		}
+
+		if summary.Coverage < minPatchCoverage {
+			return &flag.ExitError{
+				Code: flag.ExitViolations,
+				Err: fmt.Errorf("patch coverage check failed: %.2f%% of changed lines are covered, at least %.2f%% required",
+					summary.Coverage, minPatchCoverage),
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	flags := CoverageCmd.PersistentFlags()
+
+	flag.BaseFlag(flags, &coverageBaseRef)
+	flags.Float64Var(&minPatchCoverage, flag.LongMinCoverage, 0,
+		fmt.Sprintf("Minimum coverage percentage of changed lines, exits with code %d when it isn't reached",
+			flag.ExitViolations))
+	flag.RunCoverageFlag(flags, &coverageOpts.RunCoverage)
+	flag.TestRunnerFlags(flags, &coverageOpts.Runner)
+	flag.CoverageFilenameFlag(flags, &coverageOpts.CoverageFilename)
+	flag.MergeCoverageFlag(flags, &coverageOpts.Profiles)
+	flag.UntestedFlag(flags, &coverageOpts.NoUntested)
+	flag.CoverageFormatFlag(flags, &coverageOpts.Format, coverage.Formats)
+	flag.TestsFlag(flags, &coverageOpts.Tests, testfiles.Include)
+	flag.ByModuleFlag(flags, &coverageByModule)
+	flag.VerboseFlag(flags, &flag.Verbose)
+	flag.ExcludeRegexFlag(flags, &excludeCoverageRegex)
+	flag.OutputFormatFlag(flags, &coverageOpts.OutputFormat)
+
+	_ = CoverageCmd.MarkPersistentFlagRequired(flag.LongBase)
+}
+
+func printPatchCoverage(
+	results []*coverage.PatchFile,
+	summary coverage.PatchSummary,
+	out io.Writer,
+	opts *coverage.Options,
+) error {
+	switch opts.OutputFormat {
+	case flag.CSV:
+		coverage.PrintPatchCSV(results, out)
+	case flag.Tabular:
+		coverage.PrintPatchTabular(results, summary, out)
+	default:
+		return fmt.Errorf("unsupported output format: %s", opts.OutputFormat)
+	}
+
+	return nil
+}
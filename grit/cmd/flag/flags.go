@@ -3,10 +3,17 @@ package flag
 import (
 	"errors"
 	"fmt"
+	"os"
+	"slices"
+	"strings"
 
 	"github.com/spf13/pflag"
 	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/duplication"
 	"github.com/vbvictor/grit/pkg/git"
+	"github.com/vbvictor/grit/pkg/gotest"
+	"github.com/vbvictor/grit/pkg/sarif"
+	"github.com/vbvictor/grit/pkg/testfiles"
 )
 
 // Global flags used across all commands
@@ -41,6 +48,9 @@ var (
 	// Output format flags.
 	Tabular                OutputType = "tabular"
 	CSV                    OutputType = "csv"
+	SARIF                  OutputType = "sarif"
+	LCOV                   OutputType = "lcov"
+	Cobertura              OutputType = "cobertura"
 	AvailableOutputFormats            = []OutputType{Tabular, CSV}
 
 	// Coverage Run formats.
@@ -60,17 +70,65 @@ const (
 )
 
 const (
-	LongSort         = "sort"
-	LongTop          = "top"
-	LongVerbose      = "verbose"
-	LongExclude      = "exclude"
-	LongExtensions   = "ext"
-	LongSince        = "since"
-	LongUntil        = "until"
-	LongFormat       = "format"
-	LongEngine       = "complexity-engine"
-	LongRunCoverage  = "run-tests"
-	LongFileCoverage = "coverage-file"
+	LongSort          = "sort"
+	LongTop           = "top"
+	LongVerbose       = "verbose"
+	LongExclude       = "exclude"
+	LongExtensions    = "ext"
+	LongSince         = "since"
+	LongUntil         = "until"
+	LongFormat        = "format"
+	LongEngine        = "complexity-engine"
+	LongRunCoverage   = "run-tests"
+	LongFileCoverage  = "coverage-file"
+	LongPerFunction   = "per-function"
+	LongMinComplex    = "min-complexity"
+	LongExternalEng   = "external-engine"
+	LongComplexFile   = "complexity-file"
+	LongCSVColumns    = "csv-columns"
+	LongGenerated     = "include-generated"
+	LongNoExcludes    = "no-default-excludes"
+	LongNoGitignore   = "no-gitignore"
+	LongTests         = "tests"
+	LongTestPattern   = "test-pattern"
+	LongCompareTests  = "compare-tests"
+	LongSARIFLevels   = "sarif-levels"
+	LongBase          = "base"
+	LongHead          = "head"
+	LongSamples       = "samples"
+	LongTags          = "tags"
+	LongLevel         = "level"
+	LongProfilesDir   = "coverage-profiles"
+	LongBuildTags     = "build-tags"
+	LongGOOS          = "goos"
+	LongGOARCH        = "goarch"
+	LongByModule      = "by-module"
+	LongMinTokens     = "min-tokens"
+	LongClones        = "clones"
+	LongDupFactor     = "duplication-factor"
+	LongByPackage     = "by-package"
+	LongDocsFactor    = "docs-factor"
+	LongMergeCoverage = "merge-coverage"
+	LongCovFormat     = "coverage-format"
+	LongTestPackages  = "test-packages"
+	LongTestTags      = "test-tags"
+	LongRace          = "race"
+	LongShort         = "short"
+	LongTestTimeout   = "test-timeout"
+	LongCoverPkg      = "coverpkg"
+	LongTestParallel  = "test-parallel"
+	LongTestEnv       = "test-env"
+	LongWithCoverage  = "with-coverage"
+	LongNoUntested    = "no-untested"
+
+	LongMaxFuncComplex = "max-function-complexity"
+	LongMaxFileComplex = "max-file-complexity"
+	LongMaxScore       = "max-score"
+	LongMinCoverage    = "min-coverage"
+	LongMinTotalCov    = "min-total-coverage"
+	LongCRAPThreshold  = "crap-threshold"
+	LongMaxCrappy      = "max-crappy"
+	LongFail           = "fail"
 
 	// Flag shortcuts.
 	ShortTop          = "t"
@@ -93,6 +151,11 @@ func LogIfVerbose(format string, args ...any) {
 	}
 }
 
+// PrintWarning reports a non-fatal problem to stderr.
+func PrintWarning(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "Warning: "+format, args...)
+}
+
 type AbsRepoPathError struct {
 	Path string
 }
@@ -101,6 +164,23 @@ func (e *AbsRepoPathError) Error() string {
 	return "failed to get absolute path from " + e.Path
 }
 
+// ExitError makes grit exit with given code instead of the generic failure code.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitViolations is returned by commands that found quality threshold violations.
+const ExitViolations = 2
+
 var (
 	ErrCoverageNotFound = errors.New("failed to find file with code coverage")
 	ErrReadCoverage     = errors.New("failed to read coverage file")
@@ -134,7 +214,7 @@ func UntilFlag(f *pflag.FlagSet, until *string) {
 
 func EngineFlag(f *pflag.FlagSet, engine *string, defaultValue string) {
 	f.StringVarP(engine, LongEngine, ShortEngine, defaultValue,
-		fmt.Sprintf("Specify complexity calculation engine: [%s, %s]", complexity.Gocyclo, complexity.Gocognit))
+		fmt.Sprintf("Specify complexity calculation engine: [%s]", complexity.EnginesHelp()))
 }
 
 func SortFlag(f *pflag.FlagSet, sortBy *string, defaultValue string, description string) { //nolint: gocritic // unified
@@ -152,16 +232,62 @@ func RunCoverageFlag(f *pflag.FlagSet, runCoverage *string) {
 
 func CoverageFilenameFlag(f *pflag.FlagSet, filename *string) {
 	f.StringVarP(filename, LongFileCoverage, ShortFileCoverage, "coverage.out",
-		"Name of code coverage file to read or create")
+		"Name of code coverage file to read or create, or GOCOVERDIR directory of binaries built with -cover")
+}
+
+// MergeCoverageFlag registers additional coverage sources merged with --coverage-file.
+func MergeCoverageFlag(f *pflag.FlagSet, profiles *[]string) {
+	f.StringArrayVar(profiles, LongMergeCoverage, nil,
+		fmt.Sprintf("Coverage profile or GOCOVERDIR directory merged with --%s, e.g. integration tests data, "+
+			"can be repeated", LongFileCoverage))
+}
+
+// UntestedFlag registers flag disabling report of Go files missing from Go coverage profiles as not covered.
+func UntestedFlag(f *pflag.FlagSet, noUntested *bool) {
+	f.BoolVar(noUntested, LongNoUntested, false,
+		"Don't report Go files missing from coverage profiles, e.g. files of packages without tests, as not covered. "+
+			"Statements of such files are estimated one per statement, profiles of newer Go versions repeat statement "+
+			"counts of blocks split by blank lines, so untested files may weigh less than tested ones")
+}
+
+// CoverageFormatFlag registers format of coverage sources, formats[0] is the default.
+func CoverageFormatFlag(f *pflag.FlagSet, format *string, formats []string) {
+	f.StringVar(format, LongCovFormat, formats[0],
+		fmt.Sprintf("Format of coverage sources: [%s], reports of other languages allow analyzing non-Go code",
+			strings.Join(formats, ", ")))
+}
+
+// TestRunnerFlags registers flags of go test run creating coverage profile.
+func TestRunnerFlags(f *pflag.FlagSet, opts *gotest.Options) {
+	f.StringSliceVar(&opts.Packages, LongTestPackages, []string{gotest.DefaultPackages},
+		"Package patterns tested to create coverage profile, untested Go files of other packages aren't reported")
+	f.StringSliceVar(&opts.Tags, LongTestTags, nil, "Build tags of tests, e.g. 'integration,e2e'")
+	f.BoolVar(&opts.Race, LongRace, false, "Run tests with the race detector")
+	f.BoolVar(&opts.Short, LongShort, false, "Run tests with -short to skip long-running tests")
+	f.DurationVar(&opts.Timeout, LongTestTimeout, 0, "Timeout of tests of a package, go test default is used when 0")
+	f.StringVar(&opts.CoverPkg, LongCoverPkg, "",
+		"Comma-separated package patterns coverage is collected for, e.g. './...' to count cross-package tests")
+	f.IntVar(&opts.Parallel, LongTestParallel, 0,
+		"Number of packages tested in parallel, the number of CPUs is used when 0")
+	f.StringArrayVar(&opts.Env, LongTestEnv, nil, "KEY=VALUE environment variable of tests, can be repeated")
 }
 
 func TopFlag(f *pflag.FlagSet, top *int) {
 	f.IntVarP(top, LongTop, ShortTop, DefaultTop, "Number of top files to display")
 }
 
-func OutputFormatFlag(f *pflag.FlagSet, format *string) {
+// OutputFormatFlag registers output format flag, commands may support formats in addition to tabular and CSV.
+func OutputFormatFlag(f *pflag.FlagSet, format *string, extra ...OutputType) {
+	formats := append(slices.Clone(AvailableOutputFormats), extra...)
+
 	f.StringVarP(format, LongFormat, ShortFormat, Tabular,
-		fmt.Sprintf("Specify output format: [%s, %s]", Tabular, CSV))
+		fmt.Sprintf("Specify output format: [%s]", strings.Join(formats, ", ")))
+}
+
+func SARIFLevelsFlag(f *pflag.FlagSet, levels *[]float64, defaults sarif.Levels) {
+	f.Float64SliceVar(levels, LongSARIFLevels, []float64{defaults.Warning, defaults.Error},
+		fmt.Sprintf("Values starting from which SARIF results are reported as 'warning' and 'error' (used with --%s %s)",
+			LongFormat, SARIF))
 }
 
 func ExtensionsFlag(f *pflag.FlagSet, extensions *[]string) {
@@ -171,14 +297,180 @@ func ExtensionsFlag(f *pflag.FlagSet, extensions *[]string) {
 
 func ComplexityEngineFlag(f *pflag.FlagSet, engine *string) {
 	f.StringVarP(engine, LongEngine, ShortEngine, complexity.Gocyclo,
-		fmt.Sprintf(`Specify complexity calculation engine: [%s, %s, %s].
-When CSV engine is specified, GRIT will try to read function complexity data from CSV file
-'complexity.csv' located in <path>. The file should have following fields:
-"filename,function,complexity,line-count (optional),packages (optional)"
-`, complexity.Gocyclo, complexity.Gocognit, complexity.CSV))
+		fmt.Sprintf(`Specify complexity calculation engine: [%s].
+When CSV engine is specified, GRIT will read function complexity data from CSV files given
+with --%s, 'complexity.csv' located in <path> is read by default.
+CSV files without header should have following fields:
+"file,function,length,complexity,line (optional),packages (optional)"
+CSV files with header may have these columns in any order, see --%s.
+Engines added with --%s are also accepted.
+`, complexity.EnginesHelp(), LongComplexFile, LongCSVColumns, LongExternalEng))
+}
+
+func ComplexityFileFlag(f *pflag.FlagSet, files *[]string) {
+	f.StringArrayVar(files, LongComplexFile, nil,
+		"Path or glob pattern of CSV file read by CSV engine, relative to <path>, can be repeated")
+}
+
+func CSVColumnsFlag(f *pflag.FlagSet, columns *[]string) {
+	f.StringSliceVar(columns, LongCSVColumns, nil,
+		fmt.Sprintf("Map CSV engine columns %v to header names or 1-based column numbers, e.g. 'complexity=ccn,file=2'",
+			complexity.DefaultCSVColumns))
+}
+
+// CSVEngineFlags registers flags configuring CSV complexity engine.
+func CSVEngineFlags(f *pflag.FlagSet, opts *complexity.Options) {
+	ComplexityFileFlag(f, &opts.ComplexityFiles)
+	CSVColumnsFlag(f, &opts.CSVColumns)
+}
+
+func ExternalEngineFlag(f *pflag.FlagSet, engines *[]string) {
+	f.StringArrayVar(engines, LongExternalEng, nil,
+		`Register external complexity engine in 'name=command' form, can be repeated.
+Command is run in <path> and must print function complexity on stdout
+as CSV (same fields as CSV engine) or JSON object with "functions" array, e.g.
+'lizard=python3 scripts/lizard_complexity.py --output - .'
+Command is split by whitespace, quotes are not supported, use a script for arguments with spaces.
+Names of builtin engines can't be reused.
+`)
+}
+
+func BaseFlag(f *pflag.FlagSet, ref *string) {
+	f.StringVar(ref, LongBase, "", "Base git revision to compare with, e.g. 'main' or 'HEAD~1'")
+}
+
+func HeadFlag(f *pflag.FlagSet, ref *string) {
+	f.StringVar(ref, LongHead, "", "Head git revision to compare, working tree is used if not specified")
+}
+
+func ByModuleFlag(f *pflag.FlagSet, byModule *bool) {
+	f.BoolVar(byModule, LongByModule, false,
+		"Aggregate metrics of Go modules found by go.work or go.mod files instead of reporting separate files")
+}
+
+func ByPackageFlag(f *pflag.FlagSet, byPackage *bool, description string) {
+	f.BoolVar(byPackage, LongByPackage, false, description)
+}
+
+func DocsFactorFlag(f *pflag.FlagSet, factor *float64) {
+	f.Float64Var(factor, LongDocsFactor, 0,
+		"Weight of undocumented exported identifiers in the score, score is multiplied by "+
+			"(1 + factor * (100 - documentation coverage) / 100). Documentation is not analyzed when set to 0")
+}
+
+func MinTokensFlag(f *pflag.FlagSet, minTokens *int) {
+	f.IntVar(minTokens, LongMinTokens, duplication.DefaultMinTokens,
+		"Minimal number of tokens in duplicated code fragment, identifiers and literals are compared by kind only")
+}
+
+func ClonesFlag(f *pflag.FlagSet, clones *bool) {
+	f.BoolVar(clones, LongClones, false, "List pairs of duplicated code fragments instead of files")
+}
+
+func DuplicationFactorFlag(f *pflag.FlagSet, factor *float64) {
+	f.Float64Var(factor, LongDupFactor, 0,
+		"Weight of duplicated lines percentage in the score, score is multiplied by (1 + factor * duplication / 100). "+
+			"Duplication is not analyzed when set to 0")
 }
 
 func PerfectCoverageFlag(f *pflag.FlagSet, perfectCoverage *float64) {
 	f.Float64Var(perfectCoverage, "perfect-coverage", 100.0, //nolint:mnd // default value
 		"Specify code coverage penalty threshold")
 }
+
+func PerFunctionFlag(f *pflag.FlagSet, perFunction *bool, description string) {
+	f.BoolVar(perFunction, LongPerFunction, false, description)
+}
+
+// WithCoverageFlag registers flag joining coverage of functions into reports listing functions,
+// description tells how coverage is reported.
+func WithCoverageFlag(f *pflag.FlagSet, withCoverage *bool, description string) {
+	f.BoolVar(withCoverage, LongWithCoverage, false,
+		fmt.Sprintf("%s, coverage data is read or created as set by --%s and --%s",
+			description, LongRunCoverage, LongFileCoverage))
+}
+
+// CRAPThresholdFlag registers CRAP score above which functions are considered crappy.
+func CRAPThresholdFlag(f *pflag.FlagSet, threshold *float64, defaultValue float64) {
+	f.Float64Var(threshold, LongCRAPThreshold, defaultValue, "CRAP score above which functions are considered crappy")
+}
+
+// MaxCrappyFlag registers allowed percentage of crappy functions checked with --fail.
+func MaxCrappyFlag(f *pflag.FlagSet, maxCrappy *float64) {
+	f.Float64Var(maxCrappy, LongMaxCrappy, 0,
+		fmt.Sprintf("Maximum allowed percentage of crappy functions (used with --%s)", LongFail))
+}
+
+// FailFlag registers flag making command exit with ExitViolations code, description tells when it happens.
+func FailFlag(f *pflag.FlagSet, fail *bool, description string) {
+	f.BoolVar(fail, LongFail, false, fmt.Sprintf("Exit with code %d %s", ExitViolations, description))
+}
+
+func MinComplexityFlag(f *pflag.FlagSet, minComplexity *int) {
+	f.IntVar(minComplexity, LongMinComplex, 0,
+		fmt.Sprintf("Only include functions with complexity at least given value (used with --%s)", LongPerFunction))
+}
+
+func GeneratedFlag(f *pflag.FlagSet, includeGenerated *bool) {
+	f.BoolVar(includeGenerated, LongGenerated, false,
+		"Analyze generated files marked with '// Code generated ... DO NOT EDIT.'")
+}
+
+func DefaultExcludesFlag(f *pflag.FlagSet, includeDefaultExcludes *bool) {
+	f.BoolVar(includeDefaultExcludes, LongNoExcludes, false,
+		fmt.Sprintf("Analyze directories skipped by default: %v and directories starting with '.' or '_'",
+			complexity.DefaultExcludeDirs))
+}
+
+func GitignoreFlag(f *pflag.FlagSet, noGitignore *bool) {
+	f.BoolVar(noGitignore, LongNoGitignore, false, "Analyze files ignored by .gitignore")
+}
+
+// BuildContextFlags registers flags selecting build context whose files are analyzed by Go engines.
+func BuildContextFlags(f *pflag.FlagSet, opts *complexity.Options) {
+	f.StringSliceVar(&opts.BuildTags, LongBuildTags, nil,
+		"Only analyze files matching build constraints with given comma-separated build tags, e.g. 'integration,linux'")
+	f.StringVar(&opts.GOOS, LongGOOS, "",
+		"Only analyze files built for given operating system, current one is used if only other build flags are set")
+	f.StringVar(&opts.GOARCH, LongGOARCH, "",
+		"Only analyze files built for given architecture, current one is used if only other build flags are set")
+}
+
+// GoFilesFlags registers flags controlling which Go files are analyzed by complexity engines.
+// Files of all build contexts are analyzed together unless build context flags are set.
+func GoFilesFlags(f *pflag.FlagSet, opts *complexity.Options) {
+	GeneratedFlag(f, &opts.IncludeGenerated)
+	DefaultExcludesFlag(f, &opts.IncludeDefaultExcludes)
+	GitignoreFlag(f, &opts.NoGitignore)
+	BuildContextFlags(f, opts)
+}
+
+// WarnComplexity prints files that complexity engines failed to analyze.
+func WarnComplexity(w complexity.Warning) {
+	PrintWarning("%s\n", w)
+}
+
+// WarnUnresolvedCoverage reports file of coverage sources that can't be matched to the repository.
+func WarnUnresolvedCoverage(file string, reason error) {
+	PrintWarning("skipping coverage of %s: %v\n", file, reason)
+}
+
+// WarnTestFailure reports package whose tests failed while coverage profile was created.
+func WarnTestFailure(failure gotest.Failure) {
+	PrintWarning("%s, its coverage may be incomplete\n", failure)
+}
+
+// TestsFlag registers flags that separate production and test code, test code is treated by mode unless specified.
+func TestsFlag(f *pflag.FlagSet, filter *testfiles.Filter, mode string) {
+	f.StringVar(&filter.Mode, LongTests, mode,
+		fmt.Sprintf("Specify how test code is treated: [%s, %s, %s]. Files ending with '_test.go' are always tests",
+			testfiles.Include, testfiles.Exclude, testfiles.Only))
+	f.StringArrayVar(&filter.Patterns, LongTestPattern, nil,
+		"Regex pattern of additional test code paths, e.g. 'testutil/', can be repeated")
+}
+
+func CompareTestsFlag(f *pflag.FlagSet, compareTests *bool) {
+	f.BoolVar(compareTests, LongCompareTests, false,
+		fmt.Sprintf("Compare complexity and churn of each production file with its '_test.go' file instead of scoring "+
+			"files, requires --%s %s", LongTests, testfiles.Include))
+}
@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"errors"
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/vbvictor/grit/grit/cmd/check"
+	"github.com/vbvictor/grit/grit/cmd/diff"
 	"github.com/vbvictor/grit/grit/cmd/flag"
+	"github.com/vbvictor/grit/grit/cmd/history"
 	"github.com/vbvictor/grit/grit/cmd/plot"
 	"github.com/vbvictor/grit/grit/cmd/report"
 	"github.com/vbvictor/grit/grit/cmd/stat"
@@ -20,6 +24,12 @@ their codebase maintainability index through calculated metrics.`,
 
 func Execute() {
 	if err := gritCmd.Execute(); err != nil {
+		var exitErr *flag.ExitError
+		if errors.As(err, &exitErr) {
+			gritCmd.PrintErrln(exitErr.Error())
+			os.Exit(exitErr.Code)
+		}
+
 		// Errors are stored in pairs: first one is pretty-printed, second one is raw error from go-code.
 		if uw, ok := err.(interface{ Unwrap() []error }); ok {
 			errs := uw.Unwrap()
@@ -43,4 +53,7 @@ func init() {
 	gritCmd.AddCommand(report.ReportCmd)
 	gritCmd.AddCommand(plot.PlotCmd)
 	gritCmd.AddCommand(stat.StatCmd)
+	gritCmd.AddCommand(check.CheckCmd)
+	gritCmd.AddCommand(diff.DiffCmd)
+	gritCmd.AddCommand(history.HistoryCmd)
 }
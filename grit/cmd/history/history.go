@@ -0,0 +1,186 @@
+package history
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vbvictor/grit/grit/cmd/flag"
+	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/coverage"
+	"github.com/vbvictor/grit/pkg/git"
+	"github.com/vbvictor/grit/pkg/history"
+	"github.com/vbvictor/grit/pkg/module"
+	"github.com/vbvictor/grit/pkg/testfiles"
+)
+
+var (
+	excludeRegex string
+	since        string
+	until        string
+)
+
+var historyOpts = history.Options{
+	Samples: history.DefaultSamples,
+	Levels:  []history.Level{history.Repo},
+}
+
+var complexityOpts = &complexity.Options{
+	Engine:       complexity.Gocyclo,
+	ExcludeRegex: nil,
+	Top:          0,
+	OnWarning:    flag.WarnComplexity,
+}
+
+var coverageOpts = &coverage.Options{
+	RunCoverage:  flag.Never,
+	OnUnresolved: flag.WarnUnresolvedCoverage,
+}
+
+var HistoryCmd = &cobra.Command{
+	Use:   "history [flags] <repository>",
+	Short: "Shows how complexity and coverage changed over time",
+	Long: `
+Samples repository at evenly spaced commits or at each tag and calculates complexity of every sample.
+Samples are checked out into temporary git worktrees, the working tree is left untouched.
+Coverage is reported for samples that have a profile in --coverage-profiles directory named after
+the tag or commit hash of the sample, e.g. 'v1.2.0.out' or '4f2a9c1.out'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		path := filepath.Clean(args[0])
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return fmt.Errorf("repository does not exist: %w", err)
+		}
+
+		if err := history.PopulateOpts(&historyOpts, since, until); err != nil {
+			return fmt.Errorf("failed to create options: %w", err)
+		}
+
+		if err := complexity.PopulateOpts(complexityOpts, excludeRegex); err != nil {
+			return fmt.Errorf("failed to create options: %w", err)
+		}
+
+		if err := coverage.PopulateOpts(coverageOpts, excludeRegex); err != nil {
+			return fmt.Errorf("failed to create options: %w", err)
+		}
+
+		complexityOpts.Tests = coverageOpts.Tests
+
+		revisions, err := history.Revisions(path, &historyOpts)
+		if err != nil {
+			return fmt.Errorf("failed to sample revisions: %w", err)
+		}
+
+		flag.LogIfVerbose("Sampling %d revisions\n", len(revisions))
+
+		points := make([]history.Point, 0)
+
+		for _, revision := range revisions {
+			revisionPoints, err := analyzeRevision(path, revision)
+			if err != nil {
+				return err
+			}
+
+			points = append(points, revisionPoints...)
+		}
+
+		return printHistory(points, os.Stdout, historyOpts.OutputFormat)
+	},
+}
+
+func analyzeRevision(path string, revision git.Revision) ([]history.Point, error) {
+	flag.LogIfVerbose("Analyzing %s from %s...\n", revision.Name(), revision.Date.Format(time.DateOnly))
+
+	worktree, err := git.AddWorktree(path, revision.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := worktree.Remove(); err != nil {
+			flag.PrintWarning("%v\n", err)
+		}
+	}()
+
+	complexityStats, err := complexity.RunComplexity(worktree.Path, complexityOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error running complexity analysis at %s: %w", revision.Name(), err)
+	}
+
+	modules, err := module.Discover(worktree.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover modules at %s: %w", revision.Name(), err)
+	}
+
+	coverageOpts.Modules = modules
+
+	var covData []*coverage.FileCoverage
+
+	if historyOpts.ProfilesDir != "" {
+		if profile, ok := history.FindProfile(historyOpts.ProfilesDir, revision); ok {
+			// files of profile belong to the revision checked out in worktree, not to profiles directory
+			profilePath, err := filepath.Abs(filepath.Join(historyOpts.ProfilesDir, profile))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get absolute path of coverage profile: %w", err)
+			}
+
+			if covData, err = coverage.ReadCoverage(worktree.Path, profilePath, coverageOpts); err != nil {
+				return nil, fmt.Errorf("failed to read coverage profile %s: %w", profile, err)
+			}
+		} else {
+			flag.LogIfVerbose("Coverage profile of %s not found\n", revision.Name())
+		}
+	}
+
+	return history.Aggregate(revision, complexityStats, covData, historyOpts.Levels, modules), nil
+}
+
+func init() {
+	flags := HistoryCmd.PersistentFlags()
+
+	// Common flags
+	flag.ExcludeRegexFlag(flags, &excludeRegex)
+	flag.VerboseFlag(flags, &flag.Verbose)
+	flag.TestsFlag(flags, &coverageOpts.Tests, testfiles.Include)
+	flag.OutputFormatFlag(flags, &historyOpts.OutputFormat)
+
+	// Sampling flags
+	flag.SinceFlag(flags, &since)
+	flag.UntilFlag(flags, &until)
+	flags.IntVar(&historyOpts.Samples, flag.LongSamples, history.DefaultSamples,
+		"Number of evenly spaced commits to analyze, first and last commits of the date range are always included")
+	flags.BoolVar(&historyOpts.Tags, flag.LongTags, false,
+		fmt.Sprintf("Analyze every tag created in the date range instead of --%s commits", flag.LongSamples))
+	flags.StringSliceVar(&historyOpts.Levels, flag.LongLevel, []string{history.Repo},
+		fmt.Sprintf("Aggregate metrics on given levels: [%s]", strings.Join(history.AvailableLevels, ", ")))
+
+	// Complexity flags
+	flag.ComplexityEngineFlag(flags, &complexityOpts.Engine)
+	flag.ExternalEngineFlag(flags, &complexityOpts.ExternalEngines)
+	flag.GoFilesFlags(flags, complexityOpts)
+
+	// Coverage flags
+	flags.StringVar(&historyOpts.ProfilesDir, flag.LongProfilesDir, "",
+		"Directory with coverage profiles of analyzed revisions named after tag or commit hash, e.g. 'v1.0.0.out'")
+	flag.UntestedFlag(flags, &coverageOpts.NoUntested)
+
+	HistoryCmd.Flag(flag.LongUntil).DefValue = flag.DefaultUntil
+	HistoryCmd.Flag(flag.LongSince).DefValue = flag.DefaultSince
+}
+
+func printHistory(points []history.Point, out io.Writer, format string) error {
+	switch format {
+	case flag.CSV:
+		history.PrintCSV(points, out)
+	case flag.Tabular:
+		history.PrintTabular(points, out)
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+
+	return nil
+}
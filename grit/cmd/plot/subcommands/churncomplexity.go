@@ -10,7 +10,9 @@ import (
 	"github.com/vbvictor/grit/grit/cmd/flag"
 	"github.com/vbvictor/grit/pkg/complexity"
 	"github.com/vbvictor/grit/pkg/git"
+	"github.com/vbvictor/grit/pkg/module"
 	"github.com/vbvictor/grit/pkg/plot"
+	"github.com/vbvictor/grit/pkg/testfiles"
 )
 
 var (
@@ -19,6 +21,8 @@ var (
 	until        string
 	churnType    git.ChurnType
 	excludeRegex string
+	testsFilter  testfiles.Filter
+	byModule     bool
 )
 
 var churnOpts = &git.ChurnOptions{
@@ -35,6 +39,7 @@ var complexityOpts = &complexity.Options{
 	Engine:       complexity.Gocyclo,
 	ExcludeRegex: nil,
 	Top:          0, //nolint:mnd // default value
+	OnWarning:    flag.WarnComplexity,
 }
 
 var ChurnComplexityCmd = &cobra.Command{
@@ -55,10 +60,24 @@ Open generated file '.html' in a browser to view the graph.`,
 
 		flag.LogIfVerbose("Processing directory: %s\n", path)
 
+		churnOpts.Tests = testsFilter
+		complexityOpts.Tests = testsFilter
+
 		if err := git.PopulateOpts(churnOpts, []string{"go"}, since, until, path, excludeRegex); err != nil {
 			return fmt.Errorf("failed to create options: %w", err)
 		}
 
+		var modules module.Modules
+
+		if byModule {
+			var err error
+			if modules, err = module.Discover(path); err != nil {
+				return fmt.Errorf("failed to discover modules: %w", err)
+			}
+
+			churnOpts.GroupBy = modules.Name
+		}
+
 		flag.LogIfVerbose("Analyzing churn data...\n")
 
 		churns, err := git.ReadGitChurn(path, churnOpts)
@@ -81,6 +100,10 @@ Open generated file '.html' in a browser to view the graph.`,
 
 		flag.LogIfVerbose("Got %d complexity files\n", len(complexityStats))
 
+		if byModule {
+			complexityStats = complexity.RollupByModule(complexityStats, modules)
+		}
+
 		plotEntries := plot.PreparePlotData(complexityStats, churns, churnType)
 
 		if err := plot.CreateScatterChart(plotEntries, plot.NewNoopMapper(), outputFile); err != nil {
@@ -101,6 +124,8 @@ func init() {
 	flag.OutputFlag(flags, &outputFile, "complexity_churn.html")
 	flag.ExcludeRegexFlag(flags, &excludeRegex)
 	flag.ChurnTypeFlag(flags, &churnType, git.Commits)
+	flag.TestsFlag(flags, &testsFilter, testfiles.Include)
+	flag.ByModuleFlag(flags, &byModule)
 
 	// Churn flags
 	flag.SinceFlag(flags, &since)
@@ -108,6 +133,9 @@ func init() {
 
 	// Complexity flags
 	flag.EngineFlag(flags, &complexityOpts.Engine, complexity.Gocyclo)
+	flag.ExternalEngineFlag(flags, &complexityOpts.ExternalEngines)
+	flag.GoFilesFlags(flags, complexityOpts)
+	flag.CSVEngineFlags(flags, complexityOpts)
 
 	ChurnComplexityCmd.Flag(flag.LongUntil).DefValue = flag.DefaultUntil
 	ChurnComplexityCmd.Flag(flag.LongSince).DefValue = flag.DefaultSince
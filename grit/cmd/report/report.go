@@ -10,9 +10,15 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/vbvictor/grit/grit/cmd/flag"
 	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/coupling"
 	"github.com/vbvictor/grit/pkg/coverage"
+	"github.com/vbvictor/grit/pkg/docs"
+	"github.com/vbvictor/grit/pkg/duplication"
 	"github.com/vbvictor/grit/pkg/git"
+	"github.com/vbvictor/grit/pkg/module"
 	"github.com/vbvictor/grit/pkg/report"
+	"github.com/vbvictor/grit/pkg/sarif"
+	"github.com/vbvictor/grit/pkg/testfiles"
 )
 
 var (
@@ -21,6 +27,11 @@ var (
 	since        string
 	until        string
 	outputFormat string
+	testsFilter  testfiles.Filter
+	compareTests bool
+	sarifLevels  []float64
+	byModule     bool
+	extensions   []string
 )
 
 var churnOpts = &git.ChurnOptions{
@@ -37,6 +48,7 @@ var complexityOpts = &complexity.Options{
 	Engine:       complexity.Gocyclo,
 	ExcludeRegex: nil,
 	Top:          0, //nolint:mnd // default value
+	OnWarning:    flag.WarnComplexity,
 }
 
 var coverageOpts = &coverage.Options{
@@ -45,6 +57,12 @@ var coverageOpts = &coverage.Options{
 	ExcludeRegex:     nil,
 	RunCoverage:      flag.Auto,
 	CoverageFilename: "coverage.out",
+	OnUnresolved:     flag.WarnUnresolvedCoverage,
+	OnTestFailure:    flag.WarnTestFailure,
+}
+
+var duplicationOpts = &duplication.Options{
+	MinTokens: duplication.DefaultMinTokens,
 }
 
 var reportOpts = report.Options{
@@ -70,34 +88,56 @@ var ReportCmd = &cobra.Command{
 
 		flag.LogIfVerbose("Processing directory: %s\n", path)
 
-		flag.LogIfVerbose("Analyzing churn data...\n")
-		if err := git.PopulateOpts(churnOpts, []string{"go"}, since, until, path, excludeRegex); err != nil {
-			return fmt.Errorf("failed to create options: %w", err)
+		levels, err := sarif.ParseLevels(sarifLevels)
+		if err != nil {
+			return err
 		}
 
-		churns, err := git.ReadGitChurn(path, churnOpts)
-		if err != nil {
-			return fmt.Errorf("error getting churn metrics: %w", err)
+		// both production and test code are needed to compare them
+		if compareTests && testsFilter.Mode != testfiles.Include {
+			return fmt.Errorf("--%s can't be used with --%s %s", flag.LongCompareTests, flag.LongTests, testsFilter.Mode)
 		}
 
-		if churnOpts.SortBy == git.Commits {
-			for _, churn := range churns {
-				churn.Churn = churn.Commits
-			}
+		churnOpts.Tests = testsFilter
+		complexityOpts.Tests = testsFilter
+		coverageOpts.Tests = testsFilter
+
+		if byModule && reportOpts.ByPackage {
+			return fmt.Errorf("--%s can't be used with --%s", flag.LongByModule, flag.LongByPackage)
 		}
 
-		flag.LogIfVerbose("Got %d churn files\n", len(churns))
+		if (byModule || reportOpts.ByPackage) && compareTests {
+			return fmt.Errorf("--%s and --%s can't be used with --%s",
+				flag.LongByModule, flag.LongByPackage, flag.LongCompareTests)
+		}
 
-		flag.LogIfVerbose("Analyzing complexity data...\n")
-		if err := complexity.PopulateOpts(complexityOpts, excludeRegex); err != nil {
-			return fmt.Errorf("failed to create options: %w", err)
+		if byModule {
+			if coverageOpts.Modules, err = module.Discover(path); err != nil {
+				return fmt.Errorf("failed to discover modules: %w", err)
+			}
+
+			churnOpts.GroupBy = coverageOpts.Modules.Name
+		} else if reportOpts.ByPackage {
+			churnOpts.GroupBy = filepath.Dir
 		}
-		complexityStats, err := complexity.RunComplexity(path, complexityOpts)
+
+		churns, err := collectChurn(path)
 		if err != nil {
-			return fmt.Errorf("error running complexity analysis: %w", err)
+			return err
 		}
 
-		flag.LogIfVerbose("Got %d complexity files\n", len(complexityStats))
+		complexityStats, err := collectComplexity(path)
+		if err != nil {
+			return err
+		}
+
+		if compareTests {
+			comparisons := report.CompareTests(churns, complexityStats, &complexityOpts.Tests)
+			comparisons = report.SortTestComparisons(comparisons, top)
+			flag.LogIfVerbose("Got %d production files with tests\n", len(comparisons))
+
+			return printTestsComparison(comparisons, os.Stdout, outputFormat)
+		}
 
 		flag.LogIfVerbose("Analyzing coverage data...\n")
 		if err := coverage.PopulateOpts(coverageOpts, excludeRegex); err != nil {
@@ -109,14 +149,152 @@ var ReportCmd = &cobra.Command{
 		}
 		flag.LogIfVerbose("Got %d coverage files\n", len(covData))
 
+		if churnOpts.GroupBy != nil {
+			complexityStats = complexity.Rollup(complexityStats, churnOpts.GroupBy)
+			covData = coverage.Rollup(covData, churnOpts.GroupBy)
+		}
+
 		fileScores := report.CombineMetrics(churns, complexityStats, covData)
+
+		if reportOpts.DuplicationFactor != 0 {
+			duplicationData, err := collectDuplication(path)
+			if err != nil {
+				return err
+			}
+
+			if churnOpts.GroupBy != nil {
+				duplicationData = duplication.Rollup(duplicationData, churnOpts.GroupBy)
+			}
+
+			fileScores = report.AddDuplication(fileScores, duplicationData)
+		}
+
+		if reportOpts.DocsFactor != 0 {
+			docsData, err := collectDocs(path)
+			if err != nil {
+				return err
+			}
+
+			if churnOpts.GroupBy != nil {
+				docsData = docs.Rollup(docsData, churnOpts.GroupBy)
+			}
+
+			fileScores = report.AddDocs(fileScores, docsData)
+		}
+
+		if reportOpts.ByPackage {
+			packages, err := collectCoupling(path)
+			if err != nil {
+				return err
+			}
+
+			fileScores = report.AddCoupling(fileScores, packages)
+		}
+
 		fileScores = report.SortAndLimit(report.CalculateScores(fileScores, reportOpts), top)
 		flag.LogIfVerbose("Got %d file scores\n", len(fileScores))
 
-		return printReport(fileScores, os.Stdout, &reportOpts, outputFormat)
+		return printReport(fileScores, os.Stdout, &reportOpts, outputFormat, path, levels)
 	},
 }
 
+func collectChurn(path string) ([]*git.ChurnChunk, error) {
+	flag.LogIfVerbose("Analyzing churn data...\n")
+
+	// Churn of other languages is reported when their coverage reports or complexity engines are used.
+	if len(extensions) == 0 {
+		extensions = []string{"go"}
+	}
+
+	if err := git.PopulateOpts(churnOpts, extensions, since, until, path, excludeRegex); err != nil {
+		return nil, fmt.Errorf("failed to create options: %w", err)
+	}
+
+	churns, err := git.ReadGitChurn(path, churnOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error getting churn metrics: %w", err)
+	}
+
+	if churnOpts.SortBy == git.Commits {
+		for _, churn := range churns {
+			churn.Churn = churn.Commits
+		}
+	}
+
+	flag.LogIfVerbose("Got %d churn files\n", len(churns))
+
+	return churns, nil
+}
+
+func collectComplexity(path string) ([]*complexity.FileStat, error) {
+	flag.LogIfVerbose("Analyzing complexity data...\n")
+
+	if err := complexity.PopulateOpts(complexityOpts, excludeRegex); err != nil {
+		return nil, fmt.Errorf("failed to create options: %w", err)
+	}
+
+	complexityStats, err := complexity.RunComplexity(path, complexityOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error running complexity analysis: %w", err)
+	}
+
+	flag.LogIfVerbose("Got %d complexity files\n", len(complexityStats))
+
+	return complexityStats, nil
+}
+
+func collectDuplication(path string) ([]*duplication.FileDuplication, error) {
+	flag.LogIfVerbose("Analyzing duplication data...\n")
+
+	if duplicationOpts.MinTokens <= 0 {
+		return nil, fmt.Errorf("%w, got %d", duplication.ErrInvalidMinTokens, duplicationOpts.MinTokens)
+	}
+
+	// files are selected by complexity options populated in collectComplexity,
+	// so that duplication, documentation and coupling analyze the files complexity is reported for
+	duplicationOpts.Files = *complexityOpts
+
+	result, err := duplication.Detect(path, duplicationOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error running duplication analysis: %w", err)
+	}
+
+	flag.LogIfVerbose("Got %d duplication files\n", len(result.Files))
+
+	return result.Files, nil
+}
+
+func collectDocs(path string) ([]*docs.FileDocs, error) {
+	flag.LogIfVerbose("Analyzing documentation...\n")
+
+	docsData, err := docs.Analyze(path, &docs.Options{Files: *complexityOpts})
+	if err != nil {
+		return nil, fmt.Errorf("error running documentation analysis: %w", err)
+	}
+
+	flag.LogIfVerbose("Got %d documentation files\n", len(docsData))
+
+	return docsData, nil
+}
+
+func collectCoupling(path string) ([]*coupling.Package, error) {
+	flag.LogIfVerbose("Analyzing package coupling...\n")
+
+	opts := &coupling.Options{Files: *complexityOpts}
+	if opts.Files.Tests.Mode != testfiles.Only {
+		opts.Files.Tests.Mode = testfiles.Exclude
+	}
+
+	packages, err := coupling.Analyze(path, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error running coupling analysis: %w", err)
+	}
+
+	flag.LogIfVerbose("Got %d packages\n", len(packages))
+
+	return packages, nil
+}
+
 func init() {
 	flags := ReportCmd.PersistentFlags()
 
@@ -124,30 +302,68 @@ func init() {
 	flag.ExcludeRegexFlag(flags, &excludeRegex)
 	flag.TopFlag(flags, &top)
 	flag.VerboseFlag(flags, &flag.Verbose)
+	flag.TestsFlag(flags, &testsFilter, testfiles.Include)
+	flag.ByModuleFlag(flags, &byModule)
+	flag.ByPackageFlag(flags, &reportOpts.ByPackage,
+		"Aggregate metrics of package directories instead of files and show package coupling and instability")
 
 	// Churn flags
 	flag.SinceFlag(flags, &since)
 	flag.UntilFlag(flags, &until)
 	flag.ChurnTypeFlag(flags, &churnOpts.SortBy, git.Commits)
+	flag.ExtensionsFlag(flags, &extensions)
 
 	// Complexity flags
 	flag.ComplexityEngineFlag(flags, &complexityOpts.Engine)
+	flag.ExternalEngineFlag(flags, &complexityOpts.ExternalEngines)
+	flag.GoFilesFlags(flags, complexityOpts)
+	flag.CSVEngineFlags(flags, complexityOpts)
 
 	// Coverage flags
 	flag.RunCoverageFlag(flags, &coverageOpts.RunCoverage)
+	flag.TestRunnerFlags(flags, &coverageOpts.Runner)
 	flag.CoverageFilenameFlag(flags, &coverageOpts.CoverageFilename)
+	flag.MergeCoverageFlag(flags, &coverageOpts.Profiles)
+	flag.UntestedFlag(flags, &coverageOpts.NoUntested)
+	flag.CoverageFormatFlag(flags, &coverageOpts.Format, coverage.Formats)
+
+	// Duplication flags
+	flag.DuplicationFactorFlag(flags, &reportOpts.DuplicationFactor)
+	flag.MinTokensFlag(flags, &duplicationOpts.MinTokens)
+
+	// Documentation flags
+	flag.DocsFactorFlag(flags, &reportOpts.DocsFactor)
 
 	// Report specific flags
 	flag.PerfectCoverageFlag(flags, &reportOpts.PerfectCoverage)
-	flag.OutputFormatFlag(flags, &outputFormat)
+	flag.OutputFormatFlag(flags, &outputFormat, flag.SARIF)
+	flag.SARIFLevelsFlag(flags, &sarifLevels, report.DefaultSARIFLevels)
+	flag.CompareTestsFlag(flags, &compareTests)
 }
 
-func printReport(results []*report.FileScore, out io.Writer, opts *report.Options, format string) error {
+func printReport(
+	results []*report.FileScore, out io.Writer, opts *report.Options, format string, path string, levels sarif.Levels,
+) error {
 	switch format {
 	case flag.CSV:
 		report.PrintCSV(results, out, opts)
 	case flag.Tabular:
 		report.PrintTabular(results, out, opts)
+	case flag.SARIF:
+		return report.PrintSARIF(results, out, path, levels)
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+
+	return nil
+}
+
+func printTestsComparison(results []*report.TestComparison, out io.Writer, format string) error {
+	switch format {
+	case flag.CSV:
+		report.PrintTestsCSV(results, out)
+	case flag.Tabular:
+		report.PrintTestsTabular(results, out)
 	default:
 		return fmt.Errorf("unsupported output format: %s", format)
 	}
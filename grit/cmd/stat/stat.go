@@ -14,4 +14,8 @@ func init() {
 	StatCmd.AddCommand(stat.ChurnCmd)
 	StatCmd.AddCommand(stat.ComplexityCmd)
 	StatCmd.AddCommand(stat.CoverageCmd)
+	StatCmd.AddCommand(stat.CrapCmd)
+	StatCmd.AddCommand(stat.DocsCmd)
+	StatCmd.AddCommand(stat.DuplicationCmd)
+	StatCmd.AddCommand(stat.PackagesCmd)
 }
@@ -10,6 +10,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/vbvictor/grit/grit/cmd/flag"
 	"github.com/vbvictor/grit/pkg/git"
+	"github.com/vbvictor/grit/pkg/module"
+	"github.com/vbvictor/grit/pkg/testfiles"
 )
 
 var churnOpts = &git.ChurnOptions{
@@ -28,6 +30,7 @@ var (
 	since             string
 	until             string
 	excludeChurnRegex string
+	churnByModule     bool
 )
 
 var ChurnCmd = &cobra.Command{ //nolint:exhaustruct // no need to set all fields
@@ -46,6 +49,15 @@ var ChurnCmd = &cobra.Command{ //nolint:exhaustruct // no need to set all fields
 			return fmt.Errorf("failed to create options: %w", err)
 		}
 
+		if churnByModule {
+			modules, err := module.Discover(path)
+			if err != nil {
+				return fmt.Errorf("failed to discover modules: %w", err)
+			}
+
+			churnOpts.GroupBy = modules.Name
+		}
+
 		churns, err := git.ReadGitChurn(path, churnOpts)
 		if err != nil {
 			return fmt.Errorf("error getting churn metrics: %w", err)
@@ -69,6 +81,8 @@ func init() {
 	flag.ExtensionsFlag(flags, &extensionList)
 	flag.SinceFlag(flags, &since)
 	flag.UntilFlag(flags, &until)
+	flag.TestsFlag(flags, &churnOpts.Tests, testfiles.Include)
+	flag.ByModuleFlag(flags, &churnByModule)
 
 	ChurnCmd.Flag(flag.LongUntil).DefValue = flag.DefaultUntil
 	ChurnCmd.Flag(flag.LongSince).DefValue = flag.DefaultSince
@@ -5,20 +5,40 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/vbvictor/grit/grit/cmd/flag"
 	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/coverage"
+	"github.com/vbvictor/grit/pkg/module"
+	"github.com/vbvictor/grit/pkg/sarif"
+	"github.com/vbvictor/grit/pkg/testfiles"
 )
 
 var complexityOpts = complexity.Options{
-	Engine:       complexity.Gocyclo,
-	ExcludeRegex: nil,
-	Top:          10, //nolint:mnd // default value
-	OutputFormat: "",
+	Engine:        complexity.Gocyclo,
+	ExcludeRegex:  nil,
+	Top:           10, //nolint:mnd // default value
+	OutputFormat:  "",
+	PerFunction:   false,
+	MinComplexity: 0,
+	OnWarning:     flag.WarnComplexity,
 }
 
-var excludeComplexityRegex string
+var complexityCoverageOpts = &coverage.Options{
+	RunCoverage:      flag.Auto,
+	CoverageFilename: "coverage.out",
+	OnUnresolved:     flag.WarnUnresolvedCoverage,
+	OnTestFailure:    flag.WarnTestFailure,
+}
+
+var (
+	excludeComplexityRegex string
+	complexitySARIFLevels  []float64
+	complexityByModule     bool
+	complexityWithCoverage bool
+)
 
 var ComplexityCmd = &cobra.Command{ //nolint:exhaustruct // no need to set all fields
 	Use:   "complexity [flags] <path>",
@@ -36,11 +56,51 @@ var ComplexityCmd = &cobra.Command{ //nolint:exhaustruct // no need to set all f
 			return fmt.Errorf("failed to create options: %w", err)
 		}
 
+		levels, err := sarif.ParseLevels(complexitySARIFLevels)
+		if err != nil {
+			return err
+		}
+
+		// metrics other than complexity and coverage are known only for functions
+		if complexityOpts.SortBy != complexity.ByComplexity || complexityWithCoverage {
+			complexityOpts.PerFunction = true
+		}
+
+		if complexityByModule && (complexityOpts.PerFunction || complexityOpts.OutputFormat == flag.SARIF) {
+			return fmt.Errorf("--%s reports modules and can't be used with --%s or %s format",
+				flag.LongByModule, flag.LongPerFunction, flag.SARIF)
+		}
+
 		fileStat, err := complexity.RunComplexity(path, &complexityOpts)
 		if err != nil {
 			return fmt.Errorf("error running complexity analysis: %w", err)
 		}
 
+		if complexityByModule {
+			modules, err := module.Discover(path)
+			if err != nil {
+				return fmt.Errorf("failed to discover modules: %w", err)
+			}
+
+			fileStat = complexity.RollupByModule(fileStat, modules)
+		}
+
+		if complexityOpts.OutputFormat == flag.SARIF {
+			return complexity.PrintSARIF(fileStat, os.Stdout, path, &complexityOpts, levels)
+		}
+
+		if complexityOpts.PerFunction {
+			if complexityWithCoverage {
+				if err := attachFunctionCoverage(path, fileStat); err != nil {
+					return err
+				}
+			}
+
+			functions := complexity.TopFunctions(fileStat, complexityOpts)
+
+			return printFunctionStats(functions, os.Stdout, &complexityOpts)
+		}
+
 		fileStat = complexity.SortAndLimit(fileStat, complexityOpts)
 
 		return printComplexityStats(fileStat, os.Stdout, &complexityOpts)
@@ -51,10 +111,47 @@ func init() {
 	flags := ComplexityCmd.PersistentFlags()
 
 	flag.ComplexityEngineFlag(flags, &complexityOpts.Engine)
+	flag.ExternalEngineFlag(flags, &complexityOpts.ExternalEngines)
+	flag.GoFilesFlags(flags, &complexityOpts)
+	flag.CSVEngineFlags(flags, &complexityOpts)
+	flag.TestsFlag(flags, &complexityOpts.Tests, testfiles.Include)
 	flag.TopFlag(flags, &complexityOpts.Top)
 	flag.VerboseFlag(flags, &flag.Verbose)
 	flag.ExcludeRegexFlag(flags, &excludeComplexityRegex)
-	flag.OutputFormatFlag(flags, &complexityOpts.OutputFormat)
+	flag.OutputFormatFlag(flags, &complexityOpts.OutputFormat, flag.SARIF)
+	flag.SARIFLevelsFlag(flags, &complexitySARIFLevels, complexity.DefaultSARIFLevels)
+	flag.PerFunctionFlag(flags, &complexityOpts.PerFunction, "List the most complex functions instead of files")
+	flag.MinComplexityFlag(flags, &complexityOpts.MinComplexity)
+	flag.SortFlag(flags, &complexityOpts.SortBy, complexity.ByComplexity,
+		fmt.Sprintf("Specify metric functions are sorted by: [%s], metrics other than complexity imply --%s",
+			strings.Join(complexity.AvailableSorts, ", "), flag.LongPerFunction))
+	flag.ByModuleFlag(flags, &complexityByModule)
+	flag.WithCoverageFlag(flags, &complexityWithCoverage,
+		fmt.Sprintf("Add coverage column to listed functions, implies --%s", flag.LongPerFunction))
+	flag.RunCoverageFlag(flags, &complexityCoverageOpts.RunCoverage)
+	flag.TestRunnerFlags(flags, &complexityCoverageOpts.Runner)
+	flag.CoverageFilenameFlag(flags, &complexityCoverageOpts.CoverageFilename)
+	flag.MergeCoverageFlag(flags, &complexityCoverageOpts.Profiles)
+	flag.UntestedFlag(flags, &complexityCoverageOpts.NoUntested)
+	flag.CoverageFormatFlag(flags, &complexityCoverageOpts.Format, coverage.Formats)
+}
+
+// attachFunctionCoverage joins coverage of functions of the same files that complexity was calculated for.
+func attachFunctionCoverage(path string, files []*complexity.FileStat) error {
+	complexityCoverageOpts.Tests = complexityOpts.Tests
+
+	if err := coverage.PopulateOpts(complexityCoverageOpts, excludeComplexityRegex); err != nil {
+		return fmt.Errorf("failed to create options: %w", err)
+	}
+
+	functions, err := coverage.GetFunctionCoverageData(path, complexityCoverageOpts)
+	if err != nil {
+		return fmt.Errorf("failed to get coverage data: %w", err)
+	}
+
+	coverage.AttachFunctionCoverage(files, functions)
+
+	return nil
 }
 
 func printComplexityStats(results []*complexity.FileStat, out io.Writer, opts *complexity.Options) error {
@@ -69,3 +166,16 @@ func printComplexityStats(results []*complexity.FileStat, out io.Writer, opts *c
 
 	return nil
 }
+
+func printFunctionStats(results []complexity.FunctionStat, out io.Writer, opts *complexity.Options) error {
+	switch opts.OutputFormat {
+	case flag.CSV:
+		complexity.PrintFunctionsCSV(results, out)
+	case flag.Tabular:
+		complexity.PrintFunctionsTabular(results, out)
+	default:
+		return fmt.Errorf("unsupported output format: %s", opts.OutputFormat)
+	}
+
+	return nil
+}
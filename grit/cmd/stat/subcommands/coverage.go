@@ -5,10 +5,13 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/vbvictor/grit/grit/cmd/flag"
 	"github.com/vbvictor/grit/pkg/coverage"
+	"github.com/vbvictor/grit/pkg/module"
+	"github.com/vbvictor/grit/pkg/testfiles"
 )
 
 var coverageOpts = &coverage.Options{
@@ -18,9 +21,14 @@ var coverageOpts = &coverage.Options{
 	RunCoverage:      "",
 	CoverageFilename: "coverage.out",
 	OutputFormat:     "",
+	OnUnresolved:     flag.WarnUnresolvedCoverage,
+	OnTestFailure:    flag.WarnTestFailure,
 }
 
-var excludeCoverageRegex string
+var (
+	excludeCoverageRegex string
+	coverageByModule     bool
+)
 
 var CoverageCmd = &cobra.Command{ //nolint:exhaustruct // no need to set all fields
 	Use:   "coverage [flags] <path>",
@@ -38,11 +46,43 @@ var CoverageCmd = &cobra.Command{ //nolint:exhaustruct // no need to set all fie
 			return fmt.Errorf("failed to create options: %w", err)
 		}
 
+		if coverageOpts.OutputFormat == flag.LCOV || coverageOpts.OutputFormat == flag.Cobertura {
+			return exportCoverage(path, os.Stdout, coverageOpts)
+		}
+
+		if coverageOpts.PerFunction {
+			if coverageByModule {
+				return fmt.Errorf("--%s can't be used with --%s", flag.LongByModule, flag.LongPerFunction)
+			}
+
+			functions, err := coverage.GetFunctionCoverageData(path, coverageOpts)
+			if err != nil {
+				return fmt.Errorf("failed to get coverage data: %w", err)
+			}
+
+			functions = coverage.SortFunctions(functions, coverageOpts.SortBy, coverageOpts.Top)
+
+			return printFunctionCoverage(functions, os.Stdout, coverageOpts)
+		}
+
+		if coverageByModule {
+			modules, err := module.Discover(path)
+			if err != nil {
+				return fmt.Errorf("failed to discover modules: %w", err)
+			}
+
+			coverageOpts.Modules = modules
+		}
+
 		covData, err := coverage.GetCoverageData(path, coverageOpts)
 		if err != nil {
 			return fmt.Errorf("failed to get coverage data: %w", err)
 		}
 
+		if coverageByModule {
+			covData = coverage.RollupByModule(covData, coverageOpts.Modules)
+		}
+
 		covData = coverage.SortAndLimit(covData, coverageOpts.SortBy, coverageOpts.Top)
 
 		return printCoverageStats(covData, os.Stdout, coverageOpts)
@@ -55,11 +95,44 @@ func init() {
 	flag.SortFlag(flags, &coverageOpts.SortBy, coverage.Worst,
 		fmt.Sprintf("Specify sort type: [%s, %s]", coverage.Worst, coverage.Best))
 	flag.RunCoverageFlag(flags, &coverageOpts.RunCoverage)
+	flag.TestRunnerFlags(flags, &coverageOpts.Runner)
 	flag.CoverageFilenameFlag(flags, &coverageOpts.CoverageFilename)
+	flag.MergeCoverageFlag(flags, &coverageOpts.Profiles)
+	flag.UntestedFlag(flags, &coverageOpts.NoUntested)
+	flag.CoverageFormatFlag(flags, &coverageOpts.Format, coverage.Formats)
 	flag.VerboseFlag(flags, &flag.Verbose)
 	flag.TopFlag(flags, &coverageOpts.Top)
 	flag.ExcludeRegexFlag(flags, &excludeCoverageRegex)
-	flag.OutputFormatFlag(flags, &coverageOpts.OutputFormat)
+	flag.OutputFormatFlag(flags, &coverageOpts.OutputFormat, flag.LCOV, flag.Cobertura)
+	flag.TestsFlag(flags, &coverageOpts.Tests, testfiles.Include)
+	flag.ByModuleFlag(flags, &coverageByModule)
+	flag.PerFunctionFlag(flags, &coverageOpts.PerFunction, "List the least covered functions instead of files")
+}
+
+// exportCoverage converts line coverage of every file to another format, files aren't sorted nor limited.
+func exportCoverage(path string, out io.Writer, opts *coverage.Options) error {
+	if coverageByModule || opts.PerFunction {
+		return fmt.Errorf("--%s and --%s can't be used with --%s %s",
+			flag.LongByModule, flag.LongPerFunction, flag.LongFormat, opts.OutputFormat)
+	}
+
+	profiles, err := coverage.GetProfiles(path, opts)
+	if err != nil {
+		return fmt.Errorf("failed to get coverage data: %w", err)
+	}
+
+	if opts.OutputFormat == flag.LCOV {
+		coverage.PrintLCOV(profiles, out)
+
+		return nil
+	}
+
+	source, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path of repository: %w", err)
+	}
+
+	return coverage.PrintCobertura(profiles, source, time.Now(), out)
 }
 
 func printCoverageStats(results []*coverage.FileCoverage, out io.Writer, opts *coverage.Options) error {
@@ -74,3 +147,16 @@ func printCoverageStats(results []*coverage.FileCoverage, out io.Writer, opts *c
 
 	return nil
 }
+
+func printFunctionCoverage(results []*coverage.FunctionCoverage, out io.Writer, opts *coverage.Options) error {
+	switch opts.OutputFormat {
+	case flag.CSV:
+		coverage.PrintFunctionsCSV(results, out)
+	case flag.Tabular:
+		coverage.PrintFunctionsTabular(results, out)
+	default:
+		return fmt.Errorf("unsupported output format: %s", opts.OutputFormat)
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package stat
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/vbvictor/grit/grit/cmd/flag"
+	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/coverage"
+	"github.com/vbvictor/grit/pkg/crap"
+	"github.com/vbvictor/grit/pkg/testfiles"
+)
+
+var crapOpts = crap.Options{
+	Threshold:    crap.DefaultThreshold,
+	MaxCrappy:    0,
+	Top:          10, //nolint:mnd // default value
+	OutputFormat: "",
+}
+
+var crapComplexityOpts = &complexity.Options{
+	Engine:    complexity.Gocyclo,
+	OnWarning: flag.WarnComplexity,
+}
+
+var crapCoverageOpts = &coverage.Options{
+	RunCoverage:      flag.Auto,
+	CoverageFilename: "coverage.out",
+	OnUnresolved:     flag.WarnUnresolvedCoverage,
+	OnTestFailure:    flag.WarnTestFailure,
+}
+
+var (
+	excludeCrapRegex string
+	failOnCrap       bool
+)
+
+var CrapCmd = &cobra.Command{ //nolint:exhaustruct // no need to set all fields
+	Use:   "crap [flags] <path>",
+	Short: "Finds functions with the highest CRAP score",
+	Long: `
+Finds functions with the highest CRAP (Change Risk Anti-Patterns) score combining cyclomatic complexity
+and coverage of functions: complexity^2 * (1 - coverage)^3 + complexity.
+Functions with score above --crap-threshold are considered crappy. With --fail grit exits with code 2
+when the percentage of crappy functions exceeds --max-crappy.`,
+	Args:          cobra.ExactArgs(1),
+	SilenceErrors: true,
+	RunE: func(_ *cobra.Command, args []string) error {
+		path := filepath.Clean(args[0])
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return fmt.Errorf("repository does not exist: %w", err)
+		}
+
+		flag.LogIfVerbose("Processing repository: %s\n", path)
+
+		if err := crap.PopulateOpts(&crapOpts); err != nil {
+			return fmt.Errorf("failed to create options: %w", err)
+		}
+
+		crapCoverageOpts.Tests = crapComplexityOpts.Tests
+
+		if err := complexity.PopulateOpts(crapComplexityOpts, excludeCrapRegex); err != nil {
+			return fmt.Errorf("failed to create options: %w", err)
+		}
+
+		if err := coverage.PopulateOpts(crapCoverageOpts, excludeCrapRegex); err != nil {
+			return fmt.Errorf("failed to create options: %w", err)
+		}
+
+		complexityStats, err := complexity.RunComplexity(path, crapComplexityOpts)
+		if err != nil {
+			return fmt.Errorf("error running complexity analysis: %w", err)
+		}
+
+		functions, err := coverage.GetFunctionCoverageData(path, crapCoverageOpts)
+		if err != nil {
+			return fmt.Errorf("failed to get coverage data: %w", err)
+		}
+
+		scores := crap.Combine(complexityStats, functions)
+		summary := crap.Summarize(scores, crapOpts.Threshold)
+		flag.LogIfVerbose("Got %d scored functions\n", len(scores))
+
+		if err := printCrap(crap.SortAndLimit(scores, crapOpts.Top), summary, os.Stdout, &crapOpts); err != nil {
+			return err
+		}
+
+		if failOnCrap && summary.Percentage > crapOpts.MaxCrappy {
+			return &flag.ExitError{
+				Code: flag.ExitViolations,
+				Err: fmt.Errorf("CRAP check failed: %.2f%% of functions are crappy, at most %.2f%% allowed",
+					summary.Percentage, crapOpts.MaxCrappy),
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	flags := CrapCmd.PersistentFlags()
+
+	flag.CRAPThresholdFlag(flags, &crapOpts.Threshold, crap.DefaultThreshold)
+	flag.MaxCrappyFlag(flags, &crapOpts.MaxCrappy)
+	flag.FailFlag(flags, &failOnCrap,
+		fmt.Sprintf("when percentage of crappy functions exceeds --%s", flag.LongMaxCrappy))
+	flag.GoFilesFlags(flags, crapComplexityOpts)
+	flag.RunCoverageFlag(flags, &crapCoverageOpts.RunCoverage)
+	flag.TestRunnerFlags(flags, &crapCoverageOpts.Runner)
+	flag.CoverageFilenameFlag(flags, &crapCoverageOpts.CoverageFilename)
+	flag.MergeCoverageFlag(flags, &crapCoverageOpts.Profiles)
+	flag.UntestedFlag(flags, &crapCoverageOpts.NoUntested)
+	flag.CoverageFormatFlag(flags, &crapCoverageOpts.Format, coverage.Formats)
+	flag.TopFlag(flags, &crapOpts.Top)
+	flag.VerboseFlag(flags, &flag.Verbose)
+	flag.ExcludeRegexFlag(flags, &excludeCrapRegex)
+	flag.OutputFormatFlag(flags, &crapOpts.OutputFormat)
+	// functions of Go files without coverage are scored as not covered, tests aren't covered by themselves
+	flag.TestsFlag(flags, &crapComplexityOpts.Tests, testfiles.Exclude)
+}
+
+func printCrap(results []*crap.Function, summary crap.Summary, out io.Writer, opts *crap.Options) error {
+	switch opts.OutputFormat {
+	case flag.CSV:
+		crap.PrintCSV(results, out)
+	case flag.Tabular:
+		crap.PrintTabular(results, summary, out)
+	default:
+		return fmt.Errorf("unsupported output format: %s", opts.OutputFormat)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package stat
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vbvictor/grit/grit/cmd/flag"
+	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/docs"
+	"github.com/vbvictor/grit/pkg/module"
+)
+
+var docsOpts = docs.Options{
+	SortBy:       docs.Worst,
+	Top:          10, //nolint:mnd // default value
+	OutputFormat: "",
+	Files: complexity.Options{
+		OnWarning: flag.WarnComplexity,
+	},
+}
+
+var (
+	excludeDocsRegex string
+	docsByPackage    bool
+	docsByModule     bool
+)
+
+var DocsCmd = &cobra.Command{ //nolint:exhaustruct // no need to set all fields
+	Use:   "docs [flags] <path>",
+	Short: "Finds files with the least documented exported identifiers",
+	Long: `Computes share of exported functions, methods, types and constants that have doc comments
+and density of comment lines among non-blank lines. Test files are not analyzed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		path := filepath.Clean(args[0])
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return fmt.Errorf("repository does not exist: %w", err)
+		}
+
+		flag.LogIfVerbose("Processing repository: %s\n", path)
+
+		if docsByPackage && docsByModule {
+			return fmt.Errorf("--%s can't be used with --%s", flag.LongByPackage, flag.LongByModule)
+		}
+
+		if err := docs.PopulateOpts(&docsOpts, excludeDocsRegex); err != nil {
+			return fmt.Errorf("failed to create options: %w", err)
+		}
+
+		results, err := docs.Analyze(path, &docsOpts)
+		if err != nil {
+			return fmt.Errorf("error running documentation analysis: %w", err)
+		}
+
+		switch {
+		case docsByPackage:
+			results = docs.Rollup(results, filepath.Dir)
+		case docsByModule:
+			modules, err := module.Discover(path)
+			if err != nil {
+				return fmt.Errorf("failed to discover modules: %w", err)
+			}
+
+			results = docs.RollupByModule(results, modules)
+		}
+
+		results = docs.SortAndLimit(results, docsOpts.SortBy, docsOpts.Top)
+
+		return printDocsStats(results, os.Stdout, &docsOpts)
+	},
+}
+
+func init() {
+	flags := DocsCmd.PersistentFlags()
+
+	flag.SortFlag(flags, &docsOpts.SortBy, docs.Worst,
+		fmt.Sprintf("Specify sort type: [%s]", strings.Join(docs.AvailableSorts, ", ")))
+	flag.GoFilesFlags(flags, &docsOpts.Files)
+	flag.TopFlag(flags, &docsOpts.Top)
+	flag.VerboseFlag(flags, &flag.Verbose)
+	flag.ExcludeRegexFlag(flags, &excludeDocsRegex)
+	flag.OutputFormatFlag(flags, &docsOpts.OutputFormat)
+	flag.ByPackageFlag(flags, &docsByPackage, "Aggregate metrics of package directories instead of files")
+	flag.ByModuleFlag(flags, &docsByModule)
+}
+
+func printDocsStats(results []*docs.FileDocs, out io.Writer, opts *docs.Options) error {
+	switch opts.OutputFormat {
+	case flag.CSV:
+		docs.PrintCSV(results, out)
+	case flag.Tabular:
+		docs.PrintTabular(results, out)
+	default:
+		return fmt.Errorf("unsupported output format: %s", opts.OutputFormat)
+	}
+
+	return nil
+}
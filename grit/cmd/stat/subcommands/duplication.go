@@ -0,0 +1,98 @@
+package stat
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/vbvictor/grit/grit/cmd/flag"
+	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/duplication"
+	"github.com/vbvictor/grit/pkg/testfiles"
+)
+
+var duplicationOpts = duplication.Options{
+	MinTokens:    duplication.DefaultMinTokens,
+	Top:          10, //nolint:mnd // default value
+	OutputFormat: "",
+	Clones:       false,
+	Files: complexity.Options{
+		OnWarning: flag.WarnComplexity,
+	},
+}
+
+var excludeDuplicationRegex string
+
+var DuplicationCmd = &cobra.Command{ //nolint:exhaustruct // no need to set all fields
+	Use:   "duplication [flags] <path>",
+	Short: "Finds files with the most duplicated code",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		path := filepath.Clean(args[0])
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return fmt.Errorf("repository does not exist: %w", err)
+		}
+
+		flag.LogIfVerbose("Processing repository: %s\n", path)
+
+		if err := duplication.PopulateOpts(&duplicationOpts, excludeDuplicationRegex); err != nil {
+			return fmt.Errorf("failed to create options: %w", err)
+		}
+
+		result, err := duplication.Detect(path, &duplicationOpts)
+		if err != nil {
+			return fmt.Errorf("error running duplication analysis: %w", err)
+		}
+
+		flag.LogIfVerbose("Found %d clones in %d files\n", len(result.Clones), len(result.Files))
+
+		if duplicationOpts.Clones {
+			return printClones(duplication.TopClones(result.Clones, duplicationOpts.Top), os.Stdout, &duplicationOpts)
+		}
+
+		files := duplication.SortAndLimit(result.Files, duplicationOpts.Top)
+
+		return printDuplicationStats(files, os.Stdout, &duplicationOpts)
+	},
+}
+
+func init() {
+	flags := DuplicationCmd.PersistentFlags()
+
+	flag.MinTokensFlag(flags, &duplicationOpts.MinTokens)
+	flag.ClonesFlag(flags, &duplicationOpts.Clones)
+	flag.GoFilesFlags(flags, &duplicationOpts.Files)
+	flag.TestsFlag(flags, &duplicationOpts.Files.Tests, testfiles.Include)
+	flag.TopFlag(flags, &duplicationOpts.Top)
+	flag.VerboseFlag(flags, &flag.Verbose)
+	flag.ExcludeRegexFlag(flags, &excludeDuplicationRegex)
+	flag.OutputFormatFlag(flags, &duplicationOpts.OutputFormat)
+}
+
+func printDuplicationStats(results []*duplication.FileDuplication, out io.Writer, opts *duplication.Options) error {
+	switch opts.OutputFormat {
+	case flag.CSV:
+		duplication.PrintCSV(results, out)
+	case flag.Tabular:
+		duplication.PrintTabular(results, out)
+	default:
+		return fmt.Errorf("unsupported output format: %s", opts.OutputFormat)
+	}
+
+	return nil
+}
+
+func printClones(results []duplication.Clone, out io.Writer, opts *duplication.Options) error {
+	switch opts.OutputFormat {
+	case flag.CSV:
+		duplication.PrintClonesCSV(results, out)
+	case flag.Tabular:
+		duplication.PrintClonesTabular(results, out)
+	default:
+		return fmt.Errorf("unsupported output format: %s", opts.OutputFormat)
+	}
+
+	return nil
+}
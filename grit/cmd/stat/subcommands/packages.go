@@ -0,0 +1,83 @@
+package stat
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vbvictor/grit/grit/cmd/flag"
+	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/coupling"
+	"github.com/vbvictor/grit/pkg/testfiles"
+)
+
+var packagesOpts = coupling.Options{
+	SortBy:       coupling.Distance,
+	Top:          10, //nolint:mnd // default value
+	OutputFormat: "",
+	Files: complexity.Options{
+		OnWarning: flag.WarnComplexity,
+	},
+}
+
+var excludePackagesRegex string
+
+var PackagesCmd = &cobra.Command{ //nolint:exhaustruct // no need to set all fields
+	Use:   "packages [flags] <path>",
+	Short: "Finds packages the furthest from the main sequence of instability and abstractness",
+	Long: `Computes coupling metrics of packages from imports of Go files:
+afferent coupling (packages importing the package), efferent coupling (packages imported by the package),
+instability Ce / (Ca + Ce), abstractness (share of interfaces among declared types)
+and distance from the main sequence |A + I - 1|. Only packages of the repository are counted as dependencies.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		path := filepath.Clean(args[0])
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return fmt.Errorf("repository does not exist: %w", err)
+		}
+
+		flag.LogIfVerbose("Processing repository: %s\n", path)
+
+		if err := coupling.PopulateOpts(&packagesOpts, excludePackagesRegex); err != nil {
+			return fmt.Errorf("failed to create options: %w", err)
+		}
+
+		packages, err := coupling.Analyze(path, &packagesOpts)
+		if err != nil {
+			return fmt.Errorf("error running coupling analysis: %w", err)
+		}
+
+		packages = coupling.SortAndLimit(packages, packagesOpts.SortBy, packagesOpts.Top)
+
+		return printPackagesStats(packages, os.Stdout, &packagesOpts)
+	},
+}
+
+func init() {
+	flags := PackagesCmd.PersistentFlags()
+
+	flag.SortFlag(flags, &packagesOpts.SortBy, coupling.Distance,
+		fmt.Sprintf("Specify sort type: [%s]", strings.Join(coupling.AvailableSorts, ", ")))
+	flag.GoFilesFlags(flags, &packagesOpts.Files)
+	flag.TestsFlag(flags, &packagesOpts.Files.Tests, testfiles.Exclude)
+	flag.TopFlag(flags, &packagesOpts.Top)
+	flag.VerboseFlag(flags, &flag.Verbose)
+	flag.ExcludeRegexFlag(flags, &excludePackagesRegex)
+	flag.OutputFormatFlag(flags, &packagesOpts.OutputFormat)
+}
+
+func printPackagesStats(results []*coupling.Package, out io.Writer, opts *coupling.Options) error {
+	switch opts.OutputFormat {
+	case flag.CSV:
+		coupling.PrintCSV(results, out)
+	case flag.Tabular:
+		coupling.PrintTabular(results, out)
+	default:
+		return fmt.Errorf("unsupported output format: %s", opts.OutputFormat)
+	}
+
+	return nil
+}
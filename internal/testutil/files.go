@@ -0,0 +1,27 @@
+// Package testutil provides fixtures shared by tests of grit packages.
+package testutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// WriteFile writes content to path creating missing parent directories.
+func WriteFile(t testing.TB, path, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+// WriteFiles writes files given by slash-separated paths relative to root.
+func WriteFiles(t testing.TB, root string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		WriteFile(t, filepath.Join(root, filepath.FromSlash(name)), content)
+	}
+}
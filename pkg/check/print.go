@@ -0,0 +1,48 @@
+package check
+
+import (
+	"fmt"
+	"io"
+)
+
+func (v Violation) String() string {
+	location := v.File
+	if v.Line > 0 {
+		location = fmt.Sprintf("%s:%d", v.File, v.Line)
+	}
+
+	switch v.Rule {
+	case FunctionComplexity:
+		violation := fmt.Sprintf("%s: %s %s complexity %.0f > %.0f (%s)",
+			v.Rule, location, v.Function, v.Value, v.Threshold, v.Engine)
+		if v.Coverage != nil {
+			violation += fmt.Sprintf(", coverage %.2f%%", *v.Coverage)
+		}
+
+		return violation
+	case FileComplexity:
+		return fmt.Sprintf("%s: %s average complexity %.2f > %.2f (%s)",
+			v.Rule, location, v.Value, v.Threshold, v.Engine)
+	case Coverage:
+		return fmt.Sprintf("%s: %s coverage %.2f%% < %.2f%%", v.Rule, location, v.Value, v.Threshold)
+	case TotalCoverage:
+		return fmt.Sprintf("%s: coverage of all files %.2f%% < %.2f%%", v.Rule, v.Value, v.Threshold)
+	default:
+		return fmt.Sprintf("%s: %s %.2f > %.2f", v.Rule, location, v.Value, v.Threshold)
+	}
+}
+
+// PrintViolations prints one violation per line followed by a summary.
+func PrintViolations(violations []Violation, out io.Writer) {
+	if len(violations) == 0 {
+		_, _ = fmt.Fprintln(out, "All quality checks passed")
+
+		return
+	}
+
+	for _, violation := range violations {
+		_, _ = fmt.Fprintln(out, violation.String())
+	}
+
+	_, _ = fmt.Fprintf(out, "\n%d quality check violation(s) found\n", len(violations))
+}
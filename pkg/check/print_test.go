@@ -0,0 +1,44 @@
+package check
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintViolations(t *testing.T) {
+	var buf bytes.Buffer
+
+	coverage := 25.0
+
+	PrintViolations([]Violation{
+		{Rule: FunctionComplexity, Engine: "gocyclo", File: "run.go", Line: 10, Function: "Run", Value: 12, Threshold: 10},
+		{Rule: FileComplexity, Engine: "gocyclo", File: "run.go", Value: 7.5, Threshold: 5},
+		{Rule: Score, File: "run.go", Value: 120, Threshold: 100},
+		{Rule: Coverage, File: "run.go", Value: 40, Threshold: 60},
+		{Rule: TotalCoverage, Value: 55.5, Threshold: 60},
+		{
+			Rule: FunctionComplexity, Engine: "gocyclo", File: "run.go", Line: 40, Function: "parse", Value: 11,
+			Threshold: 10, Coverage: &coverage,
+		},
+	}, &buf)
+
+	assert.Equal(t, `function-complexity: run.go:10 Run complexity 12 > 10 (gocyclo)
+file-complexity: run.go average complexity 7.50 > 5.00 (gocyclo)
+score: run.go 120.00 > 100.00
+coverage: run.go coverage 40.00% < 60.00%
+total-coverage: coverage of all files 55.50% < 60.00%
+function-complexity: run.go:40 parse complexity 11 > 10 (gocyclo), coverage 25.00%
+
+6 quality check violation(s) found
+`, buf.String())
+}
+
+func TestPrintNoViolations(t *testing.T) {
+	var buf bytes.Buffer
+
+	PrintViolations(nil, &buf)
+
+	assert.Equal(t, "All quality checks passed\n", buf.String())
+}
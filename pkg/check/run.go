@@ -0,0 +1,187 @@
+package check
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/coverage"
+	"github.com/vbvictor/grit/pkg/report"
+)
+
+// Rule identifies threshold that was exceeded.
+type Rule = string
+
+const (
+	FunctionComplexity Rule = "function-complexity"
+	FileComplexity     Rule = "file-complexity"
+	Score              Rule = "score"
+	Coverage           Rule = "coverage"
+	TotalCoverage      Rule = "total-coverage"
+)
+
+const percentMultiplier = 100.0
+
+// Thresholds configure quality gate, zero value disables a threshold.
+type Thresholds struct {
+	// Maximum function complexity per complexity engine.
+	MaxFunctionComplexity map[complexity.Engine]int
+	MaxFileComplexity     float64
+	MaxScore              float64
+	MinCoverage           float64
+	// Minimum coverage of all statements of the repository.
+	MinTotalCoverage float64
+}
+
+// NeedsComplexity reports whether file aggregate complexity is required by thresholds.
+func (t *Thresholds) NeedsComplexity() bool {
+	return t.MaxFileComplexity > 0 || t.MaxScore > 0
+}
+
+// NeedsCoverage reports whether coverage data is required by thresholds.
+func (t *Thresholds) NeedsCoverage() bool {
+	return t.MinCoverage > 0 || t.MinTotalCoverage > 0 || t.MaxScore > 0
+}
+
+// NeedsChurn reports whether churn data is required by thresholds.
+func (t *Thresholds) NeedsChurn() bool {
+	return t.MaxScore > 0
+}
+
+// Empty reports whether no threshold is set.
+func (t *Thresholds) Empty() bool {
+	return len(t.MaxFunctionComplexity) == 0 && t.MaxFileComplexity == 0 && t.MaxScore == 0 && t.MinCoverage == 0 &&
+		t.MinTotalCoverage == 0
+}
+
+// Violation describes single exceeded threshold.
+type Violation struct {
+	Rule      Rule
+	Engine    complexity.Engine
+	File      string
+	Line      int
+	Function  string
+	Value     float64
+	Threshold float64
+	// Coverage of the function, nil when it's unknown.
+	Coverage *float64
+}
+
+// CheckFunctions reports functions with complexity above maxComplexity calculated by engine.
+func CheckFunctions(files []*complexity.FileStat, engine complexity.Engine, maxComplexity int) []Violation {
+	violations := make([]Violation, 0)
+
+	for _, file := range files {
+		for _, fn := range file.Functions {
+			if fn.Complexity > maxComplexity {
+				violations = append(violations, Violation{
+					Rule:      FunctionComplexity,
+					Engine:    engine,
+					File:      file.Path,
+					Line:      fn.Line,
+					Function:  fn.Name,
+					Value:     float64(fn.Complexity),
+					Threshold: float64(maxComplexity),
+					Coverage:  fn.Coverage,
+				})
+			}
+		}
+	}
+
+	return violations
+}
+
+// CheckFiles reports files with average complexity above maxComplexity.
+func CheckFiles(files []*complexity.FileStat, engine complexity.Engine, maxComplexity float64) []Violation {
+	violations := make([]Violation, 0)
+
+	for _, file := range files {
+		if file.AvgComplexity > maxComplexity {
+			violations = append(violations, Violation{
+				Rule:      FileComplexity,
+				Engine:    engine,
+				File:      file.Path,
+				Value:     file.AvgComplexity,
+				Threshold: maxComplexity,
+			})
+		}
+	}
+
+	return violations
+}
+
+// CheckScores reports files with maintainability score above maxScore.
+func CheckScores(scores []*report.FileScore, maxScore float64) []Violation {
+	violations := make([]Violation, 0)
+
+	for _, score := range scores {
+		if score.Score > maxScore {
+			violations = append(violations, Violation{
+				Rule:      Score,
+				File:      score.File,
+				Value:     score.Score,
+				Threshold: maxScore,
+			})
+		}
+	}
+
+	return violations
+}
+
+// CheckCoverage reports files with coverage below minCoverage, files without statements are skipped.
+func CheckCoverage(coverageData []*coverage.FileCoverage, minCoverage float64) []Violation {
+	violations := make([]Violation, 0)
+
+	for _, cov := range coverageData {
+		if cov.Statements > 0 && cov.Coverage < minCoverage {
+			violations = append(violations, Violation{
+				Rule:      Coverage,
+				File:      cov.File,
+				Value:     cov.Coverage,
+				Threshold: minCoverage,
+			})
+		}
+	}
+
+	return violations
+}
+
+// CheckTotalCoverage reports coverage of all statements below minCoverage, there is nothing to cover without them.
+func CheckTotalCoverage(coverageData []*coverage.FileCoverage, minCoverage float64) []Violation {
+	statements, covered := 0, 0
+
+	for _, cov := range coverageData {
+		statements += cov.Statements
+		covered += cov.Covered
+	}
+
+	if statements == 0 {
+		return []Violation{}
+	}
+
+	total := float64(covered) * percentMultiplier / float64(statements)
+	if total >= minCoverage {
+		return []Violation{}
+	}
+
+	return []Violation{{Rule: TotalCoverage, Value: total, Threshold: minCoverage}}
+}
+
+// Sort orders violations by rule, file and line to get stable output.
+func Sort(violations []Violation) {
+	slices.SortFunc(violations, func(a, b Violation) int {
+		if c := strings.Compare(a.Rule, b.Rule); c != 0 {
+			return c
+		}
+
+		if c := strings.Compare(a.Engine, b.Engine); c != 0 {
+			return c
+		}
+
+		if c := strings.Compare(a.File, b.File); c != 0 {
+			return c
+		}
+
+		return a.Line - b.Line
+	})
+}
@@ -0,0 +1,106 @@
+package check
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/coverage"
+	"github.com/vbvictor/grit/pkg/report"
+)
+
+func complexityData() []*complexity.FileStat {
+	return []*complexity.FileStat{
+		{
+			Path: "pkg/run.go",
+			Functions: []complexity.FunctionStat{
+				{Name: "Run", Line: 10, Complexity: 12},
+				{Name: "helper", Line: 40, Complexity: 2},
+			},
+			AvgComplexity: 7,
+		},
+		{
+			Path:          "pkg/print.go",
+			Functions:     []complexity.FunctionStat{{Name: "Print", Line: 5, Complexity: 3}},
+			AvgComplexity: 3,
+		},
+	}
+}
+
+func TestCheckFunctions(t *testing.T) {
+	assert.Equal(t, []Violation{
+		{
+			Rule: FunctionComplexity, Engine: complexity.Gocyclo, File: "pkg/run.go", Line: 10, Function: "Run",
+			Value: 12, Threshold: 10,
+		},
+	}, CheckFunctions(complexityData(), complexity.Gocyclo, 10))
+
+	assert.Empty(t, CheckFunctions(complexityData(), complexity.Gocyclo, 12))
+}
+
+func TestCheckFiles(t *testing.T) {
+	assert.Equal(t, []Violation{
+		{Rule: FileComplexity, Engine: complexity.Gocognit, File: "pkg/run.go", Value: 7, Threshold: 5},
+	}, CheckFiles(complexityData(), complexity.Gocognit, 5))
+}
+
+func TestCheckScores(t *testing.T) {
+	scores := []*report.FileScore{{File: "a.go", Score: 120}, {File: "b.go", Score: 80}}
+
+	assert.Equal(t, []Violation{{Rule: Score, File: "a.go", Value: 120, Threshold: 100}}, CheckScores(scores, 100))
+}
+
+func TestCheckCoverage(t *testing.T) {
+	covData := []*coverage.FileCoverage{
+		{File: "a.go", Coverage: 40, Statements: 10, Covered: 4},
+		{File: "b.go", Coverage: 90, Statements: 10, Covered: 9},
+		{File: "types.go", Coverage: 0},
+	}
+
+	assert.Equal(t, []Violation{{Rule: Coverage, File: "a.go", Value: 40, Threshold: 60}}, CheckCoverage(covData, 60))
+}
+
+func TestCheckTotalCoverage(t *testing.T) {
+	covData := []*coverage.FileCoverage{
+		{File: "a.go", Coverage: 40, Statements: 10, Covered: 4},
+		{File: "b.go", Coverage: 90, Statements: 10, Covered: 9},
+	}
+
+	assert.Equal(t, []Violation{{Rule: TotalCoverage, Value: 65, Threshold: 70}}, CheckTotalCoverage(covData, 70))
+	assert.Empty(t, CheckTotalCoverage(covData, 65))
+	assert.Empty(t, CheckTotalCoverage([]*coverage.FileCoverage{{File: "types.go"}}, 70))
+}
+
+func TestThresholds(t *testing.T) {
+	assert.True(t, (&Thresholds{}).Empty())
+
+	scoreOnly := &Thresholds{MaxScore: 10}
+	assert.True(t, scoreOnly.NeedsChurn())
+	assert.True(t, scoreOnly.NeedsComplexity())
+	assert.True(t, scoreOnly.NeedsCoverage())
+
+	functionsOnly := &Thresholds{MaxFunctionComplexity: map[string]int{complexity.Gocyclo: 10}}
+	assert.False(t, functionsOnly.Empty())
+	assert.False(t, functionsOnly.NeedsComplexity())
+	assert.False(t, functionsOnly.NeedsCoverage())
+}
+
+func TestSort(t *testing.T) {
+	violations := []Violation{
+		{Rule: Score, File: "a.go"},
+		{Rule: Coverage, File: "b.go"},
+		{Rule: Coverage, File: "a.go"},
+		{Rule: FunctionComplexity, File: "a.go", Line: 20},
+		{Rule: FunctionComplexity, File: "a.go", Line: 3},
+	}
+
+	Sort(violations)
+
+	assert.Equal(t, []Violation{
+		{Rule: Coverage, File: "a.go"},
+		{Rule: Coverage, File: "b.go"},
+		{Rule: FunctionComplexity, File: "a.go", Line: 3},
+		{Rule: FunctionComplexity, File: "a.go", Line: 20},
+		{Rule: Score, File: "a.go"},
+	}, violations)
+}
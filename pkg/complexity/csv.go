@@ -5,23 +5,169 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 )
 
+// Columns read by CSV engine.
+const (
+	ColumnFile       = "file"
+	ColumnFunction   = "function"
+	ColumnLength     = "length"
+	ColumnComplexity = "complexity"
+	ColumnLine       = "line"
+	ColumnPackages   = "packages"
+)
+
+// DefaultComplexityFile is read by CSV engine from <path> when no files are given.
+const DefaultComplexityFile = "complexity.csv"
+
+// DefaultCSVColumns is the order of columns in CSV data without header.
+var DefaultCSVColumns = []string{
+	ColumnFile, ColumnFunction, ColumnLength, ColumnComplexity, ColumnLine, ColumnPackages,
+}
+
+var requiredCSVColumns = []string{ColumnFile, ColumnFunction, ColumnComplexity}
+
+// Header names produced by popular tools that are recognized without explicit mapping.
+var csvColumnAliases = map[string]string{
+	"filename":    ColumnFile,
+	"filepath":    ColumnFile,
+	"path":        ColumnFile,
+	"name":        ColumnFunction,
+	"func":        ColumnFunction,
+	"method":      ColumnFunction,
+	"nloc":        ColumnLength,
+	"lines":       ColumnLength,
+	"line-count":  ColumnLength,
+	"ccn":         ColumnComplexity,
+	"cyclomatic":  ColumnComplexity,
+	"start":       ColumnLine,
+	"start_line":  ColumnLine,
+	"start-line":  ColumnLine,
+	"package":     ColumnPackages,
+	"namespace":   ColumnPackages,
+	"namespaces":  ColumnPackages,
+	"line_number": ColumnLine,
+}
+
+// csvHeaderColumns looks up column by header name, both column names and their aliases are recognized.
+var csvHeaderColumns = func() map[string]string {
+	columns := maps.Clone(csvColumnAliases)
+	for _, column := range DefaultCSVColumns {
+		columns[column] = column
+	}
+
+	return columns
+}()
+
+var ErrInvalidCSVColumns = errors.New("invalid CSV column mapping")
+
+// csvMapping maps CSV engine column to a header name or 1-based column number given by user.
+type csvMapping map[string]string
+
+// parseCSVMapping parses column mapping given in 'column=header' or 'column=number' form.
+func parseCSVMapping(specs []string) (csvMapping, error) {
+	mapping := make(csvMapping, len(specs))
+
+	for _, spec := range specs {
+		column, source, found := strings.Cut(spec, "=")
+		column = strings.ToLower(strings.TrimSpace(column))
+		source = strings.TrimSpace(source)
+
+		if !found || source == "" {
+			return nil, fmt.Errorf("%w: expected 'column=header' or 'column=number', got '%s'",
+				ErrInvalidCSVColumns, spec)
+		}
+
+		if !slices.Contains(DefaultCSVColumns, column) {
+			return nil, fmt.Errorf("%w: unknown column '%s', expected one of %v",
+				ErrInvalidCSVColumns, column, DefaultCSVColumns)
+		}
+
+		if number, err := strconv.Atoi(source); err == nil && number < 1 {
+			return nil, fmt.Errorf("%w: column number must be positive, got '%s'", ErrInvalidCSVColumns, spec)
+		}
+
+		mapping[column] = source
+	}
+
+	return mapping, nil
+}
+
+// RunCSVFiles reads complexity data from all CSV files matched by opts.ComplexityFiles.
+// Relative patterns are resolved against repoPath, 'complexity.csv' in repoPath is used by default.
+func RunCSVFiles(repoPath string, opts *Options) ([]*FileStat, error) {
+	patterns := opts.ComplexityFiles
+	if len(patterns) == 0 {
+		patterns = []string{DefaultComplexityFile}
+	}
+
+	functionStats := make([]*FunctionStat, 0)
+
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(repoPath, pattern)
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid complexity file pattern %s: %w", pattern, err)
+		}
+
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no complexity files found at %s: %w", pattern, os.ErrNotExist)
+		}
+
+		for _, match := range matches {
+			stats, err := readCSVFile(match, opts)
+			if err != nil {
+				return nil, err
+			}
+
+			functionStats = append(functionStats, stats...)
+		}
+	}
+
+	return groupFunctions(functionStats, opts), nil
+}
+
 func RunCSV(filepath string, opts *Options) ([]*FileStat, error) {
+	functionStats, err := readCSVFile(filepath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return groupFunctions(functionStats, opts), nil
+}
+
+func readCSVFile(filepath string, opts *Options) ([]*FunctionStat, error) {
+	mapping, err := parseCSVMapping(opts.CSVColumns)
+	if err != nil {
+		return nil, err
+	}
+
 	file, err := os.Open(filepath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open CSV file at %s: %w", filepath, err)
 	}
 	defer file.Close()
 
-	functionStats, err := readComplexityFromCSV(file)
+	functionStats, err := readComplexityFromCSV(file, mapping)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse complexity data from CSV: %w", err)
+		return nil, fmt.Errorf("failed to parse complexity data from CSV %s: %w", filepath, err)
 	}
 
+	return functionStats, nil
+}
+
+// groupFunctions collects function stats reported by non-Go engines into files
+// and calculates average complexity for each file.
+func groupFunctions(functionStats []*FunctionStat, opts *Options) []*FileStat {
 	fileMap := make(map[string][]FunctionStat)
 	for _, stat := range functionStats {
 		fileMap[stat.File] = append(fileMap[stat.File], *stat)
@@ -34,6 +180,10 @@ func RunCSV(filepath string, opts *Options) ([]*FileStat, error) {
 			continue
 		}
 
+		if opts.Tests.Skip(file) {
+			continue
+		}
+
 		result = append(result, &FileStat{
 			Path:      file,
 			Functions: functions,
@@ -43,12 +193,88 @@ func RunCSV(filepath string, opts *Options) ([]*FileStat, error) {
 	// Calculate average complexity for each file
 	AvgComplexity(result)
 
-	return result, nil
+	return result
 }
 
-const minimalColumns = 4
+// csvIndex maps CSV engine column to 0-based position in a record.
+type csvIndex map[string]int
+
+// headerIndex resolves columns from the header row, returns false if record is not a header.
+func headerIndex(header []string, mapping csvMapping) (csvIndex, bool) {
+	index := namedColumns(header, mapping)
+
+	// Data row can't contain column names, numeric mapping alone doesn't make a header.
+	if len(index) == 0 {
+		return nil, false
+	}
+
+	for column, source := range mapping {
+		if number, err := strconv.Atoi(source); err == nil {
+			index[column] = number - 1
+		}
+	}
 
-func readComplexityFromCSV(r io.Reader) ([]*FunctionStat, error) { //nolint:cyclop // complexity is not a problem here
+	for _, column := range requiredCSVColumns {
+		if _, ok := index[column]; !ok {
+			return nil, false
+		}
+	}
+
+	return index, true
+}
+
+// namedColumns finds positions of columns by header names, names mapped by user take precedence
+// over column names and their aliases, the first of cells naming the same column is used otherwise.
+func namedColumns(header []string, mapping csvMapping) csvIndex {
+	index := make(csvIndex)
+
+	for pos, cell := range header {
+		name := strings.ToLower(strings.TrimSpace(cell))
+
+		for column, source := range mapping {
+			if strings.EqualFold(source, name) {
+				index[column] = pos
+			}
+		}
+
+		if column, ok := csvHeaderColumns[name]; ok {
+			if _, mapped := index[column]; !mapped {
+				index[column] = pos
+			}
+		}
+	}
+
+	return index
+}
+
+// positionalIndex resolves columns of CSV data without header using default order and numeric mapping.
+func positionalIndex(mapping csvMapping) (csvIndex, error) {
+	index := make(csvIndex, len(DefaultCSVColumns))
+	for pos, column := range DefaultCSVColumns {
+		index[column] = pos
+	}
+
+	for column, source := range mapping {
+		number, err := strconv.Atoi(source)
+		if err != nil {
+			return nil, fmt.Errorf("%w: column '%s' is mapped to header '%s', but CSV data has no header",
+				ErrInvalidCSVColumns, column, source)
+		}
+
+		// Default column at the same position is replaced by the mapped one.
+		for other, pos := range index {
+			if pos == number-1 && mapping[other] == "" {
+				delete(index, other)
+			}
+		}
+
+		index[column] = number - 1
+	}
+
+	return index, nil
+}
+
+func readComplexityFromCSV(r io.Reader, mapping csvMapping) ([]*FunctionStat, error) {
 	csvReader := csv.NewReader(r)
 	csvReader.FieldsPerRecord = -1 // Allow variable number of fields per record
 
@@ -61,50 +287,91 @@ func readComplexityFromCSV(r io.Reader) ([]*FunctionStat, error) { //nolint:cycl
 		return nil, errors.New("CSV data is empty")
 	}
 
-	result := make([]*FunctionStat, 0, len(records))
+	firstRow := 0
 
-	for pos, record := range records {
-		// Minimum required fields: filename, function, length, complexity
-		if len(record) < minimalColumns {
-			return nil, fmt.Errorf("row %d: insufficient columns, expected at least 4", pos+1)
-		}
+	index, hasHeader := headerIndex(records[0], mapping)
+	if hasHeader {
+		firstRow = 1
+	} else if index, err = positionalIndex(mapping); err != nil {
+		return nil, err
+	}
 
-		stat := &FunctionStat{
-			File: record[0],
-			Name: record[1],
-		}
+	result := make([]*FunctionStat, 0, len(records)-firstRow)
 
-		length, err := strconv.Atoi(record[2])
+	for pos := firstRow; pos < len(records); pos++ {
+		stat, err := parseCSVRecord(records[pos], index)
 		if err != nil {
-			return nil, fmt.Errorf("row %d: invalid length value '%s': %w", pos+1, record[2], err)
+			return nil, fmt.Errorf("row %d: %w", pos+1, err)
 		}
 
-		stat.Length = length
+		result = append(result, stat)
+	}
 
-		complexity, err := strconv.Atoi(record[3])
-		if err != nil {
-			return nil, fmt.Errorf("row %d: invalid complexity value '%s': %w", pos+1, record[3], err)
-		}
+	return result, nil
+}
 
-		stat.Complexity = complexity
+// csvField returns value of the column in record, empty string if column is absent.
+func csvField(record []string, index csvIndex, column string) string {
+	pos, ok := index[column]
+	if !ok || pos >= len(record) {
+		return ""
+	}
 
-		// Optional: line number
-		if len(record) > 4 && record[4] != "" {
-			line, err := strconv.Atoi(record[4])
-			if err != nil {
-				return nil, fmt.Errorf("row %d: invalid line value '%s': %w", pos+1, record[4], err)
-			}
+	return strings.TrimSpace(record[pos])
+}
+
+func csvInt(record []string, index csvIndex, column string) (int, error) {
+	value := csvField(record, index, column)
+	if value == "" {
+		return 0, nil
+	}
 
-			stat.Line = line
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("column '%s' has invalid value '%s', expected integer", column, value)
+	}
+
+	if number < 0 {
+		return 0, fmt.Errorf("column '%s' has negative value '%s'", column, value)
+	}
+
+	return number, nil
+}
+
+func parseCSVRecord(record []string, index csvIndex) (*FunctionStat, error) {
+	for _, column := range requiredCSVColumns {
+		if index[column] >= len(record) {
+			return nil, fmt.Errorf("insufficient columns, got %d, column '%s' is expected at position %d",
+				len(record), column, index[column]+1)
 		}
 
-		// Optional: packages
-		if len(record) > 5 && record[5] != "" {
-			stat.Package = strings.Split(record[5], ";")
+		if csvField(record, index, column) == "" {
+			return nil, fmt.Errorf("column '%s' is empty", column)
 		}
+	}
 
-		result = append(result, stat)
+	stat := &FunctionStat{
+		File: csvField(record, index, ColumnFile),
+		Name: csvField(record, index, ColumnFunction),
 	}
 
-	return result, nil
+	var err error
+
+	if stat.Length, err = csvInt(record, index, ColumnLength); err != nil {
+		return nil, err
+	}
+
+	if stat.Complexity, err = csvInt(record, index, ColumnComplexity); err != nil {
+		return nil, err
+	}
+
+	if stat.Line, err = csvInt(record, index, ColumnLine); err != nil {
+		return nil, err
+	}
+
+	if packages := csvField(record, index, ColumnPackages); packages != "" {
+		stat.Package = strings.Split(packages, ";")
+	}
+
+	return stat, nil
 }
@@ -134,7 +134,7 @@ anothefile.go,Process,80,8,,main`,
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			reader := strings.NewReader(tt.csv)
-			got, err := readComplexityFromCSV(reader)
+			got, err := readComplexityFromCSV(reader, nil)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -199,3 +199,128 @@ file3.go,func4,100,15,25,pkg3;pkg4`
 	assert.Len(t, filteredResults, 1)
 	assert.Equal(t, "file3.go", filteredResults[0].Path)
 }
+
+func TestReadComplexityFromCSVColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		columns []string
+		want    []*FunctionStat
+		wantErr string
+	}{
+		{
+			name: "header in any order",
+			csv: `complexity,line,function,file
+7,12,Run,main.go`,
+			want: []*FunctionStat{{File: "main.go", Name: "Run", Complexity: 7, Line: 12}},
+		},
+		{
+			name: "header with aliases",
+			csv: `NLOC,CCN,Filename,Name,Start_Line
+30,4,src/app.cpp,App::run,10`,
+			want: []*FunctionStat{{File: "src/app.cpp", Name: "App::run", Length: 30, Complexity: 4, Line: 10}},
+		},
+		{
+			name:    "header mapped by name",
+			csv:     "source,method,score\nlib.py,parse,9",
+			columns: []string{"file=source", "function=method", "complexity=score"},
+			want:    []*FunctionStat{{File: "lib.py", Name: "parse", Complexity: 9}},
+		},
+		{
+			name:    "headerless mapped by number",
+			csv:     "9,lib.py,parse",
+			columns: []string{"complexity=1", "file=2", "function=3"},
+			want:    []*FunctionStat{{File: "lib.py", Name: "parse", Complexity: 9}},
+		},
+		{
+			name:    "headerless mapped by header name",
+			csv:     "lib.py,parse,10,9",
+			columns: []string{"complexity=score"},
+			wantErr: "has no header",
+		},
+		{
+			name:    "row error names the column",
+			csv:     "file,function,complexity\nmain.go,Run,7\nmain.go,Stop,high",
+			wantErr: "row 3: column 'complexity' has invalid value 'high', expected integer",
+		},
+		{
+			name:    "empty required column",
+			csv:     "file,function,complexity\nmain.go,,7",
+			wantErr: "row 2: column 'function' is empty",
+		},
+		{
+			name:    "negative value",
+			csv:     "main.go,Run,10,-1",
+			wantErr: "row 1: column 'complexity' has negative value '-1'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping, err := parseCSVMapping(tt.columns)
+			require.NoError(t, err)
+
+			got, err := readComplexityFromCSV(strings.NewReader(tt.csv), mapping)
+
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseCSVMapping(t *testing.T) {
+	mapping, err := parseCSVMapping([]string{"File = path", "complexity=3"})
+	require.NoError(t, err)
+	assert.Equal(t, csvMapping{"file": "path", "complexity": "3"}, mapping)
+
+	for _, spec := range []string{"unknown=col", "file", "file=", "line=0"} {
+		_, err := parseCSVMapping([]string{spec})
+		require.ErrorIs(t, err, ErrInvalidCSVColumns, spec)
+	}
+}
+
+func TestRunCSVFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "reports"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "complexity.csv"), []byte("a.go,A,10,2\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "reports", "cpp.csv"),
+		[]byte("file,function,complexity\nb.cpp,B,4\nb.cpp,C,6\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "reports", "py.csv"),
+		[]byte("c.py,D,1,8\n"), 0o600))
+
+	t.Run("default file", func(t *testing.T) {
+		results, err := RunCSVFiles(dir, &Options{})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "a.go", results[0].Path)
+	})
+
+	t.Run("glob merges files", func(t *testing.T) {
+		results, err := RunCSVFiles(dir, &Options{ComplexityFiles: []string{filepath.Join("reports", "*.csv")}})
+		require.NoError(t, err)
+
+		avg := make(map[string]float64)
+		for _, file := range results {
+			avg[file.Path] = file.AvgComplexity
+		}
+
+		assert.Equal(t, map[string]float64{"b.cpp": 5, "c.py": 8}, avg)
+	})
+
+	t.Run("absolute path", func(t *testing.T) {
+		results, err := RunCSVFiles(t.TempDir(), &Options{ComplexityFiles: []string{filepath.Join(dir, "complexity.csv")}})
+		require.NoError(t, err)
+		assert.Len(t, results, 1)
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		_, err := RunCSVFiles(dir, &Options{ComplexityFiles: []string{"missing*.csv"}})
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
@@ -0,0 +1,188 @@
+package complexity
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Change describes how function complexity changed between two revisions.
+type Change = string
+
+const (
+	Added     Change = "added"
+	Removed   Change = "removed"
+	Increased Change = "increased"
+	Decreased Change = "decreased"
+)
+
+// FunctionDiff is a function whose complexity differs between base and head revisions.
+// File and Line refer to head revision, or to base revision for removed functions.
+type FunctionDiff struct {
+	File           string
+	Package        []string
+	Name           string
+	Line           int
+	Change         Change
+	BaseComplexity int
+	HeadComplexity int
+}
+
+// Delta returns complexity change, positive when function got more complex.
+func (d FunctionDiff) Delta() int {
+	return d.HeadComplexity - d.BaseComplexity
+}
+
+// functionKey identifies function across revisions by its directory, package, receiver and name.
+// Receiver is a part of function name reported by engines, e.g. '(*Type).Method'.
+func functionKey(fn FunctionStat) string {
+	return filepath.ToSlash(filepath.Dir(fn.File)) + "\x00" + strings.Join(fn.Package, ".") + "\x00" + fn.Name
+}
+
+func groupByKey(files []*FileStat) (map[string][]FunctionStat, []string) {
+	functions := make(map[string][]FunctionStat)
+	keys := make([]string, 0)
+
+	for _, file := range files {
+		for _, fn := range file.Functions {
+			key := functionKey(fn)
+			if _, ok := functions[key]; !ok {
+				keys = append(keys, key)
+			}
+
+			functions[key] = append(functions[key], fn)
+		}
+	}
+
+	// Functions sharing a key, e.g. 'init' or build-tagged variants, are matched in order of appearance.
+	for _, fns := range functions {
+		slices.SortFunc(fns, func(a, b FunctionStat) int {
+			if c := strings.Compare(a.File, b.File); c != 0 {
+				return c
+			}
+
+			return a.Line - b.Line
+		})
+	}
+
+	return functions, keys
+}
+
+// DiffFunctions compares complexity of functions in base and head revisions
+// and returns functions that were added, removed or changed complexity.
+// When opts.Top is set, only opts.Top functions with the largest absolute change are returned.
+func DiffFunctions(base, head []*FileStat, opts Options) []FunctionDiff {
+	baseFunctions, baseKeys := groupByKey(base)
+	headFunctions, headKeys := groupByKey(head)
+
+	diffs := make([]FunctionDiff, 0)
+	diffs = appendHeadDiffs(diffs, headKeys, headFunctions, baseFunctions)
+	diffs = appendRemovedDiffs(diffs, baseKeys, baseFunctions, headFunctions)
+
+	if opts.Top > 0 && opts.Top < len(diffs) {
+		diffs = largestDiffs(diffs, opts.Top)
+	}
+
+	SortDiffs(diffs)
+
+	return diffs
+}
+
+// appendHeadDiffs appends head functions that are new or changed complexity compared to base revision.
+func appendHeadDiffs(diffs []FunctionDiff, keys []string, head, base map[string][]FunctionStat) []FunctionDiff {
+	for _, key := range keys {
+		baseFns := base[key]
+
+		for pos, fn := range head[key] {
+			if pos >= len(baseFns) {
+				diffs = append(diffs, newDiff(fn, Added, 0, fn.Complexity))
+
+				continue
+			}
+
+			if diff, ok := changedDiff(baseFns[pos], fn); ok {
+				diffs = append(diffs, diff)
+			}
+		}
+	}
+
+	return diffs
+}
+
+// appendRemovedDiffs appends base functions that have no counterpart in head revision.
+func appendRemovedDiffs(diffs []FunctionDiff, keys []string, base, head map[string][]FunctionStat) []FunctionDiff {
+	for _, key := range keys {
+		baseFns := base[key]
+
+		for _, fn := range baseFns[min(len(head[key]), len(baseFns)):] {
+			diffs = append(diffs, newDiff(fn, Removed, fn.Complexity, 0))
+		}
+	}
+
+	return diffs
+}
+
+// changedDiff compares matched functions and reports false when complexity stayed the same.
+func changedDiff(base, head FunctionStat) (FunctionDiff, bool) {
+	switch {
+	case head.Complexity > base.Complexity:
+		return newDiff(head, Increased, base.Complexity, head.Complexity), true
+	case head.Complexity < base.Complexity:
+		return newDiff(head, Decreased, base.Complexity, head.Complexity), true
+	default:
+		return FunctionDiff{}, false
+	}
+}
+
+func newDiff(fn FunctionStat, change Change, baseComplexity, headComplexity int) FunctionDiff {
+	return FunctionDiff{
+		File:           fn.File,
+		Package:        fn.Package,
+		Name:           fn.Name,
+		Line:           fn.Line,
+		Change:         change,
+		BaseComplexity: baseComplexity,
+		HeadComplexity: headComplexity,
+	}
+}
+
+// largestDiffs returns top diffs with the largest absolute complexity change,
+// so that simplified and removed functions compete with the complicated ones.
+func largestDiffs(diffs []FunctionDiff, top int) []FunctionDiff {
+	slices.SortStableFunc(diffs, func(a, b FunctionDiff) int {
+		if delta := abs(b.Delta()) - abs(a.Delta()); delta != 0 {
+			return delta
+		}
+
+		if c := strings.Compare(a.File, b.File); c != 0 {
+			return c
+		}
+
+		return a.Line - b.Line
+	})
+
+	return diffs[:top]
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
+
+// SortDiffs orders diffs by complexity increase, the most complicated functions go first.
+func SortDiffs(diffs []FunctionDiff) {
+	slices.SortStableFunc(diffs, func(a, b FunctionDiff) int {
+		if a.Delta() != b.Delta() {
+			return b.Delta() - a.Delta()
+		}
+
+		if c := strings.Compare(a.File, b.File); c != 0 {
+			return c
+		}
+
+		return a.Line - b.Line
+	})
+}
@@ -0,0 +1,128 @@
+package complexity
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffFunctions(t *testing.T) {
+	base := []*FileStat{
+		{
+			Path: "pkg/lines.go",
+			Functions: []FunctionStat{
+				{File: "pkg/lines.go", Package: []string{"pkg"}, Name: "processLines", Line: 10, Complexity: 6},
+				{File: "pkg/lines.go", Package: []string{"pkg"}, Name: "(*Parser).Parse", Line: 30, Complexity: 9},
+				{File: "pkg/lines.go", Package: []string{"pkg"}, Name: "unchanged", Line: 50, Complexity: 3},
+				{File: "pkg/lines.go", Package: []string{"pkg"}, Name: "obsolete", Line: 60, Complexity: 4},
+			},
+		},
+		{
+			Path: "cmd/main.go",
+			Functions: []FunctionStat{
+				{File: "cmd/main.go", Package: []string{"main"}, Name: "init", Line: 5, Complexity: 1},
+			},
+		},
+	}
+	head := []*FileStat{
+		{
+			// Function moved to another file of the same package is matched.
+			Path: "pkg/process.go",
+			Functions: []FunctionStat{
+				{File: "pkg/process.go", Package: []string{"pkg"}, Name: "processLines", Line: 3, Complexity: 14},
+			},
+		},
+		{
+			Path: "pkg/lines.go",
+			Functions: []FunctionStat{
+				{File: "pkg/lines.go", Package: []string{"pkg"}, Name: "(*Parser).Parse", Line: 20, Complexity: 5},
+				{File: "pkg/lines.go", Package: []string{"pkg"}, Name: "Parse", Line: 40, Complexity: 2},
+				{File: "pkg/lines.go", Package: []string{"pkg"}, Name: "unchanged", Line: 50, Complexity: 3},
+			},
+		},
+		{
+			Path: "cmd/main.go",
+			Functions: []FunctionStat{
+				{File: "cmd/main.go", Package: []string{"main"}, Name: "init", Line: 5, Complexity: 1},
+				{File: "cmd/main.go", Package: []string{"main"}, Name: "init", Line: 15, Complexity: 2},
+			},
+		},
+		{
+			// Same package and name in another directory is a different function.
+			Path: "other/lines.go",
+			Functions: []FunctionStat{
+				{File: "other/lines.go", Package: []string{"pkg"}, Name: "obsolete", Line: 1, Complexity: 4},
+			},
+		},
+	}
+
+	diffs := DiffFunctions(base, head, Options{})
+
+	assert.Equal(t, []FunctionDiff{
+		{
+			File: "pkg/process.go", Package: []string{"pkg"}, Name: "processLines", Line: 3,
+			Change: Increased, BaseComplexity: 6, HeadComplexity: 14,
+		},
+		{
+			File: "other/lines.go", Package: []string{"pkg"}, Name: "obsolete", Line: 1,
+			Change: Added, BaseComplexity: 0, HeadComplexity: 4,
+		},
+		{
+			File: "cmd/main.go", Package: []string{"main"}, Name: "init", Line: 15,
+			Change: Added, BaseComplexity: 0, HeadComplexity: 2,
+		},
+		{
+			File: "pkg/lines.go", Package: []string{"pkg"}, Name: "Parse", Line: 40,
+			Change: Added, BaseComplexity: 0, HeadComplexity: 2,
+		},
+		{
+			File: "pkg/lines.go", Package: []string{"pkg"}, Name: "(*Parser).Parse", Line: 20,
+			Change: Decreased, BaseComplexity: 9, HeadComplexity: 5,
+		},
+		{
+			File: "pkg/lines.go", Package: []string{"pkg"}, Name: "obsolete", Line: 60,
+			Change: Removed, BaseComplexity: 4, HeadComplexity: 0,
+		},
+	}, diffs)
+
+	// Top keeps the largest changes in both directions.
+	top := DiffFunctions(base, head, Options{Top: 3})
+	assert.Equal(t, []FunctionDiff{diffs[0], diffs[1], diffs[4]}, top)
+}
+
+func TestPrintDiff(t *testing.T) {
+	diffs := []FunctionDiff{
+		{
+			File: "pkg/lines.go", Package: []string{"pkg"}, Name: "processLines", Line: 10,
+			Change: Increased, BaseComplexity: 6, HeadComplexity: 14,
+		},
+		{
+			File: "pkg/lines.go", Package: []string{"pkg"}, Name: "obsolete", Line: 60,
+			Change: Removed, BaseComplexity: 4,
+		},
+	}
+
+	var tabular bytes.Buffer
+
+	PrintDiffTabular(diffs, &tabular)
+	assert.Contains(t, tabular.String(), "pkg.processLines")
+	assert.Contains(t, tabular.String(), "+8")
+	assert.Contains(t, tabular.String(), "-4")
+
+	var csv bytes.Buffer
+
+	PrintDiffCSV(diffs, &csv)
+	assert.Equal(t, `CHANGE,FILEPATH,LINE,PACKAGE,FUNCTION,BASE,HEAD,DELTA
+increased,pkg/lines.go,10,pkg,processLines,6,14,8
+removed,pkg/lines.go,60,pkg,obsolete,4,0,-4
+`, csv.String())
+}
+
+func TestPrintDiffTabularEmpty(t *testing.T) {
+	var buf bytes.Buffer
+
+	PrintDiffTabular(DiffFunctions(nil, nil, Options{}), &buf)
+
+	assert.Equal(t, "\nFunction complexity changes:\nNo complexity changes\n", buf.String())
+}
@@ -0,0 +1,91 @@
+package complexity
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+// EngineFunc calculates complexity of all functions located under repoPath.
+type EngineFunc func(repoPath string, opts *Options) ([]*FileStat, error)
+
+var (
+	engines = make(map[Engine]EngineFunc)
+	// externals holds commands of registered external engines by their names.
+	externals = make(map[Engine]string)
+)
+
+// RegisterEngine makes complexity engine available by its name.
+// Registering an engine with an already used name replaces the previous one.
+func RegisterEngine(name Engine, run EngineFunc) {
+	engines[name] = run
+}
+
+// LookupEngine returns registered complexity engine by its name.
+func LookupEngine(name Engine) (EngineFunc, bool) {
+	run, ok := engines[name]
+
+	return run, ok
+}
+
+// Engines returns sorted names of all registered complexity engines.
+func Engines() []Engine {
+	names := maps.Keys(engines)
+	slices.Sort(names)
+
+	return names
+}
+
+// EnginesHelp returns comma-separated list of registered engines for flag descriptions.
+func EnginesHelp() string {
+	return strings.Join(Engines(), ", ")
+}
+
+func init() {
+	RegisterEngine(Gocyclo, RunGocyclo)
+	RegisterEngine(Gocognit, RunGocognit)
+	RegisterEngine(CSV, RunCSVFiles)
+}
+
+// RegisterExternalEngines registers engines given in 'name=command' form. Names of builtin and
+// other external engines are rejected, registering the same engine again is allowed.
+func RegisterExternalEngines(specs []string) error {
+	for _, spec := range specs {
+		engine, err := ParseExternalEngine(spec)
+		if err != nil {
+			return err
+		}
+
+		command := strings.Join(engine.Command, " ")
+
+		if registered, ok := externals[engine.Name]; ok && registered == command {
+			continue
+		}
+
+		if _, ok := LookupEngine(engine.Name); ok {
+			return fmt.Errorf("%w: engine '%s' is already registered", ErrInvalidExternalEngine, engine.Name)
+		}
+
+		RegisterEngine(engine.Name, engine.Run)
+		externals[engine.Name] = command
+	}
+
+	return nil
+}
+
+// ParseExternalEngine parses external engine given in 'name=command' form. Command is split into
+// arguments by whitespace without shell quoting, so arguments can't contain spaces.
+// Wrap such commands into a script.
+func ParseExternalEngine(spec string) (*ExternalEngine, error) {
+	name, command, found := strings.Cut(spec, "=")
+	name = strings.TrimSpace(name)
+
+	args := strings.Fields(command)
+	if !found || name == "" || len(args) == 0 {
+		return nil, fmt.Errorf("%w: expected 'name=command', got '%s'", ErrInvalidExternalEngine, spec)
+	}
+
+	return &ExternalEngine{Name: name, Command: args}, nil
+}
@@ -0,0 +1,90 @@
+package complexity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinEngines(t *testing.T) {
+	for _, name := range []Engine{Gocyclo, Gocognit, CSV} {
+		_, ok := LookupEngine(name)
+		assert.True(t, ok, "engine %s should be registered", name)
+		assert.Contains(t, Engines(), name)
+	}
+}
+
+func TestRunComplexityUnsupportedEngine(t *testing.T) {
+	_, err := RunComplexity(".", &Options{Engine: "unknown"})
+	require.ErrorIs(t, err, ErrUnsupportedEngine)
+}
+
+func TestParseExternalEngine(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    *ExternalEngine
+		wantErr bool
+	}{
+		{
+			name: "command with arguments",
+			spec: "lizard=python3 scripts/lizard_complexity.py --output - .",
+			want: &ExternalEngine{
+				Name:    "lizard",
+				Command: []string{"python3", "scripts/lizard_complexity.py", "--output", "-", "."},
+			},
+		},
+		{
+			name: "command containing equal sign",
+			spec: "tool=tool --mode=json",
+			want: &ExternalEngine{Name: "tool", Command: []string{"tool", "--mode=json"}},
+		},
+		{
+			name:    "missing command",
+			spec:    "lizard=",
+			wantErr: true,
+		},
+		{
+			name:    "missing name",
+			spec:    "=lizard",
+			wantErr: true,
+		},
+		{
+			name:    "no separator",
+			spec:    "lizard",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseExternalEngine(tt.spec)
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidExternalEngine)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRegisterExternalEngines(t *testing.T) {
+	require.NoError(t, RegisterExternalEngines([]string{"test-external=cat complexity.csv"}))
+
+	t.Cleanup(func() {
+		delete(engines, "test-external")
+		delete(externals, "test-external")
+	})
+
+	_, ok := LookupEngine("test-external")
+	assert.True(t, ok)
+
+	require.NoError(t, RegisterExternalEngines([]string{"test-external=cat  complexity.csv"}))
+	require.ErrorIs(t, RegisterExternalEngines([]string{"test-external=cat other.csv"}), ErrInvalidExternalEngine)
+	require.ErrorIs(t, RegisterExternalEngines([]string{Gocyclo + "=cat complexity.csv"}), ErrInvalidExternalEngine)
+}
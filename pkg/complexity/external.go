@@ -0,0 +1,119 @@
+package complexity
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+)
+
+// ExternalEngine runs a command that prints function complexity on stdout.
+// Output must be either CSV in the format read by CSV engine
+// or JSON object with "functions" array, see functionJSON for fields.
+type ExternalEngine struct {
+	Name    string
+	Command []string
+}
+
+type functionJSON struct {
+	File       string   `json:"file"`
+	Function   string   `json:"function"`
+	Length     int      `json:"length"`
+	Complexity int      `json:"complexity"`
+	Line       int      `json:"line"`
+	Packages   []string `json:"packages"`
+	Nesting    int      `json:"nesting"`
+	Params     int      `json:"params"`
+	Results    int      `json:"results"`
+	Returns    int      `json:"returns"`
+	Closures   int      `json:"closures"`
+}
+
+type functionsJSON struct {
+	Functions []functionJSON `json:"functions"`
+}
+
+func (e *ExternalEngine) Run(repoPath string, opts *Options) ([]*FileStat, error) {
+	cmd := exec.Command(e.Command[0], e.Command[1:]...) //nolint:gosec // command is configured by user
+	cmd.Dir = repoPath
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to run %s engine: %w\nstderr: %s", e.Name, err, stderr.String())
+	}
+
+	mapping, err := parseCSVMapping(opts.CSVColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	functionStats, err := readExternalOutput(stdout.Bytes(), mapping)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse output of %s engine: %w", e.Name, err)
+	}
+
+	return groupFunctions(functionStats, opts), nil
+}
+
+// readExternalOutput detects whether data is JSON or CSV by its first meaningful character.
+func readExternalOutput(data []byte, mapping csvMapping) ([]*FunctionStat, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, errors.New("engine output is empty")
+	}
+
+	switch trimmed[0] {
+	case '{', '[':
+		return readComplexityFromJSON(bytes.NewReader(trimmed))
+	default:
+		return readComplexityFromCSV(bytes.NewReader(trimmed), mapping)
+	}
+}
+
+func readComplexityFromJSON(r io.Reader) ([]*FunctionStat, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON data: %w", err)
+	}
+
+	var data functionsJSON
+
+	if bytes.HasPrefix(raw, []byte("[")) {
+		err = json.Unmarshal(raw, &data.Functions)
+	} else {
+		err = json.Unmarshal(raw, &data)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode JSON data: %w", err)
+	}
+
+	result := make([]*FunctionStat, 0, len(data.Functions))
+
+	for pos, fn := range data.Functions {
+		if fn.File == "" || fn.Function == "" {
+			return nil, fmt.Errorf("function %d: 'file' and 'function' fields are required", pos+1)
+		}
+
+		result = append(result, &FunctionStat{
+			File:       fn.File,
+			Package:    fn.Packages,
+			Name:       fn.Function,
+			Line:       fn.Line,
+			Length:     fn.Length,
+			Complexity: fn.Complexity,
+			Nesting:    fn.Nesting,
+			Params:     fn.Params,
+			Results:    fn.Results,
+			Returns:    fn.Returns,
+			Closures:   fn.Closures,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,88 @@
+package complexity
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadExternalOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    []*FunctionStat
+		wantErr bool
+	}{
+		{
+			name:   "csv output",
+			output: "src/main.cpp,main,20,4,10\n",
+			want: []*FunctionStat{
+				{File: "src/main.cpp", Name: "main", Length: 20, Complexity: 4, Line: 10},
+			},
+		},
+		{
+			name: "json object output",
+			output: `{"functions": [
+				{"file": "app.py", "function": "run", "length": 12, "complexity": 3, "line": 5, "packages": ["app"]}
+			]}`,
+			want: []*FunctionStat{
+				{File: "app.py", Name: "run", Length: 12, Complexity: 3, Line: 5, Package: []string{"app"}},
+			},
+		},
+		{
+			name:   "json array output",
+			output: `[{"file": "app.py", "function": "run", "complexity": 3}]`,
+			want: []*FunctionStat{
+				{File: "app.py", Name: "run", Complexity: 3},
+			},
+		},
+		{
+			name:    "json without file",
+			output:  `[{"function": "run", "complexity": 3}]`,
+			wantErr: true,
+		},
+		{
+			name:    "empty output",
+			output:  "  \n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readExternalOutput([]byte(tt.output), nil)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExternalEngineRun(t *testing.T) {
+	dir := t.TempDir()
+	content := "file1.cpp,func1,50,5,10\nfile1.cpp,func2,70,10,20\nfile2.cpp,func3,30,3,15\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "out.csv"), []byte(content), 0o600))
+
+	engine := &ExternalEngine{Name: "cat", Command: []string{"cat", "out.csv"}}
+
+	results, err := engine.Run(dir, &Options{ExcludeRegex: regexp.MustCompile(`file2`)})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "file1.cpp", results[0].Path)
+	assert.InDelta(t, 7.5, results[0].AvgComplexity, 0.001)
+
+	failing := &ExternalEngine{Name: "cat", Command: []string{"cat", "missing.csv"}}
+
+	_, err = failing.Run(dir, &Options{})
+	assert.Error(t, err)
+}
@@ -2,39 +2,19 @@ package complexity
 
 import (
 	"fmt"
-	"go/parser"
+	"go/ast"
 	"go/token"
-	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/uudashr/gocognit"
 )
 
-func RunGocognit(repoPath string, opts *Options) ([]*FileStat, error) { //nolint:funlen,cyclop // TODO: refactor later
+func RunGocognit(repoPath string, opts *Options) ([]*FileStat, error) {
 	fileMap := make(map[string][]FunctionStat)
 
-	err := filepath.Walk(repoPath, func(path string, _ os.FileInfo, err error) error {
-		if err != nil {
-			return fmt.Errorf("failed to walk path: %w", err)
-		}
-
-		if opts.ExcludeRegex != nil && opts.ExcludeRegex.MatchString(path) {
-			return nil
-		}
-
-		if !strings.HasSuffix(path, ".go") {
-			return nil
-		}
-
-		fileSet := token.NewFileSet()
-
-		file, err := parser.ParseFile(fileSet, path, nil, parser.ParseComments)
-		if err != nil {
-			return fmt.Errorf("failed to parse file: %w", err)
-		}
-
+	err := WalkGoFiles(repoPath, opts, func(path string, file *ast.File, fileSet *token.FileSet) error {
 		stats := gocognit.ComplexityStats(file, fileSet, nil)
+		shapes := functionShapes(file, fileSet)
 		functions := make([]FunctionStat, 0, len(stats))
 
 		for _, stat := range stats {
@@ -43,13 +23,16 @@ func RunGocognit(repoPath string, opts *Options) ([]*FileStat, error) { //nolint
 				return fmt.Errorf("failed to get relative path: %w", err)
 			}
 
-			functions = append(functions, FunctionStat{
+			function := FunctionStat{
 				File:       filepath.Clean(relPath),
 				Package:    []string{stat.PkgName},
 				Name:       stat.FuncName,
 				Line:       stat.Pos.Line,
 				Complexity: stat.Complexity,
-			})
+			}
+			shapes[stat.Pos.Line].apply(&function)
+
+			functions = append(functions, function)
 		}
 
 		if len(functions) > 0 {
@@ -2,33 +2,31 @@ package complexity
 
 import (
 	"fmt"
+	"go/ast"
+	"go/token"
 	"path/filepath"
 
 	"github.com/fzipp/gocyclo"
 )
 
 func RunGocyclo(repoPath string, opts *Options) ([]*FileStat, error) {
-	paths := []string{repoPath}
-	stats := gocyclo.Analyze(paths, opts.ExcludeRegex)
-
 	result := make([]*FileStat, 0)
 	fileMap := make(map[string][]FunctionStat)
 
-	for _, stat := range stats {
-		relPath, err := filepath.Rel(repoPath, stat.Pos.Filename)
+	err := WalkGoFiles(repoPath, opts, func(_ string, file *ast.File, fileSet *token.FileSet) error {
+		functions, err := analyzeGocycloFile(repoPath, file, fileSet)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get relative path: %w", err)
+			return err
 		}
 
-		funcStat := FunctionStat{
-			File:       relPath,
-			Package:    []string{stat.PkgName},
-			Name:       stat.FuncName,
-			Line:       stat.Pos.Line,
-			Complexity: stat.Complexity,
+		if len(functions) > 0 {
+			fileMap[functions[0].File] = functions
 		}
 
-		fileMap[relPath] = append(fileMap[relPath], funcStat)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk repository: %w", err)
 	}
 
 	for filePath, functions := range fileMap {
@@ -42,3 +40,29 @@ func RunGocyclo(repoPath string, opts *Options) ([]*FileStat, error) {
 
 	return result, nil
 }
+
+func analyzeGocycloFile(repoPath string, file *ast.File, fileSet *token.FileSet) ([]FunctionStat, error) {
+	shapes := functionShapes(file, fileSet)
+	stats := gocyclo.AnalyzeASTFile(file, fileSet, nil)
+	functions := make([]FunctionStat, 0, len(stats))
+
+	for _, stat := range stats {
+		relPath, err := filepath.Rel(repoPath, stat.Pos.Filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get relative path: %w", err)
+		}
+
+		function := FunctionStat{
+			File:       relPath,
+			Package:    []string{stat.PkgName},
+			Name:       stat.FuncName,
+			Line:       stat.Pos.Line,
+			Complexity: stat.Complexity,
+		}
+		shapes[stat.Pos.Line].apply(&function)
+
+		functions = append(functions, function)
+	}
+
+	return functions, nil
+}
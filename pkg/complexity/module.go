@@ -0,0 +1,29 @@
+package complexity
+
+import "github.com/vbvictor/grit/pkg/module"
+
+// RollupByModule merges files into one entry per module named after the module path,
+// module complexity is the average complexity of all its functions.
+func RollupByModule(files []*FileStat, modules module.Modules) []*FileStat {
+	return Rollup(files, modules.Name)
+}
+
+// Rollup collects functions of files sharing a group, e.g. package directory, into one entry per group,
+// so that group complexity is averaged over functions rather than files.
+func Rollup(files []*FileStat, group func(path string) string) []*FileStat {
+	groups, names := module.GroupBy(files, func(file *FileStat) string { return group(file.Path) })
+	result := make([]*FileStat, 0, len(names))
+
+	for _, name := range names {
+		rollup := &FileStat{Path: name}
+		for _, file := range groups[name] {
+			rollup.Functions = append(rollup.Functions, file.Functions...)
+		}
+
+		result = append(result, rollup)
+	}
+
+	AvgComplexity(result)
+
+	return result
+}
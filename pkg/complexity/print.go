@@ -2,8 +2,11 @@ package complexity
 
 import (
 	"encoding/csv"
+	"fmt"
 	"io"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/bndr/gotabulate"
 )
@@ -37,3 +40,154 @@ func PrintCSV(results []*FileStat, out io.Writer) {
 		_ = writer.Write(record)
 	}
 }
+
+// PrintFunctionsTabular prints functions, coverage column is added when coverage of any function is known.
+func PrintFunctionsTabular(results []FunctionStat, out io.Writer) {
+	_, _ = io.WriteString(out, "\nFunction complexity analysis results:\n")
+
+	// gotabulate can't render a table without rows
+	if len(results) == 0 {
+		_, _ = io.WriteString(out, "No functions found\n")
+
+		return
+	}
+
+	withCoverage := slices.ContainsFunc(results, FunctionStat.HasCoverage)
+
+	data := make([][]any, len(results))
+	for i, result := range results {
+		data[i] = []any{
+			result.File, result.Line, strings.Join(result.Package, "."), result.Name, result.Length, result.Complexity,
+			result.Nesting, result.Params, result.Results, result.Returns, result.Closures,
+		}
+
+		if withCoverage {
+			data[i] = append(data[i], formatCoverage(result.Coverage, "%.2f%%", "-"))
+		}
+	}
+
+	headers := []string{
+		"FILEPATH", "LINE", "PACKAGE", "FUNCTION", "LENGTH", "COMPLEXITY", "NESTING", "PARAMS", "RESULTS", "RETURNS",
+		"CLOSURES",
+	}
+	if withCoverage {
+		headers = append(headers, "COVERAGE")
+	}
+
+	table := gotabulate.Create(data)
+	table.SetHeaders(headers)
+	table.SetAlign("left")
+
+	_, _ = io.WriteString(out, table.Render("grid"))
+}
+
+// PrintFunctionsCSV prints functions, coverage column is added when coverage of any function is known.
+func PrintFunctionsCSV(results []FunctionStat, out io.Writer) {
+	writer := csv.NewWriter(out)
+	defer writer.Flush()
+
+	withCoverage := slices.ContainsFunc(results, FunctionStat.HasCoverage)
+
+	headers := []string{
+		"FILEPATH", "LINE", "PACKAGE", "FUNCTION", "LENGTH", "COMPLEXITY", "NESTING", "PARAMS", "RESULTS", "RETURNS",
+		"CLOSURES",
+	}
+	if withCoverage {
+		headers = append(headers, "COVERAGE")
+	}
+
+	_ = writer.Write(headers)
+
+	for _, result := range results {
+		record := []string{
+			result.File,
+			strconv.Itoa(result.Line),
+			strings.Join(result.Package, ";"),
+			result.Name,
+			strconv.Itoa(result.Length),
+			strconv.Itoa(result.Complexity),
+			strconv.Itoa(result.Nesting),
+			strconv.Itoa(result.Params),
+			strconv.Itoa(result.Results),
+			strconv.Itoa(result.Returns),
+			strconv.Itoa(result.Closures),
+		}
+
+		if withCoverage {
+			record = append(record, formatCoverage(result.Coverage, "%.2f", ""))
+		}
+
+		_ = writer.Write(record)
+	}
+}
+
+// formatCoverage returns missing value when coverage of a function is unknown.
+func formatCoverage(coverage *float64, format, missing string) string {
+	if coverage == nil {
+		return missing
+	}
+
+	return fmt.Sprintf(format, *coverage)
+}
+
+func PrintDiffTabular(results []FunctionDiff, out io.Writer) {
+	_, _ = io.WriteString(out, "\nFunction complexity changes:\n")
+
+	if len(results) == 0 {
+		_, _ = io.WriteString(out, "No complexity changes\n")
+
+		return
+	}
+
+	data := make([][]any, len(results))
+	for i, result := range results {
+		data[i] = []any{
+			result.Change, result.File, result.Line, functionName(result.Package, result.Name),
+			result.BaseComplexity, result.HeadComplexity, formatDelta(result.Delta()),
+		}
+	}
+
+	table := gotabulate.Create(data)
+	table.SetHeaders([]string{"CHANGE", "FILEPATH", "LINE", "FUNCTION", "BASE", "HEAD", "DELTA"})
+	table.SetAlign("left")
+
+	_, _ = io.WriteString(out, table.Render("grid"))
+}
+
+func PrintDiffCSV(results []FunctionDiff, out io.Writer) {
+	writer := csv.NewWriter(out)
+	defer writer.Flush()
+
+	_ = writer.Write([]string{"CHANGE", "FILEPATH", "LINE", "PACKAGE", "FUNCTION", "BASE", "HEAD", "DELTA"})
+
+	for _, result := range results {
+		record := []string{
+			result.Change,
+			result.File,
+			strconv.Itoa(result.Line),
+			strings.Join(result.Package, ";"),
+			result.Name,
+			strconv.Itoa(result.BaseComplexity),
+			strconv.Itoa(result.HeadComplexity),
+			strconv.Itoa(result.Delta()),
+		}
+		_ = writer.Write(record)
+	}
+}
+
+func formatDelta(delta int) string {
+	if delta > 0 {
+		return "+" + strconv.Itoa(delta)
+	}
+
+	return strconv.Itoa(delta)
+}
+
+// functionName returns function name qualified with its package.
+func functionName(packages []string, name string) string {
+	if len(packages) == 0 {
+		return name
+	}
+
+	return strings.Join(packages, ".") + "." + name
+}
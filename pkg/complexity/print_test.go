@@ -127,3 +127,72 @@ func TestPrintCSV(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintFunctionsTabular(t *testing.T) {
+	var buf bytes.Buffer
+
+	PrintFunctionsTabular([]FunctionStat{
+		{File: "main.go", Line: 12, Package: []string{"main"}, Name: "run", Length: 30, Complexity: 9},
+	}, &buf)
+
+	output := buf.String()
+	for _, exp := range []string{"FUNCTION", "main.go", "12", "main", "run", "30", "9"} {
+		assert.Contains(t, output, exp, "Expected output to contain %q", exp)
+	}
+}
+
+func TestPrintFunctionsTabularEmpty(t *testing.T) {
+	var buf bytes.Buffer
+
+	PrintFunctionsTabular(nil, &buf)
+
+	assert.Equal(t, "\nFunction complexity analysis results:\nNo functions found\n", buf.String())
+}
+
+func TestPrintFunctionsCSV(t *testing.T) {
+	var buf bytes.Buffer
+
+	PrintFunctionsCSV([]FunctionStat{
+		{
+			File: "main.go", Line: 12, Package: []string{"pkg1", "pkg2"}, Name: "run", Length: 30, Complexity: 9,
+			Nesting: 3, Params: 2, Results: 1, Returns: 4, Closures: 1,
+		},
+		{File: "util.go", Line: 3, Name: "helper", Complexity: 1},
+	}, &buf)
+
+	reader := csv.NewReader(bytes.NewReader(buf.Bytes()))
+	output, err := reader.ReadAll()
+	require.NoError(t, err, "Failed to parse CSV output")
+
+	assert.Equal(t, [][]string{
+		{
+			"FILEPATH", "LINE", "PACKAGE", "FUNCTION", "LENGTH", "COMPLEXITY", "NESTING", "PARAMS", "RESULTS", "RETURNS",
+			"CLOSURES",
+		},
+		{"main.go", "12", "pkg1;pkg2", "run", "30", "9", "3", "2", "1", "4", "1"},
+		{"util.go", "3", "", "helper", "0", "1", "0", "0", "0", "0", "0"},
+	}, output)
+}
+
+func TestPrintFunctionsCoverage(t *testing.T) {
+	coverage := 62.5
+	functions := []FunctionStat{
+		{File: "main.go", Line: 12, Name: "run", Complexity: 9, Coverage: &coverage},
+		{File: "util.go", Line: 3, Name: "helper", Complexity: 1},
+	}
+
+	var buf bytes.Buffer
+
+	PrintFunctionsTabular(functions, &buf)
+	assert.Contains(t, buf.String(), "COVERAGE")
+	assert.Contains(t, buf.String(), "62.50%")
+
+	buf.Reset()
+	PrintFunctionsCSV(functions, &buf)
+
+	output, err := csv.NewReader(bytes.NewReader(buf.Bytes())).ReadAll()
+	require.NoError(t, err, "Failed to parse CSV output")
+
+	assert.Equal(t, []string{"main.go", "12", "", "run", "0", "9", "0", "0", "0", "0", "0", "62.50"}, output[1])
+	assert.Equal(t, []string{"util.go", "3", "", "helper", "0", "1", "0", "0", "0", "0", "0", ""}, output[2])
+}
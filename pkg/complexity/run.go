@@ -3,9 +3,12 @@ package complexity
 import (
 	"errors"
 	"fmt"
-	"path/filepath"
+	"go/build"
 	"regexp"
 	"slices"
+	"strings"
+
+	"github.com/vbvictor/grit/pkg/testfiles"
 )
 
 type Engine = string
@@ -16,6 +19,18 @@ const (
 	CSV      = "csv-file"
 )
 
+type SortType = string
+
+// Function metrics functions can be sorted by.
+const (
+	ByComplexity SortType = "complexity"
+	ByNesting    SortType = "nesting"
+	ByParams     SortType = "params"
+	ByLength     SortType = "length"
+)
+
+var AvailableSorts = []SortType{ByComplexity, ByNesting, ByParams, ByLength}
+
 type FileStat struct {
 	Path          string
 	Functions     []FunctionStat
@@ -29,16 +44,57 @@ type FunctionStat struct {
 	Line       int
 	Length     int
 	Complexity int
+	// Nesting is the deepest nesting of control flow statements.
+	Nesting int
+	Params  int
+	Results int
+	// Returns is the number of return statements, returns of closures are not counted.
+	Returns  int
+	Closures int
+	// Coverage is the percentage of covered statements, nil when coverage of the function is unknown.
+	Coverage *float64
+}
+
+// HasCoverage reports whether coverage of the function is known.
+func (f FunctionStat) HasCoverage() bool {
+	return f.Coverage != nil
 }
 
 type Options struct {
-	Engine       string
-	ExcludeRegex *regexp.Regexp
-	Top          int
-	OutputFormat string
+	Engine        string
+	ExcludeRegex  *regexp.Regexp
+	Top           int
+	OutputFormat  string
+	PerFunction   bool
+	MinComplexity int
+	// SortBy is metric functions are sorted by, files are always sorted by average complexity.
+	SortBy SortType
+	// CSV files or glob patterns read by CSV engine, relative to analyzed path.
+	ComplexityFiles []string
+	// CSV column mapping in 'column=header' or 'column=number' form.
+	CSVColumns []string
+	// External engines in 'name=command' form, registered by PopulateOpts.
+	ExternalEngines []string
+	// Go engines skip generated files, DefaultExcludeDirs and files ignored by git unless told otherwise.
+	IncludeGenerated       bool
+	IncludeDefaultExcludes bool
+	NoGitignore            bool
+	Tests                  testfiles.Filter
+	// Build constraints Go engines evaluate files against, files of all platforms and tags are analyzed if none is set.
+	BuildTags []string
+	GOOS      string
+	GOARCH    string
+	// BuildContext is created from build constraints by PopulateOpts.
+	BuildContext *build.Context
+	// OnWarning is called for every file that Go engines failed to analyze.
+	OnWarning func(Warning)
 }
 
-var ErrUnsupportedEngine = errors.New("unsupported complexity engine")
+var (
+	ErrUnsupportedEngine     = errors.New("unsupported complexity engine")
+	ErrUnsupportedSort       = errors.New("unsupported sort type")
+	ErrInvalidExternalEngine = errors.New("invalid external complexity engine")
+)
 
 func PopulateOpts(opts *Options, excludeRegex string) error {
 	if excludeRegex != "" {
@@ -50,20 +106,36 @@ func PopulateOpts(opts *Options, excludeRegex string) error {
 		}
 	}
 
+	if err := opts.Tests.Compile(); err != nil {
+		return fmt.Errorf("invalid tests option: %w", err)
+	}
+
+	if opts.SortBy == "" {
+		opts.SortBy = ByComplexity
+	} else if !slices.Contains(AvailableSorts, opts.SortBy) {
+		return fmt.Errorf("%w: %s, expected one of %v", ErrUnsupportedSort, opts.SortBy, AvailableSorts)
+	}
+
+	if _, err := parseCSVMapping(opts.CSVColumns); err != nil {
+		return fmt.Errorf("invalid CSV columns: %w", err)
+	}
+
+	opts.BuildContext = NewBuildContext(opts)
+
+	if err := RegisterExternalEngines(opts.ExternalEngines); err != nil {
+		return fmt.Errorf("invalid external engine: %w", err)
+	}
+
 	return nil
 }
 
 func RunComplexity(repoPath string, opts *Options) ([]*FileStat, error) {
-	switch opts.Engine {
-	case Gocyclo:
-		return RunGocyclo(repoPath, opts)
-	case Gocognit:
-		return RunGocognit(repoPath, opts)
-	case CSV:
-		return RunCSV(filepath.Join(repoPath, "complexity.csv"), opts)
-	default:
-		return nil, ErrUnsupportedEngine
+	run, ok := LookupEngine(opts.Engine)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedEngine, opts.Engine)
 	}
+
+	return run(repoPath, opts)
 }
 
 func SortAndLimit(fileStat []*FileStat, opts Options) []*FileStat {
@@ -86,6 +158,60 @@ func SortAndLimit(fileStat []*FileStat, opts Options) []*FileStat {
 	return fileStat
 }
 
+// TopFunctions flattens functions of all files, drops the ones below opts.MinComplexity
+// and returns the opts.Top functions with the highest opts.SortBy metric.
+func TopFunctions(files []*FileStat, opts Options) []FunctionStat {
+	fileValues := make([]FileStat, 0, len(files))
+	for _, file := range files {
+		fileValues = append(fileValues, *file)
+	}
+
+	filters := make([]FilesFilterFunc, 0)
+	if opts.MinComplexity > 0 {
+		filters = append(filters, MinComplexityFilter{MinComplexity: opts.MinComplexity}.Filter)
+	}
+
+	functions := make([]FunctionStat, 0)
+	for _, file := range ApplyFilters(fileValues, filters...) {
+		functions = append(functions, file.Functions...)
+	}
+
+	slices.SortStableFunc(functions, func(a, b FunctionStat) int {
+		if metric := sortMetric(b, opts.SortBy) - sortMetric(a, opts.SortBy); metric != 0 {
+			return metric
+		}
+
+		if a.Complexity != b.Complexity {
+			return b.Complexity - a.Complexity
+		}
+
+		if a.File != b.File {
+			return strings.Compare(a.File, b.File)
+		}
+
+		return a.Line - b.Line
+	})
+
+	if opts.Top > 0 && opts.Top < len(functions) {
+		return functions[:opts.Top]
+	}
+
+	return functions
+}
+
+func sortMetric(fn FunctionStat, sortBy SortType) int {
+	switch sortBy {
+	case ByNesting:
+		return fn.Nesting
+	case ByParams:
+		return fn.Params
+	case ByLength:
+		return fn.Length
+	default:
+		return fn.Complexity
+	}
+}
+
 func AvgComplexity(files []*FileStat) {
 	for _, file := range files {
 		if len(file.Functions) == 0 {
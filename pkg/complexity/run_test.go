@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vbvictor/grit/pkg/module"
 )
 
 func TestAvgComplexity(t *testing.T) {
@@ -217,3 +219,99 @@ func TestApplyFilters(t *testing.T) {
 		})
 	}
 }
+
+func TestTopFunctions(t *testing.T) {
+	files := []*FileStat{
+		{
+			Path: "file1.go",
+			Functions: []FunctionStat{
+				{File: "file1.go", Name: "func1", Line: 3, Complexity: 5, Nesting: 4, Params: 1},
+				{File: "file1.go", Name: "func2", Line: 10, Complexity: 12, Nesting: 2, Params: 3},
+			},
+		},
+		{
+			Path: "file2.go",
+			Functions: []FunctionStat{
+				{File: "file2.go", Name: "func3", Line: 7, Complexity: 12},
+				{File: "file2.go", Name: "func4", Line: 20, Complexity: 1},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		opts      Options
+		wantNames []string
+	}{
+		{
+			name:      "all functions sorted by complexity",
+			opts:      Options{},
+			wantNames: []string{"func2", "func3", "func1", "func4"},
+		},
+		{
+			name:      "limit to top",
+			opts:      Options{Top: 2},
+			wantNames: []string{"func2", "func3"},
+		},
+		{
+			name:      "min complexity filter",
+			opts:      Options{MinComplexity: 5},
+			wantNames: []string{"func2", "func3", "func1"},
+		},
+		{
+			name:      "min complexity filter removes everything",
+			opts:      Options{MinComplexity: 100},
+			wantNames: []string{},
+		},
+		{
+			name:      "sorted by nesting",
+			opts:      Options{SortBy: ByNesting},
+			wantNames: []string{"func1", "func2", "func3", "func4"},
+		},
+		{
+			name:      "sorted by params",
+			opts:      Options{SortBy: ByParams, Top: 1},
+			wantNames: []string{"func2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TopFunctions(files, tt.opts)
+
+			names := make([]string, 0, len(got))
+			for _, fn := range got {
+				names = append(names, fn.Name)
+			}
+
+			assert.Equal(t, tt.wantNames, names)
+		})
+	}
+}
+
+func TestRollupByModule(t *testing.T) {
+	modules := module.Modules{{Path: "example.com/root", Dir: "."}, {Path: "example.com/root/tools", Dir: "tools"}}
+	files := []*FileStat{
+		{Path: "main.go", Functions: []FunctionStat{{Name: "main", Complexity: 2}}},
+		{Path: "tools/gen.go", Functions: []FunctionStat{{Name: "gen", Complexity: 6}}},
+		{Path: "pkg/a.go", Functions: []FunctionStat{{Name: "a", Complexity: 4}}},
+	}
+
+	result := RollupByModule(files, modules)
+
+	require.Len(t, result, 2)
+	assert.Equal(t, "example.com/root", result[0].Path)
+	assert.Len(t, result[0].Functions, 2)
+	assert.InDelta(t, 3.0, result[0].AvgComplexity, 0.0001)
+	assert.Equal(t, "example.com/root/tools", result[1].Path)
+	assert.InDelta(t, 6.0, result[1].AvgComplexity, 0.0001)
+}
+
+func TestPopulateOptsSort(t *testing.T) {
+	opts := &Options{Engine: Gocyclo}
+	require.NoError(t, PopulateOpts(opts, ""))
+	assert.Equal(t, ByComplexity, opts.SortBy)
+
+	err := PopulateOpts(&Options{Engine: Gocyclo, SortBy: "unknown"}, "")
+	require.ErrorIs(t, err, ErrUnsupportedSort)
+}
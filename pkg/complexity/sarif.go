@@ -0,0 +1,67 @@
+package complexity
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/vbvictor/grit/pkg/sarif"
+)
+
+// DefaultSARIFLevels follow common interpretation of cyclomatic complexity:
+// functions above 10 are moderately complex and above 20 are hard to test.
+var DefaultSARIFLevels = sarif.Levels{Warning: 11, Error: 21} //nolint:mnd // complexity bands
+
+var engineDescriptions = map[Engine]string{
+	Gocyclo:  "Cyclomatic complexity of a function",
+	Gocognit: "Cognitive complexity of a function",
+}
+
+// SARIFRule describes complexity metric calculated by engine.
+func SARIFRule(engine Engine) sarif.Rule {
+	description, ok := engineDescriptions[engine]
+	if !ok {
+		description = fmt.Sprintf("Complexity of a function calculated by %s engine", engine)
+	}
+
+	return sarif.Rule{
+		ID:               "function-complexity/" + engine,
+		Name:             "FunctionComplexity",
+		ShortDescription: sarif.Message{Text: description},
+	}
+}
+
+// PrintSARIF reports every function with complexity at least opts.MinComplexity as a SARIF result,
+// levels.Warning is used as the threshold when opts.MinComplexity is not set.
+// Unlike other formats all matching functions are reported regardless of opts.Top.
+func PrintSARIF(results []*FileStat, out io.Writer, root string, opts *Options, levels sarif.Levels) error {
+	filterOpts := *opts
+	filterOpts.Top = 0
+
+	if filterOpts.MinComplexity == 0 {
+		filterOpts.MinComplexity = int(levels.Warning)
+	}
+
+	rule := SARIFRule(opts.Engine)
+	functions := TopFunctions(results, filterOpts)
+	sarifResults := make([]sarif.Result, 0, len(functions))
+
+	for _, fn := range functions {
+		sarifResults = append(sarifResults, sarif.Result{
+			RuleID: rule.ID,
+			Level:  levels.Level(float64(fn.Complexity)),
+			Message: sarif.Message{
+				Text: fmt.Sprintf("Function %s has %s complexity %d",
+					functionName(fn.Package, fn.Name), opts.Engine, fn.Complexity),
+			},
+			Locations:  []sarif.Location{sarif.NewLocation(fn.File, fn.Line, fn.Length)},
+			Properties: map[string]float64{"complexity": float64(fn.Complexity), "length": float64(fn.Length)},
+		})
+	}
+
+	log, err := sarif.NewLog(root, []sarif.Rule{rule}, sarifResults)
+	if err != nil {
+		return err
+	}
+
+	return sarif.Write(log, out)
+}
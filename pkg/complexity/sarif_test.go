@@ -0,0 +1,51 @@
+package complexity
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vbvictor/grit/pkg/sarif"
+)
+
+func TestPrintSARIF(t *testing.T) {
+	files := []*FileStat{
+		{
+			Path: "pkg/run.go",
+			Functions: []FunctionStat{
+				{File: "pkg/run.go", Package: []string{"pkg"}, Name: "Simple", Line: 3, Length: 5, Complexity: 2},
+				{File: "pkg/run.go", Package: []string{"pkg"}, Name: "Moderate", Line: 10, Length: 20, Complexity: 12},
+				{File: "pkg/run.go", Package: []string{"pkg"}, Name: "Complex", Line: 40, Length: 60, Complexity: 25},
+			},
+		},
+	}
+	levels := sarif.Levels{Warning: 10, Error: 20}
+
+	t.Run("warning level is default threshold", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		require.NoError(t, PrintSARIF(files, &buf, ".", &Options{Engine: Gocyclo, Top: 1}, levels))
+
+		output := buf.String()
+		assert.Contains(t, output, `"id": "function-complexity/gocyclo"`)
+		assert.Contains(t, output, `"text": "Function pkg.Complex has gocyclo complexity 25"`)
+		assert.Contains(t, output, `"level": "error"`)
+		assert.Contains(t, output, `"text": "Function pkg.Moderate has gocyclo complexity 12"`)
+		assert.Contains(t, output, `"level": "warning"`)
+		assert.Contains(t, output, `"startLine": 10`)
+		assert.Contains(t, output, `"endLine": 29`)
+		assert.NotContains(t, output, "Simple")
+	})
+
+	t.Run("min complexity overrides threshold", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		require.NoError(t, PrintSARIF(files, &buf, ".", &Options{Engine: "lizard", MinComplexity: 1}, levels))
+
+		output := buf.String()
+		assert.Contains(t, output, `"text": "Complexity of a function calculated by lizard engine"`)
+		assert.Contains(t, output, `"text": "Function pkg.Simple has lizard complexity 2"`)
+		assert.Contains(t, output, `"level": "note"`)
+	})
+}
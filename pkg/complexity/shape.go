@@ -0,0 +1,162 @@
+package complexity
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// functionShape holds metrics of function structure that Go engines report in addition to complexity.
+type functionShape struct {
+	Length   int
+	Nesting  int
+	Params   int
+	Results  int
+	Returns  int
+	Closures int
+}
+
+func (s functionShape) apply(fn *FunctionStat) {
+	fn.Length = s.Length
+	fn.Nesting = s.Nesting
+	fn.Params = s.Params
+	fn.Results = s.Results
+	fn.Returns = s.Returns
+	fn.Closures = s.Closures
+}
+
+// functionShapes maps the start line of every top-level function in a file to its shape.
+// Function literals assigned to package level variables are included, the same way engines report them.
+func functionShapes(file *ast.File, fileSet *token.FileSet) map[int]functionShape {
+	shapes := make(map[int]functionShape)
+
+	addShape := func(node ast.Node, funcType *ast.FuncType, body *ast.BlockStmt) {
+		start := fileSet.Position(node.Pos()).Line
+		shape := functionShape{
+			Length:  fileSet.Position(node.End()).Line - start + 1,
+			Params:  countFields(funcType.Params),
+			Results: countFields(funcType.Results),
+		}
+
+		if body != nil {
+			shape.Nesting = maxNesting(body)
+			shape.Returns, shape.Closures = countReturnsAndClosures(body)
+		}
+
+		shapes[start] = shape
+	}
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			addShape(decl, decl.Type, decl.Body)
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				valueSpec, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+
+				for _, value := range valueSpec.Values {
+					if funcLit, ok := value.(*ast.FuncLit); ok {
+						addShape(funcLit, funcLit.Type, funcLit.Body)
+					}
+				}
+			}
+		}
+	}
+
+	return shapes
+}
+
+// countFields counts parameters or results, every name of 'a, b int' is counted separately.
+func countFields(fields *ast.FieldList) int {
+	if fields == nil {
+		return 0
+	}
+
+	count := 0
+
+	for _, field := range fields.List {
+		count += max(len(field.Names), 1)
+	}
+
+	return count
+}
+
+// maxNesting returns the deepest nesting of if, for, switch and select statements in body.
+// Else-if chains don't increase nesting, statements in closures continue nesting of the enclosing function.
+func maxNesting(body *ast.BlockStmt) int {
+	deepest := 0
+
+	var walk func(node ast.Node, depth int)
+
+	var walkIf func(stmt *ast.IfStmt, depth int)
+
+	walkIf = func(stmt *ast.IfStmt, depth int) {
+		deepest = max(deepest, depth)
+		walk(stmt.Body, depth)
+
+		switch elseStmt := stmt.Else.(type) {
+		case *ast.IfStmt:
+			walkIf(elseStmt, depth)
+		case *ast.BlockStmt:
+			walk(elseStmt, depth)
+		}
+	}
+
+	walk = func(root ast.Node, depth int) {
+		ast.Inspect(root, func(node ast.Node) bool {
+			if node == root {
+				return true
+			}
+
+			switch node := node.(type) {
+			case *ast.IfStmt:
+				walkIf(node, depth+1)
+
+				return false
+			case *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+				deepest = max(deepest, depth+1)
+				walk(node, depth+1)
+
+				return false
+			}
+
+			return true
+		})
+	}
+
+	walk(body, 0)
+
+	return deepest
+}
+
+// countReturnsAndClosures counts return statements of the function itself and function literals declared in body.
+func countReturnsAndClosures(body *ast.BlockStmt) (int, int) {
+	returns, closures := 0, 0
+
+	var walk func(root ast.Node, ownReturns bool)
+
+	walk = func(root ast.Node, ownReturns bool) {
+		ast.Inspect(root, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.ReturnStmt:
+				if ownReturns {
+					returns++
+				}
+			case *ast.FuncLit:
+				closures++
+				// Returns of closure exit the closure, not the function.
+				walk(node.Body, false)
+
+				return false
+			}
+
+			return true
+		})
+	}
+
+	walk(body, true)
+
+	return returns, closures
+}
@@ -0,0 +1,77 @@
+package complexity
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFunctionShapes(t *testing.T) {
+	src := `package main
+
+func short() {}
+
+func long(a int) int {
+	if a > 0 {
+		return a
+	}
+
+	return -a
+}
+
+var handler = func() {
+	println("handler")
+}
+
+var value = 42
+`
+
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, "main.go", src, parser.ParseComments)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[int]functionShape{
+		3:  {Length: 1},
+		5:  {Length: 7, Nesting: 1, Params: 1, Results: 1, Returns: 2},
+		13: {Length: 3},
+	}, functionShapes(file, fileSet))
+}
+
+func TestFunctionShapesNesting(t *testing.T) {
+	src := `package main
+
+func (s *server) handle(a, b int, _ string, opts ...int) (result int, err error) {
+	for i := range a {
+		if i > b {
+			continue
+		} else if i < 0 {
+			switch {
+			case i == -1:
+				return 0, nil
+			}
+		} else {
+			go func() error {
+				select {
+				default:
+					return nil
+				}
+			}()
+		}
+	}
+
+	defer func() {}()
+
+	return a, nil
+}
+`
+
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, "main.go", src, parser.ParseComments)
+	require.NoError(t, err)
+
+	assert.Equal(t, functionShape{Length: 23, Nesting: 3, Params: 4, Results: 2, Returns: 2, Closures: 2},
+		functionShapes(file, fileSet)[3])
+}
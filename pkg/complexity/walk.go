@@ -0,0 +1,208 @@
+package complexity
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// DefaultExcludeDirs are directories that never contain code worth analyzing.
+// Directories starting with '.' or '_' are skipped as well, the same way go tool does.
+var DefaultExcludeDirs = []string{"vendor", "testdata", "node_modules"}
+
+// Warning describes a file that was skipped because it could not be analyzed.
+type Warning struct {
+	Path string
+	Err  error
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("skipped %s: %v", w.Path, w.Err)
+}
+
+func (opts *Options) warn(path string, err error) {
+	if opts.OnWarning != nil {
+		opts.OnWarning(Warning{Path: path, Err: err})
+	}
+}
+
+// GoFileFunc is called with every parsed Go file, path is the file path under analyzed repository.
+type GoFileFunc func(path string, file *ast.File, fileSet *token.FileSet) error
+
+// WalkGoFiles parses every Go file under repoPath that is not excluded by opts and calls fn for it.
+// Files are selected the same way for all Go engines and metrics, so that they analyze the same files.
+// Files that fail to parse are reported as warnings and skipped.
+func WalkGoFiles(repoPath string, opts *Options, fn GoFileFunc) error {
+	ignored := gitIgnored(repoPath, opts)
+
+	return filepath.WalkDir(repoPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to walk path: %w", err)
+		}
+
+		if path == repoPath {
+			return nil
+		}
+
+		if entry.IsDir() {
+			if isExcludedDir(entry.Name(), opts) || ignored.dir(repoPath, path) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !isSelectedFile(repoPath, path, ignored, opts) {
+			return nil
+		}
+
+		return parseGoFile(path, opts, fn)
+	})
+}
+
+// isSelectedFile reports whether file at path is a Go file that is neither ignored, excluded by opts
+// nor built for another build context.
+func isSelectedFile(repoPath, path string, ignored ignoredPaths, opts *Options) bool {
+	return strings.HasSuffix(path, ".go") && !ignored.file(repoPath, path) && !isSkippedTest(repoPath, path, opts) &&
+		(opts.ExcludeRegex == nil || !opts.ExcludeRegex.MatchString(path)) && matchesBuildContext(path, opts)
+}
+
+// parseGoFile parses file at path and calls fn for it unless it's generated and opts skip generated files.
+func parseGoFile(path string, opts *Options, fn GoFileFunc) error {
+	fileSet := token.NewFileSet()
+
+	file, err := parser.ParseFile(fileSet, path, nil, parser.ParseComments)
+	if err != nil {
+		opts.warn(path, fmt.Errorf("failed to parse file: %w", err))
+
+		return nil
+	}
+
+	if !opts.IncludeGenerated && ast.IsGenerated(file) {
+		return nil
+	}
+
+	return fn(path, file, fileSet)
+}
+
+// NewBuildContext creates build context from opts.BuildTags, opts.GOOS and opts.GOARCH,
+// nil context is returned if none of them is set, meaning that files of all contexts are analyzed.
+// Unset GOOS and GOARCH default to the current platform, cgo is disabled for other platforms as go build does.
+func NewBuildContext(opts *Options) *build.Context {
+	if len(opts.BuildTags) == 0 && opts.GOOS == "" && opts.GOARCH == "" {
+		return nil
+	}
+
+	ctx := build.Default
+	ctx.BuildTags = opts.BuildTags
+
+	if opts.GOOS != "" {
+		ctx.GOOS = opts.GOOS
+	}
+
+	if opts.GOARCH != "" {
+		ctx.GOARCH = opts.GOARCH
+	}
+
+	if ctx.GOOS != build.Default.GOOS || ctx.GOARCH != build.Default.GOARCH {
+		ctx.CgoEnabled = false
+	}
+
+	return &ctx
+}
+
+// matchesBuildContext evaluates file name suffixes and '//go:build' constraints against opts.BuildContext.
+func matchesBuildContext(path string, opts *Options) bool {
+	if opts.BuildContext == nil {
+		return true
+	}
+
+	match, err := opts.BuildContext.MatchFile(filepath.Dir(path), filepath.Base(path))
+	if err != nil {
+		opts.warn(path, fmt.Errorf("failed to evaluate build constraints: %w", err))
+
+		return false
+	}
+
+	return match
+}
+
+// isSkippedTest matches test patterns against repository relative path.
+func isSkippedTest(repoPath, path string, opts *Options) bool {
+	relPath, err := filepath.Rel(repoPath, path)
+	if err != nil {
+		relPath = path
+	}
+
+	return opts.Tests.Skip(relPath)
+}
+
+func isExcludedDir(name string, opts *Options) bool {
+	if opts.IncludeDefaultExcludes {
+		return false
+	}
+
+	return slices.Contains(DefaultExcludeDirs, name) ||
+		(strings.HasPrefix(name, ".") && name != "." && name != "..") ||
+		strings.HasPrefix(name, "_")
+}
+
+// ignoredPaths holds repository relative paths ignored by git, directories end with '/'.
+type ignoredPaths map[string]struct{}
+
+func (ip ignoredPaths) contains(repoPath, path, suffix string) bool {
+	if len(ip) == 0 {
+		return false
+	}
+
+	relPath, err := filepath.Rel(repoPath, path)
+	if err != nil {
+		return false
+	}
+
+	_, ok := ip[filepath.ToSlash(relPath)+suffix]
+
+	return ok
+}
+
+func (ip ignoredPaths) dir(repoPath, path string) bool {
+	return ip.contains(repoPath, path, "/")
+}
+
+func (ip ignoredPaths) file(repoPath, path string) bool {
+	return ip.contains(repoPath, path, "")
+}
+
+// gitIgnored lists untracked paths ignored by .gitignore rules.
+// Outside of git repository nothing is considered ignored.
+func gitIgnored(repoPath string, opts *Options) ignoredPaths {
+	if opts.NoGitignore {
+		return nil
+	}
+
+	cmd := exec.Command("git", "ls-files", "-z", "--others", "--ignored", "--exclude-standard", "--directory")
+	cmd.Dir = repoPath
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	ignored := make(ignoredPaths)
+
+	for _, path := range bytes.Split(output, []byte{0}) {
+		if len(path) > 0 {
+			ignored[string(path)] = struct{}{}
+		}
+	}
+
+	return ignored
+}
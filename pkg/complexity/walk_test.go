@@ -0,0 +1,179 @@
+package complexity
+
+import (
+	"go/ast"
+	"go/build"
+	"go/token"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vbvictor/grit/internal/testutil"
+	"github.com/vbvictor/grit/pkg/testfiles"
+)
+
+func walkedFiles(t *testing.T, root string, opts *Options) []string {
+	t.Helper()
+
+	files := make([]string, 0)
+
+	err := WalkGoFiles(root, opts, func(path string, _ *ast.File, _ *token.FileSet) error {
+		relPath, err := filepath.Rel(root, path)
+		require.NoError(t, err)
+
+		files = append(files, filepath.ToSlash(relPath))
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	sort.Strings(files)
+
+	return files
+}
+
+func TestWalkGoFiles(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteFiles(t, root, map[string]string{
+		"main.go":             "package main\n\nfunc main() {}\n",
+		"api/api.pb.go":       "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage api\n\nfunc Get() {}\n",
+		"vendor/dep/dep.go":   "package dep\n\nfunc Dep() {}\n",
+		"testdata/data.go":    "package testdata\n",
+		".hidden/hidden.go":   "package hidden\n",
+		"broken/broken.go":    "package broken\n\nfunc {\n",
+		"build/out.go":        "package build\n",
+		"pkg/util/util.go":    "package util\n\nfunc Util() {}\n",
+		"pkg/util/README.txt": "not go",
+		".gitignore":          "build/\n",
+	})
+
+	cmd := exec.Command("git", "init", "-q")
+	cmd.Dir = root
+	require.NoError(t, cmd.Run())
+
+	t.Run("defaults", func(t *testing.T) {
+		warnings := make([]Warning, 0)
+		opts := &Options{OnWarning: func(w Warning) { warnings = append(warnings, w) }}
+
+		assert.Equal(t, []string{"main.go", "pkg/util/util.go"}, walkedFiles(t, root, opts))
+		require.Len(t, warnings, 1)
+		assert.Equal(t, filepath.Join(root, "broken", "broken.go"), warnings[0].Path)
+	})
+
+	t.Run("include everything", func(t *testing.T) {
+		opts := &Options{IncludeGenerated: true, IncludeDefaultExcludes: true, NoGitignore: true}
+
+		assert.Equal(t, []string{
+			".hidden/hidden.go", "api/api.pb.go", "build/out.go", "main.go",
+			"pkg/util/util.go", "testdata/data.go", "vendor/dep/dep.go",
+		}, walkedFiles(t, root, opts))
+	})
+}
+
+func TestWalkGoFilesTests(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteFiles(t, root, map[string]string{
+		"run.go":                    "package main\n",
+		"run_test.go":               "package main\n",
+		"internal/testutil/util.go": "package testutil\n",
+	})
+
+	opts := &Options{Tests: testfiles.Filter{Mode: testfiles.Only, Patterns: []string{"^internal/testutil/"}}}
+	require.NoError(t, opts.Tests.Compile())
+	assert.Equal(t, []string{"internal/testutil/util.go", "run_test.go"}, walkedFiles(t, root, opts))
+
+	opts.Tests.Mode = testfiles.Exclude
+	assert.Equal(t, []string{"run.go"}, walkedFiles(t, root, opts))
+}
+
+func TestWalkGoFilesBuildContext(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteFiles(t, root, map[string]string{
+		"common.go":          "package p\n",
+		"file_linux.go":      "package p\n",
+		"file_windows.go":    "package p\n",
+		"file_arm64.go":      "package p\n",
+		"integration.go":     "//go:build integration\n\npackage p\n",
+		"not_integration.go": "//go:build !integration && (linux || windows)\n\npackage p\n",
+	})
+
+	testCases := []struct {
+		name     string
+		opts     Options
+		expected []string
+	}{
+		{
+			name: "all contexts",
+			opts: Options{},
+			expected: []string{
+				"common.go", "file_arm64.go", "file_linux.go", "file_windows.go", "integration.go", "not_integration.go",
+			},
+		},
+		{
+			name:     "windows amd64",
+			opts:     Options{GOOS: "windows", GOARCH: "amd64"},
+			expected: []string{"common.go", "file_windows.go", "not_integration.go"},
+		},
+		{
+			name:     "linux arm64 with tags",
+			opts:     Options{GOOS: "linux", GOARCH: "arm64", BuildTags: []string{"integration"}},
+			expected: []string{"common.go", "file_arm64.go", "file_linux.go", "integration.go"},
+		},
+		{
+			name:     "darwin",
+			opts:     Options{GOOS: "darwin", GOARCH: "amd64"},
+			expected: []string{"common.go"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.BuildContext = NewBuildContext(&tc.opts)
+			assert.Equal(t, tc.expected, walkedFiles(t, root, &tc.opts))
+		})
+	}
+}
+
+func TestNewBuildContext(t *testing.T) {
+	assert.Nil(t, NewBuildContext(&Options{}))
+
+	ctx := NewBuildContext(&Options{BuildTags: []string{"integration"}})
+	require.NotNil(t, ctx)
+	assert.Equal(t, build.Default.GOOS, ctx.GOOS)
+	assert.Equal(t, build.Default.GOARCH, ctx.GOARCH)
+	assert.Equal(t, build.Default.CgoEnabled, ctx.CgoEnabled)
+	assert.Equal(t, []string{"integration"}, ctx.BuildTags)
+
+	other := "windows"
+	if build.Default.GOOS == other {
+		other = "linux"
+	}
+
+	ctx = NewBuildContext(&Options{GOOS: other})
+	require.NotNil(t, ctx)
+	assert.Equal(t, other, ctx.GOOS)
+	assert.False(t, ctx.CgoEnabled)
+}
+
+func TestRunGocycloSkipsBrokenFiles(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteFiles(t, root, map[string]string{
+		"main.go":   "package main\n\nfunc main() {\n\tif true {\n\t}\n}\n",
+		"broken.go": "package main\n\nfunc {\n",
+	})
+
+	warnings := 0
+	opts := &Options{OnWarning: func(Warning) { warnings++ }}
+
+	for _, run := range []EngineFunc{RunGocyclo, RunGocognit} {
+		result, err := run(root, opts)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "main.go", result[0].Path)
+	}
+
+	assert.Equal(t, 2, warnings)
+}
@@ -0,0 +1,58 @@
+package coupling
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/bndr/gotabulate"
+)
+
+func PrintTabular(results []*Package, out io.Writer) {
+	_, _ = io.WriteString(out, "\nPackage coupling analysis results:\n")
+
+	data := make([][]any, len(results))
+	for i, result := range results {
+		data[i] = []any{
+			result.Path,
+			result.Files,
+			result.Afferent,
+			result.Efferent,
+			fmt.Sprintf("%.2f", result.Instability),
+			fmt.Sprintf("%.2f", result.Abstractness),
+			fmt.Sprintf("%.2f", result.Distance),
+		}
+	}
+
+	table := gotabulate.Create(data)
+	table.SetHeaders([]string{"PACKAGE", "FILES", "AFFERENT", "EFFERENT", "INSTABILITY", "ABSTRACTNESS", "DISTANCE"})
+	table.SetAlign("left")
+
+	_, _ = io.WriteString(out, table.Render("grid"))
+}
+
+func PrintCSV(results []*Package, out io.Writer) {
+	writer := csv.NewWriter(out)
+	defer writer.Flush()
+
+	_ = writer.Write([]string{
+		"PACKAGE", "DIRECTORY", "FILES", "TYPES", "INTERFACES", "AFFERENT", "EFFERENT", "INSTABILITY", "ABSTRACTNESS",
+		"DISTANCE",
+	})
+
+	for _, result := range results {
+		_ = writer.Write([]string{
+			result.Path,
+			result.Dir,
+			strconv.Itoa(result.Files),
+			strconv.Itoa(result.Types),
+			strconv.Itoa(result.Interfaces),
+			strconv.Itoa(result.Afferent),
+			strconv.Itoa(result.Efferent),
+			strconv.FormatFloat(result.Instability, 'f', 2, 64),
+			strconv.FormatFloat(result.Abstractness, 'f', 2, 64),
+			strconv.FormatFloat(result.Distance, 'f', 2, 64),
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package coupling
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var printPackages = []*Package{
+	{
+		Path: "example.com/app/store", Dir: "store", Files: 2, Types: 3, Interfaces: 2, Afferent: 2, Efferent: 0,
+		Instability: 0, Abstractness: 2.0 / 3, Distance: 1.0 / 3,
+	},
+}
+
+func TestPrintCSV(t *testing.T) {
+	var buf bytes.Buffer
+
+	PrintCSV(printPackages, &buf)
+
+	assert.Equal(t,
+		"PACKAGE,DIRECTORY,FILES,TYPES,INTERFACES,AFFERENT,EFFERENT,INSTABILITY,ABSTRACTNESS,DISTANCE\n"+
+			"example.com/app/store,store,2,3,2,2,0,0.00,0.67,0.33\n",
+		buf.String())
+}
+
+func TestPrintTabular(t *testing.T) {
+	var buf bytes.Buffer
+
+	PrintTabular(printPackages, &buf)
+
+	assert.Contains(t, buf.String(), "INSTABILITY")
+	assert.Contains(t, buf.String(), "example.com/app/store")
+	assert.Contains(t, buf.String(), "0.67")
+}
@@ -0,0 +1,239 @@
+// Package coupling computes Robert C. Martin's package metrics from imports of Go files:
+// afferent and efferent coupling, instability, abstractness and distance from the main sequence.
+package coupling
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"math"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/module"
+)
+
+type SortType = string
+
+const (
+	Distance    SortType = "distance"
+	Instability SortType = "instability"
+	Afferent    SortType = "afferent"
+	Efferent    SortType = "efferent"
+)
+
+var (
+	AvailableSorts = []SortType{Distance, Instability, Afferent, Efferent}
+
+	ErrUnsupportedSort = errors.New("unsupported sort type")
+)
+
+const testSuffix = "_test"
+
+type Options struct {
+	SortBy       SortType
+	Top          int
+	OutputFormat string
+	// Files selects analyzed Go files the same way complexity engines do. Commands exclude test files by default,
+	// since test-only imports would inflate efferent coupling of production packages.
+	Files complexity.Options
+}
+
+// Package holds coupling metrics of a package. Only packages of the analyzed repository are counted
+// as dependencies, so that standard library and third-party imports don't affect the metrics.
+type Package struct {
+	// Path is import path of the package, directory is used when it doesn't belong to any module.
+	Path string
+	// Dir is package directory relative to the repository.
+	Dir        string
+	Files      int
+	Types      int
+	Interfaces int
+	// Afferent is the number of packages that import this package.
+	Afferent int
+	// Efferent is the number of packages imported by this package.
+	Efferent     int
+	Instability  float64
+	Abstractness float64
+	Distance     float64
+}
+
+func PopulateOpts(opts *Options, excludeRegex string) error {
+	if !slices.Contains(AvailableSorts, opts.SortBy) {
+		return fmt.Errorf("%w: %s, expected one of %v", ErrUnsupportedSort, opts.SortBy, AvailableSorts)
+	}
+
+	if err := complexity.PopulateOpts(&opts.Files, excludeRegex); err != nil {
+		return fmt.Errorf("invalid files options: %w", err)
+	}
+
+	return nil
+}
+
+// Analyze computes metrics of every package with Go files under repoPath. Import paths of packages
+// are derived from modules of the repository. External test packages, 'package x_test', are reported
+// separately from the tested package with '_test' suffix, the same way go tool names them.
+func Analyze(repoPath string, opts *Options) ([]*Package, error) {
+	modules, err := module.Discover(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover modules: %w", err)
+	}
+
+	packages := make(map[string]*Package)
+	imports := make(map[string]map[string]bool)
+
+	err = complexity.WalkGoFiles(repoPath, &opts.Files, func(filePath string, file *ast.File, _ *token.FileSet) error {
+		relPath, err := filepath.Rel(repoPath, filePath)
+		if err != nil {
+			return fmt.Errorf("failed to get relative path: %w", err)
+		}
+
+		dir := filepath.Dir(relPath)
+		key, pkgPath := dir, importPath(dir, modules)
+
+		if strings.HasSuffix(file.Name.Name, testSuffix) {
+			key, pkgPath = key+testSuffix, pkgPath+testSuffix
+		}
+
+		if packages[key] == nil {
+			packages[key] = &Package{Path: pkgPath, Dir: dir}
+			imports[key] = make(map[string]bool)
+		}
+
+		addFile(packages[key], imports[key], file)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk repository: %w", err)
+	}
+
+	return computeMetrics(packages, imports), nil
+}
+
+// importPath returns import path of package in dir using the module containing it.
+func importPath(dir string, modules module.Modules) string {
+	dir = filepath.ToSlash(dir)
+
+	owner, ok := modules.Owner(dir)
+	if !ok {
+		return dir
+	}
+
+	if owner.Dir == "." {
+		return path.Join(owner.Path, dir)
+	}
+
+	return path.Join(owner.Path, strings.TrimPrefix(dir, owner.Dir))
+}
+
+func addFile(pkg *Package, imports map[string]bool, file *ast.File) {
+	pkg.Files++
+
+	for _, spec := range file.Imports {
+		if imported, err := strconv.Unquote(spec.Path.Value); err == nil {
+			imports[imported] = true
+		}
+	}
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+
+			pkg.Types++
+
+			if _, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+				pkg.Interfaces++
+			}
+		}
+	}
+}
+
+// computeMetrics resolves imports of packages to packages of the repository and calculates metrics.
+func computeMetrics(packages map[string]*Package, imports map[string]map[string]bool) []*Package {
+	byPath := make(map[string]*Package, len(packages))
+	for _, pkg := range packages {
+		byPath[pkg.Path] = pkg
+	}
+
+	for key, pkg := range packages {
+		for imported := range imports[key] {
+			dependency, ok := byPath[imported]
+			if !ok || dependency == pkg {
+				continue
+			}
+
+			pkg.Efferent++
+			dependency.Afferent++
+		}
+	}
+
+	result := make([]*Package, 0, len(packages))
+
+	for _, pkg := range packages {
+		if coupling := pkg.Afferent + pkg.Efferent; coupling > 0 {
+			pkg.Instability = float64(pkg.Efferent) / float64(coupling)
+		}
+
+		if pkg.Types > 0 {
+			pkg.Abstractness = float64(pkg.Interfaces) / float64(pkg.Types)
+		}
+
+		pkg.Distance = math.Abs(pkg.Abstractness + pkg.Instability - 1)
+
+		result = append(result, pkg)
+	}
+
+	slices.SortFunc(result, func(a, b *Package) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return result
+}
+
+// SortAndLimit sorts packages by sortBy metric, highest first, and returns top of them.
+func SortAndLimit(packages []*Package, sortBy SortType, top int) []*Package {
+	metric := func(pkg *Package) float64 {
+		switch sortBy {
+		case Instability:
+			return pkg.Instability
+		case Afferent:
+			return float64(pkg.Afferent)
+		case Efferent:
+			return float64(pkg.Efferent)
+		default:
+			return pkg.Distance
+		}
+	}
+
+	slices.SortStableFunc(packages, func(a, b *Package) int {
+		if metric(a) > metric(b) {
+			return -1
+		}
+
+		if metric(a) < metric(b) {
+			return 1
+		}
+
+		return 0
+	})
+
+	if top > 0 && top < len(packages) {
+		return packages[:top]
+	}
+
+	return packages
+}
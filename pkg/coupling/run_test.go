@@ -0,0 +1,150 @@
+package coupling
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vbvictor/grit/internal/testutil"
+	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/module"
+	"github.com/vbvictor/grit/pkg/testfiles"
+)
+
+func TestAnalyze(t *testing.T) {
+	repo := t.TempDir()
+	testutil.WriteFiles(t, repo, map[string]string{
+		"go.mod": "module example.com/app\n",
+		"main.go": `package main
+
+import (
+	"fmt"
+
+	"example.com/app/api"
+	"example.com/app/store"
+)
+
+func main() { fmt.Println(api.Handler{}, store.New()) }
+`,
+		"api/api.go": `package api
+
+import "example.com/app/store"
+
+type Handler struct{ Store store.Store }
+`,
+		"store/store.go": `package store
+
+type Store interface{ Get(key string) string }
+
+type Reader interface{ Read() }
+
+type memory struct{}
+
+func (memory) Get(string) string { return "" }
+
+func New() Store { return memory{} }
+`,
+		"store/store_test.go": `package store_test
+
+import "example.com/app/store"
+
+var _ = store.New
+`,
+		"tools/go.mod": "module example.com/tools\n",
+		"tools/gen.go": `package tools
+
+import "example.com/app/api"
+
+var _ api.Handler
+`,
+	})
+
+	opts := &Options{SortBy: Distance, Files: complexity.Options{NoGitignore: true}}
+	opts.Files.Tests.Mode = testfiles.Exclude
+	require.NoError(t, PopulateOpts(opts, ""))
+
+	packages, err := Analyze(repo, opts)
+	require.NoError(t, err)
+
+	// Variables round the same way as metrics computed at runtime.
+	third, twoThirds := 1.0/3, 2.0/3
+
+	assert.Equal(t, []*Package{
+		{
+			Path: "example.com/app", Dir: ".", Files: 1, Afferent: 0, Efferent: 2,
+			Instability: 1, Abstractness: 0, Distance: 0,
+		},
+		{
+			Path: "example.com/app/api", Dir: "api", Files: 1, Types: 1, Afferent: 2, Efferent: 1,
+			Instability: third, Abstractness: 0, Distance: 1 - third,
+		},
+		{
+			Path: "example.com/app/store", Dir: "store", Files: 1, Types: 3, Interfaces: 2, Afferent: 2, Efferent: 0,
+			Instability: 0, Abstractness: twoThirds, Distance: 1 - twoThirds,
+		},
+		{
+			Path: "example.com/tools", Dir: "tools", Files: 1, Afferent: 0, Efferent: 1,
+			Instability: 1, Abstractness: 0, Distance: 0,
+		},
+	}, packages)
+
+	// external test package is a separate package importing the tested one
+	opts.Files.Tests.Mode = testfiles.Include
+	require.NoError(t, PopulateOpts(opts, ""))
+
+	packages, err = Analyze(repo, opts)
+	require.NoError(t, err)
+	require.Len(t, packages, 5)
+
+	assert.Equal(t, "example.com/app/store", packages[2].Path)
+	assert.Equal(t, 3, packages[2].Afferent)
+	assert.Equal(t, &Package{
+		Path: "example.com/app/store_test", Dir: "store", Files: 1, Efferent: 1, Instability: 1,
+	}, packages[3])
+}
+
+func TestImportPath(t *testing.T) {
+	modules := module.Modules{{Path: "example.com/app", Dir: "."}, {Path: "example.com/tools", Dir: "tools"}}
+
+	assert.Equal(t, "example.com/app", importPath(".", modules))
+	assert.Equal(t, "example.com/app/pkg/a", importPath(filepath.Join("pkg", "a"), modules))
+	assert.Equal(t, "example.com/tools", importPath("tools", modules))
+	assert.Equal(t, "example.com/tools/gen", importPath(filepath.Join("tools", "gen"), modules))
+	assert.Equal(t, "pkg/a", importPath(filepath.Join("pkg", "a"), nil))
+}
+
+func TestPopulateOpts(t *testing.T) {
+	require.ErrorIs(t, PopulateOpts(&Options{SortBy: "churn"}, ""), ErrUnsupportedSort)
+	require.Error(t, PopulateOpts(&Options{SortBy: Distance}, "("))
+	require.NoError(t, PopulateOpts(&Options{SortBy: Efferent}, ""))
+}
+
+func TestSortAndLimit(t *testing.T) {
+	packages := []*Package{
+		{Path: "a", Afferent: 1, Efferent: 3, Instability: 0.75, Distance: 0.25},
+		{Path: "b", Afferent: 4, Efferent: 0, Instability: 0, Distance: 1},
+		{Path: "c", Afferent: 2, Efferent: 2, Instability: 0.5, Distance: 0.5},
+	}
+
+	tests := []struct {
+		sortBy SortType
+		want   []string
+	}{
+		{Distance, []string{"b", "c"}},
+		{Instability, []string{"a", "c"}},
+		{Afferent, []string{"b", "c"}},
+		{Efferent, []string{"a", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			names := make([]string, 0)
+			for _, pkg := range SortAndLimit(packages, tt.sortBy, 2) {
+				names = append(names, pkg.Path)
+			}
+
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
@@ -0,0 +1,450 @@
+package coverage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/cover"
+)
+
+// Binary coverage data written to GOCOVERDIR by binaries built with 'go build -cover' consists of meta-data files
+// 'covmeta.<hash>', describing coverable units of every function, and counter files 'covcounters.<hash>.<pid>.<time>'
+// holding counters of a run. The layout mirrors Go's internal/coverage packages, which can't be imported.
+const (
+	metaFilePrefix    = "covmeta."
+	counterFilePrefix = "covcounters."
+
+	metaFileHeaderSize    = 56
+	metaPackageHeaderSize = 44
+	counterHeaderSize     = 32
+	counterSegmentSize    = 16
+	counterFooterSize     = 16
+
+	counterRaw     = 1
+	counterULEB128 = 2
+
+	granularityPerFunc = 2
+
+	hashSize = 16
+	wordSize = 4
+)
+
+var (
+	metaMagic    = []byte{0x00, 0x63, 0x76, 0x6d}
+	counterMagic = []byte{0x00, 0x63, 0x77, 0x6d}
+
+	errInvalidCovdata = errors.New("invalid binary coverage data")
+)
+
+// counterModes are modes of profiles by mode numbers of meta-data files.
+var counterModes = map[byte]string{1: ModeSet, 2: ModeCount, 3: ModeAtomic} //nolint:mnd // internal/coverage values
+
+// coverUnit is a coverable unit, block of statements, of a function.
+type coverUnit struct {
+	block cover.ProfileBlock
+	file  string
+}
+
+// metaFile holds coverable units of functions by package and function index.
+type metaFile struct {
+	mode        string
+	granularity byte
+	units       [][][]coverUnit
+}
+
+// IsCoverageDir reports whether path is a directory with binary coverage data.
+func IsCoverageDir(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && info.IsDir()
+}
+
+// readCoverageDir converts binary coverage data of dir to profiles. Counters of every run of the same binary
+// are merged, units of functions that were never executed are reported with zero count.
+func readCoverageDir(dir string) ([]*cover.Profile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read coverage directory: %w", err)
+	}
+
+	metas := make(map[string]*metaFile)
+
+	for _, entry := range entries {
+		if hash, ok := strings.CutPrefix(entry.Name(), metaFilePrefix); ok {
+			if metas[hash], err = readMetaFile(filepath.Join(dir, entry.Name())); err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+			}
+		}
+	}
+
+	for _, entry := range entries {
+		name, ok := strings.CutPrefix(entry.Name(), counterFilePrefix)
+		if !ok {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(name, ".")
+
+		meta, ok := metas[hash]
+		if !ok {
+			return nil, fmt.Errorf("%w: meta-data of %s not found", errInvalidCovdata, entry.Name())
+		}
+
+		if err := readCounterFile(filepath.Join(dir, entry.Name()), meta); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+	}
+
+	profiles := make([]*cover.Profile, 0)
+	for _, meta := range metas {
+		profiles = append(profiles, meta.profiles()...)
+	}
+
+	return profiles, nil
+}
+
+// profiles groups units of meta file by source file.
+func (m *metaFile) profiles() []*cover.Profile {
+	byFile := make(map[string]*cover.Profile)
+	result := make([]*cover.Profile, 0)
+
+	for _, functions := range m.units {
+		for _, units := range functions {
+			for _, unit := range units {
+				profile, ok := byFile[unit.file]
+				if !ok {
+					profile = &cover.Profile{FileName: unit.file, Mode: m.mode}
+					byFile[unit.file] = profile
+					result = append(result, profile)
+				}
+
+				profile.Blocks = append(profile.Blocks, unit.block)
+			}
+		}
+	}
+
+	for _, profile := range result {
+		sortBlocks(profile.Blocks)
+	}
+
+	return result
+}
+
+// byteReader reads little-endian values and ULEB128 numbers from data, errors are collected to err.
+type byteReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *byteReader) bytes(n int) []byte {
+	if r.err != nil || n < 0 || r.pos+n > len(r.data) {
+		r.err = fmt.Errorf("%w: unexpected end of data", errInvalidCovdata)
+
+		return make([]byte, max(n, 0))
+	}
+
+	result := r.data[r.pos : r.pos+n]
+	r.pos += n
+
+	return result
+}
+
+func (r *byteReader) uint32() uint32 {
+	return binary.LittleEndian.Uint32(r.bytes(wordSize))
+}
+
+func (r *byteReader) uint64() uint64 {
+	return binary.LittleEndian.Uint64(r.bytes(2 * wordSize)) //nolint:mnd // 8 bytes
+}
+
+func (r *byteReader) uleb128() uint64 {
+	var value uint64
+
+	for shift := 0; r.err == nil; shift += 7 {
+		b := r.bytes(1)[0]
+		value |= uint64(b&0x7f) << shift //nolint:mnd // ULEB128 payload bits
+
+		if b&0x80 == 0 {
+			break
+		}
+	}
+
+	return value
+}
+
+func (r *byteReader) seek(pos int) {
+	if pos < 0 || pos > len(r.data) {
+		r.err = fmt.Errorf("%w: offset %d is out of data", errInvalidCovdata, pos)
+
+		return
+	}
+
+	r.pos = pos
+}
+
+func (r *byteReader) align() {
+	if rem := r.pos % wordSize; rem != 0 {
+		r.bytes(wordSize - rem)
+	}
+}
+
+func (r *byteReader) stringTable() []string {
+	count := r.uleb128()
+	if r.err != nil || count > uint64(len(r.data)) {
+		r.err = errors.Join(r.err, fmt.Errorf("%w: malformed string table", errInvalidCovdata))
+
+		return nil
+	}
+
+	strs := make([]string, 0, count)
+	for range count {
+		strs = append(strs, string(r.bytes(int(r.uleb128())))) //nolint:gosec // length is checked by bytes
+	}
+
+	return strs
+}
+
+func lookup(strs []string, index uint64) (string, error) {
+	if index >= uint64(len(strs)) {
+		return "", fmt.Errorf("%w: string index %d is out of table", errInvalidCovdata, index)
+	}
+
+	return strs[index], nil
+}
+
+func readMetaFile(path string) (*metaFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	reader := &byteReader{data: data}
+
+	meta, entries, err := readMetaHeader(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	offsets := make([]uint64, entries)
+	for i := range offsets {
+		offsets[i] = reader.uint64()
+	}
+
+	lengths := make([]uint64, entries)
+	for i := range lengths {
+		lengths[i] = reader.uint64()
+	}
+
+	for i := range offsets {
+		if reader.err != nil || offsets[i]+lengths[i] > uint64(len(data)) {
+			return nil, fmt.Errorf("%w: package %d is out of file", errInvalidCovdata, i)
+		}
+
+		functions, err := readMetaPackage(data[offsets[i] : offsets[i]+lengths[i]])
+		if err != nil {
+			return nil, err
+		}
+
+		meta.units = append(meta.units, functions)
+	}
+
+	return meta, reader.err
+}
+
+// readMetaHeader reads counter mode and granularity of meta-data file and the number of its packages,
+// reader is left at the package offsets table.
+func readMetaHeader(reader *byteReader) (*metaFile, uint64, error) {
+	if !bytes.Equal(reader.bytes(len(metaMagic)), metaMagic) {
+		return nil, 0, fmt.Errorf("%w: not a meta-data file", errInvalidCovdata)
+	}
+
+	reader.uint32() // version
+	reader.uint64() // total length
+	entries := reader.uint64()
+	reader.bytes(hashSize)
+	reader.uint32() // string table offset
+	reader.uint32() // string table length
+
+	modeByte := reader.bytes(1)[0]
+	meta := &metaFile{mode: counterModes[modeByte], granularity: reader.bytes(1)[0]}
+
+	if meta.mode == "" {
+		return nil, 0, fmt.Errorf("%w: counter mode %d", errUnsupportedMode, modeByte)
+	}
+
+	reader.seek(metaFileHeaderSize)
+
+	if reader.err != nil || entries > uint64(len(reader.data)) {
+		return nil, 0, fmt.Errorf("%w: malformed header", errInvalidCovdata)
+	}
+
+	return meta, entries, nil
+}
+
+// readMetaPackage reads coverable units of every function of package payload.
+func readMetaPackage(data []byte) ([][]coverUnit, error) {
+	reader := &byteReader{data: data}
+	reader.bytes(metaPackageHeaderSize - 2*wordSize) //nolint:mnd // fields up to NumFiles aren't needed
+	reader.uint32()                                  // number of files
+	numFuncs := reader.uint32()
+
+	if reader.err != nil || uint64(numFuncs)*wordSize > uint64(len(data)) {
+		return nil, fmt.Errorf("%w: malformed package header", errInvalidCovdata)
+	}
+
+	funcOffsets := make([]uint32, numFuncs)
+	for i := range funcOffsets {
+		funcOffsets[i] = reader.uint32()
+	}
+
+	strs := reader.stringTable()
+	functions := make([][]coverUnit, 0, numFuncs)
+
+	for _, offset := range funcOffsets {
+		reader.seek(int(offset))
+
+		numUnits := reader.uleb128()
+		reader.uleb128() // function name
+
+		file, err := lookup(strs, reader.uleb128())
+		if err != nil {
+			return nil, err
+		}
+
+		if reader.err != nil || numUnits > uint64(len(data)) {
+			return nil, fmt.Errorf("%w: malformed function", errInvalidCovdata)
+		}
+
+		units := make([]coverUnit, 0, numUnits)
+		for range numUnits {
+			units = append(units, coverUnit{file: file, block: cover.ProfileBlock{
+				StartLine: int(reader.uleb128()), //nolint:gosec // positions fit int
+				StartCol:  int(reader.uleb128()), //nolint:gosec // positions fit int
+				EndLine:   int(reader.uleb128()), //nolint:gosec // positions fit int
+				EndCol:    int(reader.uleb128()), //nolint:gosec // positions fit int
+				NumStmt:   int(reader.uleb128()), //nolint:gosec // statements fit int
+			}})
+		}
+
+		functions = append(functions, units)
+	}
+
+	if reader.err != nil {
+		return nil, reader.err
+	}
+
+	return functions, nil
+}
+
+// readCounterFile adds counters of every segment of counter file to units of meta.
+func readCounterFile(path string, meta *metaFile) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	reader := &byteReader{data: data}
+
+	readValue, err := readCounterHeader(reader)
+	if err != nil {
+		return err
+	}
+
+	for reader.err == nil && reader.pos+counterFooterSize < len(data) {
+		if err := readCounterSegment(reader, readValue, meta); err != nil {
+			return err
+		}
+	}
+
+	return reader.err
+}
+
+// readCounterHeader checks counter file header and returns a function reading counter values
+// in the encoding of the file, reader is left at the first segment.
+func readCounterHeader(reader *byteReader) (func() uint32, error) {
+	if !bytes.Equal(reader.bytes(len(counterMagic)), counterMagic) {
+		return nil, fmt.Errorf("%w: not a counter file", errInvalidCovdata)
+	}
+
+	reader.uint32() // version
+	reader.bytes(hashSize)
+	flavor := reader.bytes(1)[0]
+	bigEndian := reader.bytes(1)[0] != 0
+
+	if flavor != counterRaw && flavor != counterULEB128 {
+		return nil, fmt.Errorf("%w: counter flavor %d", errInvalidCovdata, flavor)
+	}
+
+	reader.seek(counterHeaderSize)
+
+	return func() uint32 {
+		switch {
+		case flavor == counterULEB128:
+			return uint32(reader.uleb128()) //nolint:gosec // counters are 32 bit
+		case bigEndian:
+			return binary.BigEndian.Uint32(reader.bytes(wordSize))
+		default:
+			return reader.uint32()
+		}
+	}, nil
+}
+
+// readCounterSegment adds counters of every function of one segment of counter file to units of meta.
+func readCounterSegment(reader *byteReader, readValue func() uint32, meta *metaFile) error {
+	functions := reader.uint64()
+	strTabLen := reader.uint32()
+	argsLen := reader.uint32()
+	reader.bytes(int(strTabLen) + int(argsLen))
+	reader.align()
+
+	for range functions {
+		numCounters := readValue()
+		pkgIdx, funcIdx := readValue(), readValue()
+
+		counters := make([]uint32, 0, numCounters)
+		for range numCounters {
+			counters = append(counters, readValue())
+		}
+
+		if err := meta.addCounters(pkgIdx, funcIdx, counters); err != nil {
+			return err
+		}
+	}
+
+	reader.bytes(counterFooterSize)
+
+	return nil
+}
+
+func (m *metaFile) addCounters(pkgIdx, funcIdx uint32, counters []uint32) error {
+	if int(pkgIdx) >= len(m.units) || int(funcIdx) >= len(m.units[pkgIdx]) {
+		return fmt.Errorf("%w: counters of unknown function %d of package %d", errInvalidCovdata, funcIdx, pkgIdx)
+	}
+
+	units := m.units[pkgIdx][funcIdx]
+
+	for i := range units {
+		var count uint32
+
+		switch {
+		case m.granularity == granularityPerFunc && len(counters) > 0:
+			count = counters[0]
+		case i < len(counters):
+			count = counters[i]
+		}
+
+		if m.mode == ModeSet {
+			units[i].block.Count = max(units[i].block.Count, min(int(count), 1))
+		} else {
+			units[i].block.Count += int(count)
+		}
+	}
+
+	return nil
+}
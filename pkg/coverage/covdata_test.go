@@ -0,0 +1,57 @@
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/cover"
+)
+
+// covdataDir holds data of two runs of a binary built with 'go build -cover -covermode=count'.
+var covdataDir = filepath.Join("..", "..", "testdata", "coverage", "covdata")
+
+func TestReadCoverageDir(t *testing.T) {
+	profiles, err := readCoverageDir(covdataDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*cover.Profile{{
+		FileName: "example.com/hello/main.go",
+		Mode:     ModeCount,
+		Blocks: []cover.ProfileBlock{
+			{StartLine: 9, StartCol: 2, EndLine: 9, EndCol: 22, NumStmt: 1, Count: 2},
+			{StartLine: 10, StartCol: 3, EndLine: 11, EndCol: 1, NumStmt: 2, Count: 1},
+			{StartLine: 12, StartCol: 3, EndLine: 13, EndCol: 1, NumStmt: 2, Count: 1},
+			{StartLine: 15, StartCol: 2, EndLine: 15, EndCol: 22, NumStmt: 1, Count: 1},
+			{StartLine: 19, StartCol: 2, EndLine: 20, EndCol: 1, NumStmt: 1, Count: 0},
+		},
+	}}, profiles)
+}
+
+func TestReadCoverageDirInvalid(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "covmeta.0011"), []byte("not a meta file"), 0o600))
+
+	_, err := readCoverageDir(tmpDir)
+	require.ErrorIs(t, err, errInvalidCovdata)
+}
+
+func TestReadCoverageMergesSources(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/hello\n"), 0o600))
+
+	unit := createTempFile(t, tmpDir, `mode: atomic
+example.com/hello/main.go:19.2,20.1 1 3`)
+
+	absDir, err := filepath.Abs(covdataDir)
+	require.NoError(t, err)
+
+	got, err := ReadCoverage(tmpDir, filepath.Base(unit.Name()), &Options{Profiles: []string{absDir}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []*FileCoverage{
+		{File: "main.go", Coverage: 100, Statements: 7, Covered: 7, Mode: ModeCount, Hits: 10},
+	}, got)
+}
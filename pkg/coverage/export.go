@@ -0,0 +1,198 @@
+package coverage
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/vbvictor/grit/grit/cmd/flag"
+	"golang.org/x/tools/cover"
+)
+
+// lineHits is the number of executions of a source line, which is the highest count of blocks on the line.
+type lineHits struct {
+	Line int
+	Hits int
+}
+
+// GetProfiles is GetCoverageData returning merged profiles, files of profiles are repository relative paths.
+func GetProfiles(repoPath string, coverageOpts *Options) ([]*cover.Profile, error) {
+	if err := ensureProfile(repoPath, coverageOpts); err != nil {
+		return nil, err
+	}
+
+	profiles, err := readProfiles(repoPath, coverageOpts.CoverageFilename, coverageOpts)
+	if err != nil {
+		return nil, errors.Join(flag.ErrReadCoverage, err)
+	}
+
+	return profiles, nil
+}
+
+// profileLines converts blocks of profile to hits of lines. A block ending at the first column of its
+// last line doesn't cover that line.
+func profileLines(profile *cover.Profile) []lineHits {
+	hits := make(map[int]int)
+
+	for _, block := range profile.Blocks {
+		if block.NumStmt == 0 {
+			continue
+		}
+
+		endLine := block.EndLine
+		if block.EndCol <= 1 && endLine > block.StartLine {
+			endLine--
+		}
+
+		for line := block.StartLine; line <= endLine; line++ {
+			if count, ok := hits[line]; !ok || block.Count > count {
+				hits[line] = block.Count
+			}
+		}
+	}
+
+	lines := make([]lineHits, 0, len(hits))
+	for _, line := range slices.Sorted(maps.Keys(hits)) {
+		lines = append(lines, lineHits{Line: line, Hits: hits[line]})
+	}
+
+	return lines
+}
+
+func coveredLines(lines []lineHits) int {
+	covered := 0
+
+	for _, line := range lines {
+		if line.Hits > 0 {
+			covered++
+		}
+	}
+
+	return covered
+}
+
+// PrintLCOV writes profiles as LCOV tracefile with a record of line data per file.
+func PrintLCOV(profiles []*cover.Profile, out io.Writer) {
+	for _, profile := range profiles {
+		lines := profileLines(profile)
+
+		_, _ = fmt.Fprintf(out, "TN:\nSF:%s\n", filepath.ToSlash(profile.FileName))
+
+		for _, line := range lines {
+			_, _ = fmt.Fprintf(out, "DA:%d,%d\n", line.Line, line.Hits)
+		}
+
+		_, _ = fmt.Fprintf(out, "LF:%d\nLH:%d\nend_of_record\n", len(lines), coveredLines(lines))
+	}
+}
+
+type coberturaOutput struct {
+	XMLName         xml.Name            `xml:"coverage"`
+	LineRate        string              `xml:"line-rate,attr"`
+	BranchRate      string              `xml:"branch-rate,attr"`
+	LinesCovered    int                 `xml:"lines-covered,attr"`
+	LinesValid      int                 `xml:"lines-valid,attr"`
+	BranchesCovered int                 `xml:"branches-covered,attr"`
+	BranchesValid   int                 `xml:"branches-valid,attr"`
+	Complexity      string              `xml:"complexity,attr"`
+	Version         string              `xml:"version,attr"`
+	Timestamp       int64               `xml:"timestamp,attr"`
+	Sources         []string            `xml:"sources>source"`
+	Packages        []*coberturaPackage `xml:"packages>package"`
+}
+
+type coberturaPackage struct {
+	Name       string            `xml:"name,attr"`
+	LineRate   string            `xml:"line-rate,attr"`
+	BranchRate string            `xml:"branch-rate,attr"`
+	Complexity string            `xml:"complexity,attr"`
+	Classes    []*coberturaClass `xml:"classes>class"`
+
+	covered, valid int
+}
+
+type coberturaClass struct {
+	Name       string          `xml:"name,attr"`
+	Filename   string          `xml:"filename,attr"`
+	LineRate   string          `xml:"line-rate,attr"`
+	BranchRate string          `xml:"branch-rate,attr"`
+	Complexity string          `xml:"complexity,attr"`
+	Methods    struct{}        `xml:"methods"`
+	Lines      []coberturaLine `xml:"lines>line"`
+}
+
+type coberturaLine struct {
+	Number int `xml:"number,attr"`
+	Hits   int `xml:"hits,attr"`
+}
+
+// PrintCobertura writes profiles as Cobertura XML report. Files are classes of packages named by their
+// directories, filenames are relative to source. Branches aren't known to Go profiles and are reported as zero.
+func PrintCobertura(profiles []*cover.Profile, source string, timestamp time.Time, out io.Writer) error {
+	report := &coberturaOutput{
+		BranchRate: "0", Complexity: "0", Version: "grit", Timestamp: timestamp.UnixMilli(), Sources: []string{source},
+	}
+	packages := make(map[string]*coberturaPackage)
+
+	for _, profile := range profiles {
+		file := filepath.ToSlash(profile.FileName)
+		lines := profileLines(profile)
+		covered := coveredLines(lines)
+
+		pkg, ok := packages[path.Dir(file)]
+		if !ok {
+			pkg = &coberturaPackage{Name: path.Dir(file), BranchRate: "0", Complexity: "0"}
+			packages[pkg.Name] = pkg
+			report.Packages = append(report.Packages, pkg)
+		}
+
+		class := &coberturaClass{
+			Name: path.Base(file), Filename: file, LineRate: lineRate(covered, len(lines)), BranchRate: "0", Complexity: "0",
+		}
+		for _, line := range lines {
+			class.Lines = append(class.Lines, coberturaLine{Number: line.Line, Hits: line.Hits})
+		}
+
+		pkg.Classes = append(pkg.Classes, class)
+		pkg.covered += covered
+		pkg.valid += len(lines)
+		report.LinesCovered += covered
+		report.LinesValid += len(lines)
+	}
+
+	for _, pkg := range report.Packages {
+		pkg.LineRate = lineRate(pkg.covered, pkg.valid)
+	}
+
+	report.LineRate = lineRate(report.LinesCovered, report.LinesValid)
+
+	_, _ = io.WriteString(out, xml.Header)
+	_, _ = io.WriteString(out,
+		`<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">`+"\n")
+
+	encoder := xml.NewEncoder(out)
+	encoder.Indent("", "\t")
+
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to encode Cobertura report: %w", err)
+	}
+
+	_, _ = io.WriteString(out, "\n")
+
+	return nil
+}
+
+func lineRate(covered, valid int) string {
+	if valid == 0 {
+		return "0"
+	}
+
+	return strconv.FormatFloat(float64(covered)/float64(valid), 'f', -1, 64)
+}
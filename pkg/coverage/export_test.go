@@ -0,0 +1,112 @@
+package coverage
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/cover"
+)
+
+var exportProfiles = []*cover.Profile{
+	{
+		FileName: "pkg/a/a.go",
+		Mode:     ModeCount,
+		Blocks: []cover.ProfileBlock{
+			{StartLine: 3, StartCol: 20, EndLine: 5, EndCol: 3, NumStmt: 2, Count: 4},
+			{StartLine: 5, StartCol: 3, EndLine: 7, EndCol: 1, NumStmt: 1, Count: 0},
+			{StartLine: 9, StartCol: 1, EndLine: 9, EndCol: 10, NumStmt: 0, Count: 0},
+		},
+	},
+	{
+		FileName: "main.go",
+		Mode:     ModeCount,
+		Blocks:   []cover.ProfileBlock{{StartLine: 1, StartCol: 1, EndLine: 1, EndCol: 5, NumStmt: 1, Count: 1}},
+	},
+}
+
+func TestProfileLines(t *testing.T) {
+	assert.Equal(t, []lineHits{
+		{Line: 3, Hits: 4},
+		{Line: 4, Hits: 4},
+		{Line: 5, Hits: 4},
+		{Line: 6, Hits: 0},
+	}, profileLines(exportProfiles[0]))
+}
+
+func TestPrintLCOV(t *testing.T) {
+	var out bytes.Buffer
+
+	PrintLCOV(exportProfiles, &out)
+
+	assert.Equal(t, `TN:
+SF:pkg/a/a.go
+DA:3,4
+DA:4,4
+DA:5,4
+DA:6,0
+LF:4
+LH:3
+end_of_record
+TN:
+SF:main.go
+DA:1,1
+LF:1
+LH:1
+end_of_record
+`, out.String())
+
+	// exported report is read back with the same coverage of lines
+	profiles, err := parseLCOV(out.Bytes())
+	require.NoError(t, err)
+	assert.Len(t, profiles, 2)
+	assert.Equal(t, "pkg/a/a.go", profiles[0].FileName)
+	assert.Len(t, profiles[0].Blocks, 4)
+}
+
+func TestPrintCobertura(t *testing.T) {
+	var out bytes.Buffer
+
+	require.NoError(t, PrintCobertura(exportProfiles, "/repo", time.UnixMilli(1700000000000), &out))
+
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">
+<coverage line-rate="0.8" branch-rate="0" lines-covered="4" lines-valid="5" branches-covered="0" `+
+		`branches-valid="0" complexity="0" version="grit" timestamp="1700000000000">
+	<sources>
+		<source>/repo</source>
+	</sources>
+	<packages>
+		<package name="pkg/a" line-rate="0.75" branch-rate="0" complexity="0">
+			<classes>
+				<class name="a.go" filename="pkg/a/a.go" line-rate="0.75" branch-rate="0" complexity="0">
+					<methods></methods>
+					<lines>
+						<line number="3" hits="4"></line>
+						<line number="4" hits="4"></line>
+						<line number="5" hits="4"></line>
+						<line number="6" hits="0"></line>
+					</lines>
+				</class>
+			</classes>
+		</package>
+		<package name="." line-rate="1" branch-rate="0" complexity="0">
+			<classes>
+				<class name="main.go" filename="main.go" line-rate="1" branch-rate="0" complexity="0">
+					<methods></methods>
+					<lines>
+						<line number="1" hits="1"></line>
+					</lines>
+				</class>
+			</classes>
+		</package>
+	</packages>
+</coverage>
+`, out.String())
+
+	profiles, err := parseCobertura(out.Bytes())
+	require.NoError(t, err)
+	assert.Len(t, profiles, 2)
+}
@@ -0,0 +1,250 @@
+package coverage
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/cover"
+)
+
+// Formats of coverage files, reports of other languages are converted to profiles with a block per line.
+const (
+	FormatAuto      = "auto"
+	FormatGo        = "go"
+	FormatLCOV      = "lcov"
+	FormatCobertura = "cobertura"
+	FormatJaCoCo    = "jacoco"
+)
+
+var (
+	Formats = []string{FormatAuto, FormatGo, FormatLCOV, FormatCobertura, FormatJaCoCo}
+
+	ErrUnsupportedFormat = errors.New("unsupported coverage format")
+)
+
+// detectionSize is the number of leading bytes format is detected by.
+const detectionSize = 1024
+
+// DetectFormat detects format of coverage file by its leading content, Go profile is assumed when nothing matches.
+func DetectFormat(data []byte) string {
+	head := data[:min(len(data), detectionSize)]
+	trimmed := bytes.TrimSpace(head)
+
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("mode:")):
+		return FormatGo
+	case bytes.HasPrefix(trimmed, []byte("TN:")) || bytes.HasPrefix(trimmed, []byte("SF:")):
+		return FormatLCOV
+	case bytes.Contains(head, []byte("<coverage")):
+		return FormatCobertura
+	case bytes.Contains(head, []byte("<report")):
+		return FormatJaCoCo
+	default:
+		return FormatGo
+	}
+}
+
+// parsers convert coverage data of every format except FormatAuto to profiles.
+var parsers = map[string]func(data []byte) ([]*cover.Profile, error){
+	FormatGo:        parseGoProfile,
+	FormatLCOV:      parseLCOV,
+	FormatCobertura: parseCobertura,
+	FormatJaCoCo:    parseJaCoCo,
+}
+
+func parseGoProfile(data []byte) ([]*cover.Profile, error) {
+	return cover.ParseProfilesFromReader(bytes.NewReader(data)) //nolint:wrapcheck // wrapped by parseSource
+}
+
+// parseSource reads coverage source in format, which is detected when it is FormatAuto.
+// Native sources, Go profiles and binary coverage directories, name files by import paths,
+// other formats name them by paths in file system.
+func parseSource(source, format string) ([]*cover.Profile, bool, error) {
+	if IsCoverageDir(source) {
+		profiles, err := readCoverageDir(source)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read coverage directory %s: %w", source, err)
+		}
+
+		return profiles, true, nil
+	}
+
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read coverage file: %w", err)
+	}
+
+	if format == FormatAuto || format == "" {
+		format = DetectFormat(data)
+	}
+
+	parse, ok := parsers[format]
+	if !ok {
+		return nil, false, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+
+	profiles, err := parse(data)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to parse %s coverage data: %w", format, err)
+	}
+
+	return profiles, format == FormatGo, nil
+}
+
+// lineProfiles converts hits of lines by file to profiles with a single statement block per line.
+type lineProfiles struct {
+	mode   string
+	files  []string
+	byFile map[string]*cover.Profile
+}
+
+func newLineProfiles(mode string) *lineProfiles {
+	return &lineProfiles{mode: mode, byFile: make(map[string]*cover.Profile)}
+}
+
+func (lp *lineProfiles) add(file string, line, hits int) {
+	profile, ok := lp.byFile[file]
+	if !ok {
+		profile = &cover.Profile{FileName: file, Mode: lp.mode}
+		lp.byFile[file] = profile
+		lp.files = append(lp.files, file)
+	}
+
+	profile.Blocks = append(profile.Blocks, cover.ProfileBlock{
+		StartLine: line, StartCol: 1, EndLine: line, EndCol: 2, NumStmt: 1, Count: hits,
+	})
+}
+
+func (lp *lineProfiles) profiles() []*cover.Profile {
+	result := make([]*cover.Profile, 0, len(lp.files))
+	for _, file := range lp.files {
+		result = append(result, lp.byFile[file])
+	}
+
+	return result
+}
+
+// fileResolver maps paths of other languages reports to repository relative paths. Absolute paths and paths
+// relative to the repository are used as is, other paths are matched by suffix against repository files,
+// e.g. 'com/example/Foo.java' of JaCoCo matches 'src/main/java/com/example/Foo.java'.
+type fileResolver struct {
+	repoPath string
+	files    []string
+}
+
+func newFileResolver(repoPath string) *fileResolver {
+	return &fileResolver{repoPath: repoPath}
+}
+
+func (r *fileResolver) resolve(file string) (string, bool) {
+	if filepath.IsAbs(file) {
+		absRepo, err := filepath.Abs(r.repoPath)
+		if err != nil {
+			return "", false
+		}
+
+		relPath, err := filepath.Rel(absRepo, file)
+		if err != nil || strings.HasPrefix(relPath, "..") {
+			return "", false
+		}
+
+		return relPath, true
+	}
+
+	file = filepath.Clean(filepath.FromSlash(file))
+	if _, err := os.Stat(filepath.Join(r.repoPath, file)); err == nil {
+		return file, true
+	}
+
+	return r.matchSuffix(file)
+}
+
+// resolveImportPath returns the longest repository file that file ends with, e.g. 'pkg/a.go'
+// for 'github.com/user/repo/pkg/a.go', which is how import paths of projects without modules look.
+func (r *fileResolver) resolveImportPath(file string) (string, bool) {
+	if r.files == nil {
+		r.files = r.listFiles()
+	}
+
+	file = filepath.FromSlash(file)
+	match := ""
+
+	for _, candidate := range r.files {
+		if (file == candidate || strings.HasSuffix(file, string(filepath.Separator)+candidate)) &&
+			len(candidate) > len(match) {
+			match = candidate
+		}
+	}
+
+	return match, match != ""
+}
+
+// matchSuffix returns the only repository file ending with file.
+func (r *fileResolver) matchSuffix(file string) (string, bool) {
+	if r.files == nil {
+		r.files = r.listFiles()
+	}
+
+	match := ""
+
+	for _, candidate := range r.files {
+		if strings.HasSuffix(candidate, string(filepath.Separator)+file) {
+			if match != "" {
+				return "", false
+			}
+
+			match = candidate
+		}
+	}
+
+	return match, match != ""
+}
+
+func (r *fileResolver) listFiles() []string {
+	files := make([]string, 0)
+
+	_ = filepath.WalkDir(r.repoPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil //nolint:nilerr // unreadable directories are skipped
+		}
+
+		if entry.IsDir() {
+			if path != r.repoPath && (entry.Name() == ".git" || entry.Name() == "node_modules") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if relPath, err := filepath.Rel(r.repoPath, path); err == nil {
+			files = append(files, relPath)
+		}
+
+		return nil
+	})
+
+	return files
+}
+
+// scanLines calls fn for every line of data.
+func scanLines(data []byte, fn func(line string) error) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		if err := fn(strings.TrimSpace(scanner.Text())); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to scan data: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,236 @@
+package coverage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vbvictor/grit/grit/cmd/flag"
+	"github.com/vbvictor/grit/internal/testutil"
+	"golang.org/x/tools/cover"
+)
+
+const lcovInfo = `TN:
+SF:src/app.js
+DA:1,3
+DA:2,0
+DA:5,1,checksum
+end_of_record
+TN:other
+SF:src/app.js
+DA:2,2
+end_of_record
+`
+
+const coberturaXML = `<?xml version="1.0" ?>
+<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">
+<coverage line-rate="0.5" version="1.9">
+	<sources><source>/nonexistent/build/machine</source></sources>
+	<packages>
+		<package name="app">
+			<classes>
+				<class name="app.py" filename="app/app.py">
+					<methods/>
+					<lines>
+						<line number="1" hits="4"/>
+						<line number="2" hits="0"/>
+					</lines>
+				</class>
+			</classes>
+		</package>
+	</packages>
+</coverage>
+`
+
+const jacocoXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<!DOCTYPE report PUBLIC "-//JACOCO//DTD Report 1.1//EN" "report.dtd">
+<report name="app">
+	<group name="module">
+		<package name="com/example">
+			<class name="com/example/Foo" sourcefilename="Foo.java"/>
+			<sourcefile name="Foo.java">
+				<line nr="3" mi="0" ci="5" mb="0" cb="0"/>
+				<line nr="4" mi="2" ci="0" mb="0" cb="0"/>
+				<line nr="5" mi="0" ci="0" mb="0" cb="0"/>
+			</sourcefile>
+		</package>
+	</group>
+</report>
+`
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"go profile", "mode: set\nfile.go:1.1,2.2 1 1\n", FormatGo},
+		{"lcov", lcovInfo, FormatLCOV},
+		{"lcov without test name", "SF:a.js\nDA:1,1\n", FormatLCOV},
+		{"cobertura", coberturaXML, FormatCobertura},
+		{"jacoco", jacocoXML, FormatJaCoCo},
+		{"unknown", "something else", FormatGo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DetectFormat([]byte(tt.content)))
+		})
+	}
+}
+
+func TestParseLCOV(t *testing.T) {
+	profiles, err := parseLCOV([]byte(lcovInfo))
+	require.NoError(t, err)
+
+	assert.Equal(t, []*cover.Profile{{
+		FileName: "src/app.js",
+		Mode:     ModeCount,
+		Blocks: []cover.ProfileBlock{
+			{StartLine: 1, StartCol: 1, EndLine: 1, EndCol: 2, NumStmt: 1, Count: 3},
+			{StartLine: 2, StartCol: 1, EndLine: 2, EndCol: 2, NumStmt: 1, Count: 0},
+			{StartLine: 5, StartCol: 1, EndLine: 5, EndCol: 2, NumStmt: 1, Count: 1},
+			{StartLine: 2, StartCol: 1, EndLine: 2, EndCol: 2, NumStmt: 1, Count: 2},
+		},
+	}}, profiles)
+}
+
+func TestParseLCOVInvalid(t *testing.T) {
+	for _, content := range []string{"DA:1,1\n", "SF:a.js\nDA:x,1\n", "SF:a.js\nDA:1\n"} {
+		_, err := parseLCOV([]byte(content))
+		require.ErrorIs(t, err, errInvalidLCOV, content)
+	}
+}
+
+func TestParseCobertura(t *testing.T) {
+	profiles, err := parseCobertura([]byte(coberturaXML))
+	require.NoError(t, err)
+
+	assert.Equal(t, []*cover.Profile{{
+		FileName: "app/app.py",
+		Mode:     ModeCount,
+		Blocks: []cover.ProfileBlock{
+			{StartLine: 1, StartCol: 1, EndLine: 1, EndCol: 2, NumStmt: 1, Count: 4},
+			{StartLine: 2, StartCol: 1, EndLine: 2, EndCol: 2, NumStmt: 1, Count: 0},
+		},
+	}}, profiles)
+}
+
+func TestCoberturaFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFile(t, filepath.Join(tmpDir, "src", "app.py"), "")
+
+	assert.Equal(t, filepath.Join(tmpDir, "src", "app.py"),
+		coberturaFile([]string{"/nonexistent", filepath.Join(tmpDir, "src")}, "app.py"))
+	assert.Equal(t, "app.py", coberturaFile([]string{"/nonexistent"}, "app.py"))
+}
+
+func TestParseJaCoCo(t *testing.T) {
+	profiles, err := parseJaCoCo([]byte(jacocoXML))
+	require.NoError(t, err)
+
+	assert.Equal(t, []*cover.Profile{{
+		FileName: "com/example/Foo.java",
+		Mode:     ModeSet,
+		Blocks: []cover.ProfileBlock{
+			{StartLine: 3, StartCol: 1, EndLine: 3, EndCol: 2, NumStmt: 1, Count: 1},
+			{StartLine: 4, StartCol: 1, EndLine: 4, EndCol: 2, NumStmt: 1, Count: 0},
+		},
+	}}, profiles)
+}
+
+func TestFileResolver(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFile(t, filepath.Join(tmpDir, "src", "main", "java", "com", "example", "Foo.java"), "")
+	testutil.WriteFile(t, filepath.Join(tmpDir, "a", "util.js"), "")
+	testutil.WriteFile(t, filepath.Join(tmpDir, "b", "util.js"), "")
+
+	absDir, err := filepath.Abs(tmpDir)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		file   string
+		want   string
+		wantOK bool
+	}{
+		{"relative to repository", "a/util.js", filepath.Join("a", "util.js"), true},
+		{"absolute", filepath.Join(absDir, "a", "util.js"), filepath.Join("a", "util.js"), true},
+		{"absolute outside of repository", "/elsewhere/util.js", "", false},
+		{"unique suffix", "com/example/Foo.java", filepath.Join("src", "main", "java", "com", "example", "Foo.java"), true},
+		{"ambiguous suffix", "util.js", "", false},
+		{"missing", "Bar.java", "", false},
+	}
+
+	resolver := newFileResolver(tmpDir)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := resolver.resolve(tt.file)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReadCoverageForeignFormats(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFile(t, filepath.Join(tmpDir, "src", "app.js"), "")
+	testutil.WriteFile(t, filepath.Join(tmpDir, "src", "main", "java", "com", "example", "Foo.java"), "")
+	testutil.WriteFile(t, filepath.Join(tmpDir, "lcov.info"), lcovInfo)
+	testutil.WriteFile(t, filepath.Join(tmpDir, "jacoco.xml"), jacocoXML)
+
+	var unresolved []string
+
+	opts := &Options{Profiles: []string{"jacoco.xml"}, OnUnresolved: func(file string, reason error) {
+		assert.ErrorIs(t, reason, ErrFileNotFound)
+		unresolved = append(unresolved, file)
+	}}
+	require.NoError(t, PopulateOpts(opts, ""))
+
+	got, err := ReadCoverage(tmpDir, "lcov.info", opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*FileCoverage{
+		{File: filepath.Join("src", "app.js"), Coverage: 100, Statements: 3, Covered: 3, Mode: ModeSet},
+		{
+			File:     filepath.Join("src", "main", "java", "com", "example", "Foo.java"),
+			Coverage: 50, Statements: 2, Covered: 1, Mode: ModeSet,
+		},
+	}, got)
+	assert.Empty(t, unresolved)
+}
+
+func TestReadCoverageExplicitFormat(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFile(t, filepath.Join(tmpDir, "src", "app.js"), "")
+	testutil.WriteFile(t, filepath.Join(tmpDir, "coverage.info"), "SF:src/app.js\nDA:1,0\nDA:2,2\nend_of_record\n")
+
+	opts := &Options{Format: FormatLCOV}
+	require.NoError(t, PopulateOpts(opts, ""))
+
+	got, err := ReadCoverage(tmpDir, "coverage.info", opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*FileCoverage{
+		{File: filepath.Join("src", "app.js"), Coverage: 50, Statements: 2, Covered: 1, Mode: ModeCount, Hits: 2},
+	}, got)
+}
+
+func TestPopulateOptsFormat(t *testing.T) {
+	opts := &Options{}
+	require.NoError(t, PopulateOpts(opts, ""))
+	assert.Equal(t, FormatAuto, opts.Format)
+
+	require.ErrorIs(t, PopulateOpts(&Options{Format: "clover"}, ""), ErrUnsupportedFormat)
+}
+
+func TestEnsureProfileForeignReport(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFile(t, filepath.Join(tmpDir, "lcov.info"), lcovInfo)
+
+	// tests of repository without Go modules would fail if they were run
+	require.NoError(t, ensureProfile(tmpDir, &Options{CoverageFilename: "lcov.info", RunCoverage: flag.Always}))
+	require.Error(t, ensureProfile(tmpDir, &Options{CoverageFilename: "missing.xml", Format: FormatJaCoCo}))
+}
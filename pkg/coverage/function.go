@@ -0,0 +1,243 @@
+package coverage
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/vbvictor/grit/grit/cmd/flag"
+	"github.com/vbvictor/grit/pkg/complexity"
+	"golang.org/x/tools/cover"
+)
+
+// FunctionCoverage is coverage of a function. File, Line and Name identify the function
+// the same way complexity.FunctionStat does, so that both can be joined.
+type FunctionCoverage struct {
+	File       string
+	Line       int
+	EndLine    int
+	Package    string
+	Name       string
+	Coverage   float64
+	Statements int
+	Covered    int
+	// Mode and Hits have the same meaning as in FileCoverage.
+	Mode string
+	Hits int
+}
+
+// HasHits reports whether hit counts of the function were recorded by its profile.
+func (f *FunctionCoverage) HasHits() bool {
+	return f.Mode == ModeCount || f.Mode == ModeAtomic
+}
+
+// GetFunctionCoverageData is GetCoverageData reporting coverage of functions.
+func GetFunctionCoverageData(repoPath string, coverageOpts *Options) ([]*FunctionCoverage, error) {
+	if err := ensureProfile(repoPath, coverageOpts); err != nil {
+		return nil, err
+	}
+
+	covData, err := ReadFunctionCoverage(repoPath, coverageOpts.CoverageFilename, coverageOpts)
+	if err != nil {
+		return nil, errors.Join(flag.ErrReadCoverage, err)
+	}
+
+	return covData, nil
+}
+
+// ReadFunctionCoverage reads profile file at repoPath and intersects its blocks with functions
+// of source files the way 'go tool cover -func' does. Functions without statements are not reported.
+func ReadFunctionCoverage(repoPath, file string, opts *Options) ([]*FunctionCoverage, error) {
+	profiles, err := readProfiles(repoPath, file, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*FunctionCoverage, 0)
+
+	for _, profile := range profiles {
+		functions, err := profileFunctions(repoPath, profile)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, functions...)
+	}
+
+	return results, nil
+}
+
+func profileFunctions(repoPath string, profile *cover.Profile) ([]*FunctionCoverage, error) {
+	if filepath.Ext(profile.FileName) != ".go" {
+		return nil, nil // functions of other languages are unknown
+	}
+
+	fileSet := token.NewFileSet()
+
+	file, err := parser.ParseFile(fileSet, filepath.Join(repoPath, profile.FileName), nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse source of %s: %w", profile.FileName, err)
+	}
+
+	results := make([]*FunctionCoverage, 0)
+
+	for _, function := range fileFunctions(file) {
+		start, end := fileSet.Position(function.node.Pos()), fileSet.Position(function.node.End())
+
+		total, covered, hits := countBlocks(functionBlocks(profile.Blocks, start, end), profile.Mode)
+		if total == 0 {
+			continue
+		}
+
+		results = append(results, &FunctionCoverage{
+			File:       profile.FileName,
+			Line:       start.Line,
+			EndLine:    end.Line,
+			Package:    file.Name.Name,
+			Name:       function.name,
+			Coverage:   float64(covered) * percentMultiplier / float64(total),
+			Statements: total,
+			Covered:    covered,
+			Mode:       profile.Mode,
+			Hits:       hits,
+		})
+	}
+
+	return results, nil
+}
+
+type namedFunction struct {
+	node ast.Node
+	name string
+}
+
+// fileFunctions returns functions with bodies declared in file. Function literals assigned to package level
+// variables are named after the variables, the same way complexity engines report them.
+func fileFunctions(file *ast.File) []namedFunction {
+	functions := make([]namedFunction, 0)
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Body != nil {
+				functions = append(functions, namedFunction{node: decl, name: functionName(decl)})
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				valueSpec, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+
+				for i, value := range valueSpec.Values {
+					if funcLit, ok := value.(*ast.FuncLit); ok && i < len(valueSpec.Names) {
+						functions = append(functions, namedFunction{node: funcLit, name: valueSpec.Names[i].Name})
+					}
+				}
+			}
+		}
+	}
+
+	return functions
+}
+
+// functionBlocks returns blocks located between start and end positions, blocks are sorted by cover package.
+func functionBlocks(blocks []cover.ProfileBlock, start, end token.Position) []cover.ProfileBlock {
+	result := make([]cover.ProfileBlock, 0)
+
+	for _, block := range blocks {
+		if block.StartLine > end.Line || (block.StartLine == end.Line && block.StartCol >= end.Column) {
+			break
+		}
+
+		if block.EndLine < start.Line || (block.EndLine == start.Line && block.EndCol <= start.Column) {
+			continue
+		}
+
+		result = append(result, block)
+	}
+
+	return result
+}
+
+// functionName formats name of function the same way gocyclo does, e.g. '(*Type).Method'.
+func functionName(funcDecl *ast.FuncDecl) string {
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+		return funcDecl.Name.Name
+	}
+
+	return fmt.Sprintf("(%s).%s", receiverName(funcDecl.Recv.List[0].Type), funcDecl.Name.Name)
+}
+
+func receiverName(expr ast.Expr) string {
+	switch typ := expr.(type) {
+	case *ast.Ident:
+		return typ.Name
+	case *ast.StarExpr:
+		return "*" + receiverName(typ.X)
+	case *ast.IndexExpr:
+		return receiverName(typ.X)
+	case *ast.IndexListExpr:
+		return receiverName(typ.X)
+	default:
+		return "BADRECV"
+	}
+}
+
+// AttachFunctionCoverage sets coverage of complexity functions matching functions by file and line,
+// coverage of other functions stays unknown.
+func AttachFunctionCoverage(files []*complexity.FileStat, functions []*FunctionCoverage) {
+	type key struct {
+		file string
+		line int
+	}
+
+	covered := make(map[key]float64, len(functions))
+	for _, function := range functions {
+		covered[key{file: filepath.Clean(function.File), line: function.Line}] = function.Coverage
+	}
+
+	for _, file := range files {
+		for i := range file.Functions {
+			function := &file.Functions[i]
+
+			if coverage, ok := covered[key{file: filepath.Clean(function.File), line: function.Line}]; ok {
+				function.Coverage = &coverage
+			}
+		}
+	}
+}
+
+// SortFunctions sorts functions by coverage, functions with more statements go first among equally covered ones.
+func SortFunctions(functions []*FunctionCoverage, sortBy SortType, limit int) []*FunctionCoverage {
+	slices.SortStableFunc(functions, func(a, b *FunctionCoverage) int {
+		if a.Coverage != b.Coverage {
+			if (a.Coverage < b.Coverage) == (sortBy == Worst) {
+				return -1
+			}
+
+			return 1
+		}
+
+		if a.Statements != b.Statements {
+			return b.Statements - a.Statements
+		}
+
+		if a.File != b.File {
+			return strings.Compare(a.File, b.File)
+		}
+
+		return a.Line - b.Line
+	})
+
+	if limit > 0 && len(functions) > limit {
+		return functions[:limit]
+	}
+
+	return functions
+}
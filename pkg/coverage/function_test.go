@@ -0,0 +1,111 @@
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vbvictor/grit/pkg/complexity"
+)
+
+const functionsSource = `package calc
+
+type Calc struct{}
+
+func Add(a, b int) int {
+	return a + b
+}
+
+func (c *Calc) Abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+
+	return a
+}
+
+func empty() {}
+
+var negate = func(a int) int {
+	return -a
+}
+`
+
+func TestReadFunctionCoverage(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeGoMod(t, tmpDir)
+	require.NoError(t, os.Mkdir(filepath.Join(tmpDir, "calc"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "calc", "calc.go"), []byte(functionsSource), 0o600))
+
+	tmpfile := createTempFile(t, tmpDir, `mode: count
+example.com/name/module/calc/calc.go:5.24,7.2 1 3
+example.com/name/module/calc/calc.go:9.31,10.11 1 2
+example.com/name/module/calc/calc.go:10.11,12.3 1 0
+example.com/name/module/calc/calc.go:14.2,14.10 1 2
+example.com/name/module/calc/calc.go:19.29,21.2 1 0`)
+
+	got, err := ReadFunctionCoverage(tmpDir, filepath.Base(tmpfile.Name()), &Options{})
+	require.NoError(t, err)
+
+	file := filepath.Join("calc", "calc.go")
+	assert.Equal(t, []*FunctionCoverage{
+		{
+			File: file, Line: 5, EndLine: 7, Package: "calc", Name: "Add",
+			Coverage: 100, Statements: 1, Covered: 1, Mode: ModeCount, Hits: 3,
+		},
+		{
+			File: file, Line: 9, EndLine: 15, Package: "calc", Name: "(*Calc).Abs",
+			Coverage: 200.0 / 3, Statements: 3, Covered: 2, Mode: ModeCount, Hits: 4,
+		},
+		{
+			File: file, Line: 19, EndLine: 21, Package: "calc", Name: "negate",
+			Coverage: 0, Statements: 1, Covered: 0, Mode: ModeCount, Hits: 0,
+		},
+	}, got)
+}
+
+func TestSortFunctions(t *testing.T) {
+	functions := []*FunctionCoverage{
+		{Name: "covered", Coverage: 100, Statements: 4},
+		{Name: "small", Coverage: 0, Statements: 1},
+		{Name: "large", Coverage: 0, Statements: 10},
+		{Name: "half", Coverage: 50, Statements: 2},
+	}
+
+	names := func(functions []*FunctionCoverage) []string {
+		result := make([]string, 0, len(functions))
+		for _, function := range functions {
+			result = append(result, function.Name)
+		}
+
+		return result
+	}
+
+	assert.Equal(t, []string{"large", "small", "half"}, names(SortFunctions(functions, Worst, 3)))
+	assert.Equal(t, []string{"covered", "half"}, names(SortFunctions(functions, Best, 2)))
+}
+
+func TestAttachFunctionCoverage(t *testing.T) {
+	files := []*complexity.FileStat{
+		{
+			Path: "calc/calc.go",
+			Functions: []complexity.FunctionStat{
+				{File: "calc/calc.go", Line: 5, Name: "Add"},
+				{File: "calc/calc.go", Line: 17, Name: "empty"},
+			},
+		},
+		{Path: "main.go", Functions: []complexity.FunctionStat{{File: "main.go", Line: 3, Name: "main"}}},
+	}
+
+	AttachFunctionCoverage(files, []*FunctionCoverage{
+		{File: filepath.Join("calc", "calc.go"), Line: 5, Name: "Add", Coverage: 75},
+		{File: "other.go", Line: 3, Name: "main", Coverage: 100},
+	})
+
+	require.NotNil(t, files[0].Functions[0].Coverage)
+	assert.InDelta(t, 75.0, *files[0].Functions[0].Coverage, 0.001)
+	assert.Nil(t, files[0].Functions[1].Coverage)
+	assert.Nil(t, files[1].Functions[0].Coverage)
+}
@@ -0,0 +1,57 @@
+package coverage
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/cover"
+)
+
+var errInvalidLCOV = errors.New("invalid LCOV record")
+
+// parseLCOV converts line data of LCOV tracefile, 'SF:<file>' followed by 'DA:<line>,<hits>' lines,
+// to profiles of count mode. Records of the same file written by different tests are merged later.
+func parseLCOV(data []byte) ([]*cover.Profile, error) {
+	profiles := newLineProfiles(ModeCount)
+	file := ""
+
+	err := scanLines(data, func(line string) error {
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			file = strings.TrimPrefix(line, "SF:")
+		case line == "end_of_record":
+			file = ""
+		case strings.HasPrefix(line, "DA:"):
+			if file == "" {
+				return fmt.Errorf("%w: %s is outside of source file record", errInvalidLCOV, line)
+			}
+
+			fields := strings.Split(strings.TrimPrefix(line, "DA:"), ",")
+			if len(fields) < 2 { //nolint:mnd // line number and hits
+				return fmt.Errorf("%w: %s", errInvalidLCOV, line)
+			}
+
+			lineNumber, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return fmt.Errorf("%w: %s", errInvalidLCOV, line)
+			}
+
+			// some tools write hits as float, e.g. 'DA:3,1.0'
+			hits, err := strconv.ParseFloat(fields[1], 64)
+			if err != nil {
+				return fmt.Errorf("%w: %s", errInvalidLCOV, line)
+			}
+
+			profiles.add(file, lineNumber, max(int(hits), 0))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return profiles.profiles(), nil
+}
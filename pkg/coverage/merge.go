@@ -0,0 +1,122 @@
+package coverage
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/cover"
+)
+
+// mergeCoverProfiles merges profiles of the same files read from several sources. Counts of identical blocks are
+// summed, or combined with OR in set mode. Blocks overlapping a preceding one or matching it with a different
+// number of statements, which only happens when sources were produced from different versions of code,
+// are dropped so that their statements aren't counted twice.
+// Sources of set mode make the result set mode, since hit counts of such sources are unknown.
+func mergeCoverProfiles(sources [][]*cover.Profile) ([]*cover.Profile, error) {
+	mode := ""
+	byFile := make(map[string]*cover.Profile)
+
+	for _, profiles := range sources {
+		for _, profile := range profiles {
+			if profile.Mode != ModeSet && profile.Mode != ModeCount && profile.Mode != ModeAtomic {
+				return nil, fmt.Errorf("%w: %s", errUnsupportedMode, profile.Mode)
+			}
+
+			mode = mergedMode(mode, profile.Mode)
+
+			merged, ok := byFile[profile.FileName]
+			if !ok {
+				merged = &cover.Profile{FileName: profile.FileName}
+				byFile[profile.FileName] = merged
+			}
+
+			merged.Blocks = append(merged.Blocks, profile.Blocks...)
+		}
+	}
+
+	result := make([]*cover.Profile, 0, len(byFile))
+
+	for _, profile := range byFile {
+		profile.Mode = mode
+
+		profile.Blocks = mergeBlocks(profile.Blocks, mode)
+		result = append(result, profile)
+	}
+
+	slices.SortFunc(result, func(a, b *cover.Profile) int {
+		return strings.Compare(a.FileName, b.FileName)
+	})
+
+	return result, nil
+}
+
+func mergedMode(current, mode string) string {
+	switch {
+	case current == "" || current == mode:
+		return mode
+	case current == ModeSet || mode == ModeSet:
+		return ModeSet
+	default:
+		return ModeCount
+	}
+}
+
+func mergeBlocks(blocks []cover.ProfileBlock, mode string) []cover.ProfileBlock {
+	sortBlocks(blocks)
+
+	result := make([]cover.ProfileBlock, 0, len(blocks))
+
+	for _, block := range blocks {
+		if mode == ModeSet {
+			block.Count = min(block.Count, 1)
+		}
+
+		if len(result) == 0 {
+			result = append(result, block)
+
+			continue
+		}
+
+		last := &result[len(result)-1]
+
+		switch {
+		case sameBlock(*last, block) && last.NumStmt == block.NumStmt:
+			if mode == ModeSet {
+				last.Count = max(last.Count, block.Count)
+			} else {
+				last.Count += block.Count
+			}
+		case comparePosition(block.StartLine, block.StartCol, last.EndLine, last.EndCol) < 0:
+			continue // overlaps the preceding block or matches it with different statements
+		default:
+			result = append(result, block)
+		}
+	}
+
+	return result
+}
+
+func sameBlock(a, b cover.ProfileBlock) bool {
+	return a.StartLine == b.StartLine && a.StartCol == b.StartCol && a.EndLine == b.EndLine && a.EndCol == b.EndCol
+}
+
+func comparePosition(line, col, otherLine, otherCol int) int {
+	if line != otherLine {
+		return cmp.Compare(line, otherLine)
+	}
+
+	return cmp.Compare(col, otherCol)
+}
+
+// sortBlocks sorts blocks by start position, then by end position.
+func sortBlocks(blocks []cover.ProfileBlock) {
+	slices.SortStableFunc(blocks, func(a, b cover.ProfileBlock) int {
+		if order := comparePosition(a.StartLine, a.StartCol, b.StartLine, b.StartCol); order != 0 {
+			return order
+		}
+
+		return comparePosition(a.EndLine, a.EndCol, b.EndLine, b.EndCol)
+	})
+}
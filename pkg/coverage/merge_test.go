@@ -0,0 +1,69 @@
+package coverage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/cover"
+)
+
+func TestMergeCoverProfiles(t *testing.T) {
+	first := []*cover.Profile{{FileName: "a.go", Mode: ModeCount, Blocks: []cover.ProfileBlock{
+		{StartLine: 1, StartCol: 1, EndLine: 3, EndCol: 2, NumStmt: 2, Count: 2},
+		{StartLine: 5, StartCol: 1, EndLine: 6, EndCol: 2, NumStmt: 1, Count: 0},
+	}}}
+	second := []*cover.Profile{
+		{FileName: "a.go", Mode: ModeAtomic, Blocks: []cover.ProfileBlock{
+			{StartLine: 1, StartCol: 1, EndLine: 3, EndCol: 2, NumStmt: 2, Count: 3},
+			{StartLine: 2, StartCol: 1, EndLine: 4, EndCol: 2, NumStmt: 5, Count: 1},
+		}},
+		{FileName: "b.go", Mode: ModeAtomic, Blocks: []cover.ProfileBlock{
+			{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 2, NumStmt: 1, Count: 1},
+		}},
+	}
+
+	merged, err := mergeCoverProfiles([][]*cover.Profile{first, second})
+	require.NoError(t, err)
+
+	assert.Equal(t, []*cover.Profile{
+		{FileName: "a.go", Mode: ModeCount, Blocks: []cover.ProfileBlock{
+			{StartLine: 1, StartCol: 1, EndLine: 3, EndCol: 2, NumStmt: 2, Count: 5},
+			{StartLine: 5, StartCol: 1, EndLine: 6, EndCol: 2, NumStmt: 1, Count: 0},
+		}},
+		{FileName: "b.go", Mode: ModeCount, Blocks: []cover.ProfileBlock{
+			{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 2, NumStmt: 1, Count: 1},
+		}},
+	}, merged)
+}
+
+func TestMergeCoverProfilesSetMode(t *testing.T) {
+	set := []*cover.Profile{{FileName: "a.go", Mode: ModeSet, Blocks: []cover.ProfileBlock{
+		{StartLine: 1, StartCol: 1, EndLine: 3, EndCol: 2, NumStmt: 2, Count: 0},
+	}}}
+	count := []*cover.Profile{{FileName: "a.go", Mode: ModeCount, Blocks: []cover.ProfileBlock{
+		{StartLine: 1, StartCol: 1, EndLine: 3, EndCol: 2, NumStmt: 2, Count: 7},
+	}}}
+
+	merged, err := mergeCoverProfiles([][]*cover.Profile{set, count})
+	require.NoError(t, err)
+	require.Len(t, merged, 1)
+	assert.Equal(t, ModeSet, merged[0].Mode)
+	assert.Equal(t, 1, merged[0].Blocks[0].Count)
+}
+
+func TestMergeCoverProfilesInconsistent(t *testing.T) {
+	inconsistent := []*cover.Profile{{FileName: "a.go", Mode: ModeSet, Blocks: []cover.ProfileBlock{
+		{StartLine: 1, StartCol: 1, EndLine: 3, EndCol: 2, NumStmt: 4, Count: 1},
+	}}}
+	base := []*cover.Profile{{FileName: "a.go", Mode: ModeSet, Blocks: []cover.ProfileBlock{
+		{StartLine: 1, StartCol: 1, EndLine: 3, EndCol: 2, NumStmt: 2, Count: 1},
+	}}}
+
+	merged, err := mergeCoverProfiles([][]*cover.Profile{base, inconsistent})
+	require.NoError(t, err)
+	assert.Equal(t, base[0].Blocks, merged[0].Blocks)
+
+	_, err = mergeCoverProfiles([][]*cover.Profile{{{FileName: "a.go", Mode: "unknown"}}})
+	require.ErrorIs(t, err, errUnsupportedMode)
+}
@@ -0,0 +1,58 @@
+package coverage
+
+import "github.com/vbvictor/grit/pkg/module"
+
+// RollupByModule merges files into one entry per module named after the module path,
+// module coverage is the share of covered statements of all its files.
+func RollupByModule(files []*FileCoverage, modules module.Modules) []*FileCoverage {
+	return Rollup(files, modules.Name)
+}
+
+// Rollup sums statements, covered statements and hits of files of every group and computes group coverage
+// from the sums, so that large files weigh more than small ones.
+func Rollup(files []*FileCoverage, group func(path string) string) []*FileCoverage {
+	groups, names := module.GroupBy(files, func(file *FileCoverage) string { return group(file.File) })
+	result := make([]*FileCoverage, 0, len(names))
+
+	for _, name := range names {
+		rollup := &FileCoverage{File: name}
+		for _, file := range groups[name] {
+			rollup.Statements += file.Statements
+			rollup.Covered += file.Covered
+			rollup.Hits += file.Hits
+			rollup.Mode = file.Mode
+		}
+
+		if rollup.Statements > 0 {
+			rollup.Coverage = float64(rollup.Covered) * percentMultiplier / float64(rollup.Statements)
+		}
+
+		result = append(result, rollup)
+	}
+
+	return result
+}
+
+// RollupPatchByModule merges changed files into one entry per module named after the module path,
+// module patch coverage is the share of covered changed lines of all its files.
+// Uncovered lines of different files can't be told apart and aren't kept.
+func RollupPatchByModule(files []*PatchFile, modules module.Modules) []*PatchFile {
+	groups, names := module.GroupBy(files, func(file *PatchFile) string { return modules.Name(file.File) })
+	result := make([]*PatchFile, 0, len(names))
+
+	for _, name := range names {
+		rollup := &PatchFile{File: name}
+		for _, file := range groups[name] {
+			rollup.Lines += file.Lines
+			rollup.Covered += file.Covered
+		}
+
+		if rollup.Lines > 0 {
+			rollup.Coverage = float64(rollup.Covered) * percentMultiplier / float64(rollup.Lines)
+		}
+
+		result = append(result, rollup)
+	}
+
+	return result
+}
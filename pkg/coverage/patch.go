@@ -0,0 +1,180 @@
+package coverage
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/cover"
+)
+
+// PatchFile is coverage of changed lines of a file. Only changed lines holding statements are counted,
+// so that comments, declarations and blank lines don't affect patch coverage.
+type PatchFile struct {
+	File      string
+	Coverage  float64
+	Lines     int
+	Covered   int
+	Uncovered []int
+}
+
+// PatchSummary is coverage of all changed lines.
+type PatchSummary struct {
+	Files    int
+	Lines    int
+	Covered  int
+	Coverage float64
+}
+
+// PatchCoverage joins changed lines of files with line hits of profiles, both keyed by repository relative paths.
+// Files without changed statements and files missing from profiles, whose statements are unknown, are skipped,
+// profiles of changed Go files are completed by MissingProfiles.
+func PatchCoverage(profiles []*cover.Profile, changed map[string][]int) []*PatchFile {
+	byFile := make(map[string]*cover.Profile, len(profiles))
+	for _, profile := range profiles {
+		byFile[filepath.Clean(profile.FileName)] = profile
+	}
+
+	results := make([]*PatchFile, 0)
+
+	for file, lines := range changed {
+		profile, ok := byFile[filepath.Clean(file)]
+		if !ok {
+			continue
+		}
+
+		result := patchFile(file, lines, profile)
+		if result.Lines == 0 {
+			continue
+		}
+
+		results = append(results, result)
+	}
+
+	// least covered files first
+	slices.SortFunc(results, func(a, b *PatchFile) int {
+		if a.Coverage != b.Coverage {
+			if a.Coverage < b.Coverage {
+				return -1
+			}
+
+			return 1
+		}
+
+		return strings.Compare(a.File, b.File)
+	})
+
+	return results
+}
+
+// patchFile matches changed lines of file with line hits of its profile, lines without statements aren't counted.
+func patchFile(file string, lines []int, profile *cover.Profile) *PatchFile {
+	hits := make(map[int]int)
+	for _, line := range profileLines(profile) {
+		hits[line.Line] = line.Hits
+	}
+
+	result := &PatchFile{File: file}
+
+	for _, line := range lines {
+		count, coverable := hits[line]
+		if !coverable {
+			continue
+		}
+
+		result.Lines++
+
+		if count > 0 {
+			result.Covered++
+		} else {
+			result.Uncovered = append(result.Uncovered, line)
+		}
+	}
+
+	if result.Lines > 0 {
+		result.Coverage = float64(result.Covered) * percentMultiplier / float64(result.Lines)
+	}
+
+	return result
+}
+
+// MissingProfiles returns profiles with zero counts for changed Go files that go test builds but profiles have
+// no coverage data for, e.g. files left out of reports of other tools, so that PatchCoverage counts their changed
+// statements as not covered. Test files and files excluded by opts are skipped.
+func MissingProfiles(
+	repoPath string, profiles []*cover.Profile, changed map[string][]int, opts *Options,
+) ([]*cover.Profile, error) {
+	mode := ModeSet
+	profiled := make(map[string]bool, len(profiles))
+
+	for _, profile := range profiles {
+		mode = profile.Mode
+		profiled[filepath.Clean(profile.FileName)] = true
+	}
+
+	buildContext := testBuildContext(opts)
+	results := make([]*cover.Profile, 0)
+
+	for file := range changed {
+		relPath := filepath.Clean(file)
+		path := filepath.Join(repoPath, relPath)
+
+		if profiled[relPath] || !isBuiltGoFile(&buildContext, path) ||
+			opts.Tests.Skip(relPath) || isExcluded(opts.ExcludeRegex, file, relPath) {
+			continue
+		}
+
+		blocks, err := statementBlocks(path)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, &cover.Profile{FileName: relPath, Mode: mode, Blocks: blocks})
+	}
+
+	slices.SortFunc(results, func(a, b *cover.Profile) int {
+		return strings.Compare(a.FileName, b.FileName)
+	})
+
+	return results, nil
+}
+
+// SummarizePatch returns coverage of changed lines of all files, which is 100% when no statements were changed.
+func SummarizePatch(files []*PatchFile) PatchSummary {
+	summary := PatchSummary{Files: len(files), Coverage: percentMultiplier}
+
+	for _, file := range files {
+		summary.Lines += file.Lines
+		summary.Covered += file.Covered
+	}
+
+	if summary.Lines > 0 {
+		summary.Coverage = float64(summary.Covered) * percentMultiplier / float64(summary.Lines)
+	}
+
+	return summary
+}
+
+// LineRanges formats sorted lines as ranges, e.g. '3-5, 9'.
+func LineRanges(lines []int) string {
+	ranges := make([]string, 0)
+
+	for i := 0; i < len(lines); {
+		j := i
+		for j+1 < len(lines) && lines[j+1] == lines[j]+1 {
+			j++
+		}
+
+		if i == j {
+			ranges = append(ranges, strconv.Itoa(lines[i]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", lines[i], lines[j]))
+		}
+
+		i = j + 1
+	}
+
+	return strings.Join(ranges, ", ")
+}
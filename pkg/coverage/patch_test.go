@@ -0,0 +1,113 @@
+package coverage
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vbvictor/grit/internal/testutil"
+	"github.com/vbvictor/grit/pkg/module"
+	"golang.org/x/tools/cover"
+)
+
+func TestPatchCoverage(t *testing.T) {
+	profiles := []*cover.Profile{
+		{
+			FileName: filepath.Join("pkg", "a.go"),
+			Mode:     ModeSet,
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 3, StartCol: 20, EndLine: 5, EndCol: 3, NumStmt: 2, Count: 1},
+				{StartLine: 5, StartCol: 3, EndLine: 8, EndCol: 1, NumStmt: 2, Count: 0},
+			},
+		},
+		{
+			FileName: "b.go",
+			Mode:     ModeSet,
+			Blocks:   []cover.ProfileBlock{{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 5, NumStmt: 1, Count: 1}},
+		},
+	}
+
+	changed := map[string][]int{
+		filepath.Join("pkg", "a.go"): {1, 4, 6, 7, 20},
+		"b.go":                       {2},
+		"c.go":                       {1, 2},
+		"README.md":                  {1},
+	}
+
+	got := PatchCoverage(profiles, changed)
+
+	assert.Equal(t, []*PatchFile{
+		{File: filepath.Join("pkg", "a.go"), Coverage: 100.0 / 3, Lines: 3, Covered: 1, Uncovered: []int{6, 7}},
+		{File: "b.go", Coverage: 100, Lines: 1, Covered: 1},
+	}, got)
+
+	assert.Equal(t, PatchSummary{Files: 2, Lines: 4, Covered: 2, Coverage: 50}, SummarizePatch(got))
+}
+
+func TestMissingProfiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"a.go":           "package a\n",
+		"c.go":           "package a\n\nfunc C() int {\n\treturn 1\n}\n",
+		"c_test.go":      "package a\n",
+		"gen/gen.go":     "package gen\n",
+		"other_plan9.go": "package a\n",
+		"README.md":      "# a\n",
+	})
+
+	profiles := []*cover.Profile{{FileName: "a.go", Mode: ModeSet}}
+	changed := map[string][]int{
+		"a.go": {1}, "c.go": {1}, "c_test.go": {1}, filepath.Join("gen", "gen.go"): {1}, "other_plan9.go": {1},
+		"README.md": {1},
+	}
+
+	opts := &Options{ExcludeRegex: regexp.MustCompile("^gen/")}
+
+	got, err := MissingProfiles(tmpDir, profiles, changed, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []*cover.Profile{
+		{
+			FileName: "c.go",
+			Mode:     ModeSet,
+			Blocks:   []cover.ProfileBlock{{StartLine: 4, StartCol: 2, EndLine: 4, EndCol: 10, NumStmt: 1}},
+		},
+	}, got)
+}
+
+func TestSummarizePatchWithoutStatements(t *testing.T) {
+	assert.Equal(t, PatchSummary{Coverage: 100}, SummarizePatch(nil))
+}
+
+func TestRollupPatchByModule(t *testing.T) {
+	modules := module.Modules{{Path: "example.com/root", Dir: "."}, {Path: "example.com/root/tools", Dir: "tools"}}
+	files := []*PatchFile{
+		{File: "main.go", Lines: 2, Covered: 2, Coverage: 100},
+		{File: filepath.Join("pkg", "a.go"), Lines: 6, Covered: 2, Coverage: 33.33, Uncovered: []int{3, 4, 5, 6}},
+		{File: filepath.Join("tools", "gen.go"), Lines: 4, Covered: 1, Coverage: 25, Uncovered: []int{1, 2, 3}},
+	}
+
+	assert.Equal(t, []*PatchFile{
+		{File: "example.com/root", Lines: 8, Covered: 4, Coverage: 50},
+		{File: "example.com/root/tools", Lines: 4, Covered: 1, Coverage: 25},
+	}, RollupPatchByModule(files, modules))
+}
+
+func TestLineRanges(t *testing.T) {
+	assert.Empty(t, LineRanges(nil))
+	assert.Equal(t, "3", LineRanges([]int{3}))
+	assert.Equal(t, "3-5, 9, 11-12", LineRanges([]int{3, 4, 5, 9, 11, 12}))
+}
+
+func TestPrintPatchCSV(t *testing.T) {
+	var out bytes.Buffer
+
+	PrintPatchCSV([]*PatchFile{
+		{File: "a.go", Coverage: 50, Lines: 4, Covered: 2, Uncovered: []int{3, 4}},
+		{File: "b.go", Coverage: 100, Lines: 1, Covered: 1},
+	}, &out)
+
+	assert.Equal(t, "filepath,coverage,changed,covered,uncovered\na.go,50.00,4,2,3;4\nb.go,100.00,1,1,\n", out.String())
+}
@@ -3,13 +3,23 @@ package coverage
 import (
 	"fmt"
 	"io"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/bndr/gotabulate"
 )
 
+// hasHits reports whether results were read from count or atomic profiles, hits column is printed only for them.
+func hasHits(results []*FileCoverage) bool {
+	return slices.ContainsFunc(results, (*FileCoverage).HasHits)
+}
+
 func PrintTabular(results []*FileCoverage, out io.Writer) {
 	fmt.Fprintf(out, "\nCode coverage analysis results:\n")
 
+	withHits := hasHits(results)
+
 	data := make([][]any, len(results))
 	for i, result := range results {
 		data[i] = []any{
@@ -18,24 +28,150 @@ func PrintTabular(results []*FileCoverage, out io.Writer) {
 			result.Statements,
 			result.Covered,
 		}
+
+		if withHits {
+			data[i] = append(data[i], result.Hits)
+		}
+	}
+
+	headers := []string{"FILEPATH", "COVERAGE", "STATEMENTS", "COVERED"}
+	if withHits {
+		headers = append(headers, "HITS")
 	}
 
 	table := gotabulate.Create(data)
-	table.SetHeaders([]string{"FILEPATH", "COVERAGE", "STATEMENTS", "COVERED"})
+	table.SetHeaders(headers)
 	table.SetAlign("left")
 
 	_, _ = io.WriteString(out, table.Render("grid"))
 }
 
 func PrintCSV(results []*FileCoverage, out io.Writer) {
-	_, _ = fmt.Fprintln(out, "filepath,coverage,statements,covered")
+	withHits := hasHits(results)
+
+	if withHits {
+		_, _ = fmt.Fprintln(out, "filepath,coverage,statements,covered,hits")
+	} else {
+		_, _ = fmt.Fprintln(out, "filepath,coverage,statements,covered")
+	}
+
+	for _, result := range results {
+		_, _ = fmt.Fprintf(out, "%s,%.2f,%d,%d",
+			result.File,
+			result.Coverage,
+			result.Statements,
+			result.Covered,
+		)
+
+		if withHits {
+			_, _ = fmt.Fprintf(out, ",%d", result.Hits)
+		}
+
+		_, _ = fmt.Fprintln(out)
+	}
+}
+
+func PrintFunctionsTabular(results []*FunctionCoverage, out io.Writer) {
+	fmt.Fprintf(out, "\nFunction coverage analysis results:\n")
+
+	withHits := slices.ContainsFunc(results, (*FunctionCoverage).HasHits)
+
+	data := make([][]any, len(results))
+	for i, result := range results {
+		data[i] = []any{
+			result.File,
+			result.Line,
+			result.Name,
+			fmt.Sprintf("%.2f%%", result.Coverage),
+			result.Statements,
+			result.Covered,
+		}
+
+		if withHits {
+			data[i] = append(data[i], result.Hits)
+		}
+	}
+
+	headers := []string{"FILEPATH", "LINE", "FUNCTION", "COVERAGE", "STATEMENTS", "COVERED"}
+	if withHits {
+		headers = append(headers, "HITS")
+	}
+
+	table := gotabulate.Create(data)
+	table.SetHeaders(headers)
+	table.SetAlign("left")
+
+	_, _ = io.WriteString(out, table.Render("grid"))
+}
+
+func PrintFunctionsCSV(results []*FunctionCoverage, out io.Writer) {
+	withHits := slices.ContainsFunc(results, (*FunctionCoverage).HasHits)
+
+	if withHits {
+		_, _ = fmt.Fprintln(out, "filepath,line,function,coverage,statements,covered,hits")
+	} else {
+		_, _ = fmt.Fprintln(out, "filepath,line,function,coverage,statements,covered")
+	}
 
 	for _, result := range results {
-		_, _ = fmt.Fprintf(out, "%s,%.2f,%d,%d\n",
+		_, _ = fmt.Fprintf(out, "%s,%d,%s,%.2f,%d,%d",
 			result.File,
+			result.Line,
+			result.Name,
 			result.Coverage,
 			result.Statements,
 			result.Covered,
 		)
+
+		if withHits {
+			_, _ = fmt.Fprintf(out, ",%d", result.Hits)
+		}
+
+		_, _ = fmt.Fprintln(out)
+	}
+}
+
+func PrintPatchTabular(results []*PatchFile, summary PatchSummary, out io.Writer) {
+	_, _ = io.WriteString(out, "\nPatch coverage results:\n")
+
+	if len(results) > 0 {
+		data := make([][]any, len(results))
+		for i, result := range results {
+			data[i] = []any{
+				result.File,
+				fmt.Sprintf("%.2f%%", result.Coverage),
+				result.Lines,
+				result.Covered,
+				LineRanges(result.Uncovered),
+			}
+		}
+
+		table := gotabulate.Create(data)
+		table.SetHeaders([]string{"FILEPATH", "COVERAGE", "CHANGED LINES", "COVERED", "UNCOVERED LINES"})
+		table.SetAlign("left")
+
+		_, _ = io.WriteString(out, table.Render("grid"))
+	}
+
+	PrintPatchSummary(summary, out)
+}
+
+// PrintPatchSummary prints coverage of all changed lines.
+func PrintPatchSummary(summary PatchSummary, out io.Writer) {
+	_, _ = fmt.Fprintf(out, "Patch coverage: %.2f%% (%d of %d changed lines in %d files covered)\n",
+		summary.Coverage, summary.Covered, summary.Lines, summary.Files)
+}
+
+func PrintPatchCSV(results []*PatchFile, out io.Writer) {
+	_, _ = fmt.Fprintln(out, "filepath,coverage,changed,covered,uncovered")
+
+	for _, result := range results {
+		uncovered := make([]string, len(result.Uncovered))
+		for i, line := range result.Uncovered {
+			uncovered[i] = strconv.Itoa(line)
+		}
+
+		_, _ = fmt.Fprintf(out, "%s,%.2f,%d,%d,%s\n",
+			result.File, result.Coverage, result.Lines, result.Covered, strings.Join(uncovered, ";"))
 	}
 }
@@ -110,6 +110,20 @@ func TestPrintCSV(t *testing.T) {
 			},
 			expected: "filepath,coverage,statements,covered\npath/to/foo.go,90.00,50,45\nbar.go,60.50,200,121\n",
 		},
+		{
+			name: "count mode coverage",
+			input: []*FileCoverage{
+				{
+					File:       "main.go",
+					Coverage:   50.0,
+					Statements: 4,
+					Covered:    2,
+					Mode:       ModeCount,
+					Hits:       17,
+				},
+			},
+			expected: "filepath,coverage,statements,covered,hits\nmain.go,50.00,4,2,17\n",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -124,3 +138,16 @@ func TestPrintCSV(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintFunctionsCSV(t *testing.T) {
+	var buf bytes.Buffer
+
+	PrintFunctionsCSV([]*FunctionCoverage{
+		{File: "calc.go", Line: 9, Name: "(*Calc).Abs", Coverage: 50, Statements: 4, Covered: 2},
+	}, &buf)
+
+	expected := "filepath,line,function,coverage,statements,covered\ncalc.go,9,(*Calc).Abs,50.00,4,2\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("PrintFunctionsCSV() = %q, want %q", got, expected)
+	}
+}
@@ -9,14 +9,33 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/vbvictor/grit/grit/cmd/flag"
+	"github.com/vbvictor/grit/pkg/gotest"
+	"github.com/vbvictor/grit/pkg/module"
+	"github.com/vbvictor/grit/pkg/testfiles"
 	"golang.org/x/tools/cover"
 )
 
-var errUnsupportedMode = errors.New("unsupported coverage mode")
+var (
+	errUnsupportedMode = errors.New("unsupported coverage mode")
+	errTestsFailed     = errors.New("failed to run tests")
+
+	// ErrNoModule is passed to OnUnresolved for files of Go profiles whose import paths match none of modules.
+	ErrNoModule = errors.New("it doesn't belong to any module of the repository")
+	// ErrFileNotFound is passed to OnUnresolved for files of reports that match no file of the repository.
+	ErrFileNotFound = errors.New("it is not found in the repository")
+)
+
+// Coverage profile modes, count and atomic profiles hold the number of times each block was executed.
+const (
+	ModeSet    = "set"
+	ModeCount  = "count"
+	ModeAtomic = "atomic"
+)
 
 const (
 	percentMultiplier = 100.0
@@ -35,6 +54,15 @@ type FileCoverage struct {
 	Coverage   float64
 	Statements int
 	Covered    int
+	// Mode is the mode of coverage profile the file was read from.
+	Mode string
+	// Hits is the number of executed statements counting every execution, it is known only for count and atomic modes.
+	Hits int
+}
+
+// HasHits reports whether hit counts of the file were recorded by its profile.
+func (f *FileCoverage) HasHits() bool {
+	return f.Mode == ModeCount || f.Mode == ModeAtomic
 }
 
 type Options struct {
@@ -44,6 +72,25 @@ type Options struct {
 	RunCoverage      string
 	CoverageFilename string
 	OutputFormat     string
+	Tests            testfiles.Filter
+	// Modules of analyzed repository, discovered at analyzed path when not set.
+	Modules module.Modules
+	// OnUnresolved is called for every file of coverage sources that can't be matched to a repository file
+	// with the reason, ErrNoModule or ErrFileNotFound, such files are skipped.
+	OnUnresolved func(file string, reason error)
+	// PerFunction makes commands report coverage of functions instead of files.
+	PerFunction bool
+	// Profiles are additional profiles or GOCOVERDIR directories merged with CoverageFilename,
+	// relative paths are relative to the analyzed repository.
+	Profiles []string
+	// Runner configures go test run to create coverage profile.
+	Runner gotest.Options
+	// OnTestFailure is called for every package whose tests failed, coverage of other packages is still reported.
+	OnTestFailure func(failure gotest.Failure)
+	// Format is one of Formats, format of every coverage source is detected by its content when it is FormatAuto.
+	Format string
+	// NoUntested disables reporting Go files missing from Go profiles as not covered.
+	NoUntested bool
 }
 
 func PopulateOpts(opts *Options, excludeRegex string) error {
@@ -56,11 +103,57 @@ func PopulateOpts(opts *Options, excludeRegex string) error {
 		}
 	}
 
+	if err := opts.Tests.Compile(); err != nil {
+		return fmt.Errorf("invalid tests option: %w", err)
+	}
+
+	if err := opts.Runner.Validate(); err != nil {
+		return err
+	}
+
+	if opts.Format == "" {
+		opts.Format = FormatAuto
+	}
+
+	if !slices.Contains(Formats, opts.Format) {
+		return fmt.Errorf("%w: %s, expected one of %s", ErrUnsupportedFormat, opts.Format, strings.Join(Formats, ", "))
+	}
+
 	return nil
 }
 
 func GetCoverageData(repoPath string, coverageOpts *Options) ([]*FileCoverage, error) {
-	coveragePath := filepath.Join(repoPath, coverageOpts.CoverageFilename)
+	if err := ensureProfile(repoPath, coverageOpts); err != nil {
+		return nil, err
+	}
+
+	covData, err := ReadCoverage(repoPath, coverageOpts.CoverageFilename, coverageOpts)
+	if err != nil {
+		return nil, errors.Join(flag.ErrReadCoverage, err)
+	}
+
+	return covData, nil
+}
+
+// ensureProfile runs tests to create coverage profile of repoPath when it is missing or opts.RunCoverage requires it.
+func ensureProfile(repoPath string, coverageOpts *Options) error {
+	coveragePath := sourcePath(repoPath, coverageOpts.CoverageFilename)
+
+	if IsCoverageDir(coveragePath) {
+		flag.LogIfVerbose("Coverage directory %s is used as is, tests are not run\n", coveragePath)
+
+		return nil
+	}
+
+	if isForeignReport(coveragePath, coverageOpts.Format) {
+		if _, err := os.Stat(coveragePath); err != nil {
+			return errors.Join(flag.ErrCoverageNotFound, err)
+		}
+
+		flag.LogIfVerbose("Coverage report %s is used as is, tests are not run\n", coveragePath)
+
+		return nil
+	}
 
 	_, err := os.Stat(coveragePath)
 	if os.IsNotExist(err) {
@@ -69,63 +162,61 @@ func GetCoverageData(repoPath string, coverageOpts *Options) ([]*FileCoverage, e
 		if coverageOpts.RunCoverage != flag.Never {
 			flag.LogIfVerbose("Running test suite\n\n")
 
-			if err = RunCoverage(repoPath, coverageOpts.CoverageFilename); err != nil {
-				return nil, errors.Join(flag.ErrRunCoverage, err)
+			if err = RunCoverage(repoPath, coverageOpts.CoverageFilename, coverageOpts); err != nil {
+				return errors.Join(flag.ErrRunCoverage, err)
 			}
 
 			flag.LogIfVerbose("Coverage file %s created\n", coveragePath)
 		} else {
 			flag.LogIfVerbose("Go test did not run since flag run='Never'\n")
 
-			return nil, errors.Join(flag.ErrCoverageNotFound, err)
+			return errors.Join(flag.ErrCoverageNotFound, err)
 		}
 	} else if coverageOpts.RunCoverage == flag.Always {
 		flag.LogIfVerbose("Removing previous test coverage file %s\n", coveragePath)
 		os.Remove(coveragePath)
 		flag.LogIfVerbose("Running test suite\n\n")
 
-		if err = RunCoverage(repoPath, coverageOpts.CoverageFilename); err != nil {
-			return nil, errors.Join(flag.ErrRunCoverage, err)
+		if err = RunCoverage(repoPath, coverageOpts.CoverageFilename, coverageOpts); err != nil {
+			return errors.Join(flag.ErrRunCoverage, err)
 		}
 
 		flag.LogIfVerbose("Coverage file %s created\n", coveragePath)
 	}
 
-	covData, err := ReadCoverage(repoPath, coverageOpts.CoverageFilename, coverageOpts)
+	return nil
+}
+
+// isForeignReport reports whether coverage file is a report of other language, which go test can't produce.
+func isForeignReport(coveragePath, format string) bool {
+	if format != FormatAuto && format != "" {
+		return format != FormatGo
+	}
+
+	file, err := os.Open(coveragePath)
 	if err != nil {
-		return nil, errors.Join(flag.ErrReadCoverage, err)
+		return false
 	}
+	defer file.Close()
 
-	return covData, nil
+	head := make([]byte, detectionSize)
+	n, _ := io.ReadFull(file, head)
+
+	return DetectFormat(head[:n]) != FormatGo
 }
 
+// ReadCoverage reads profile file at path. Files of Go profiles are mapped to repository relative paths
+// by import paths of opts.Modules, which are discovered at path when not set.
 func ReadCoverage(path, file string, opts *Options) ([]*FileCoverage, error) {
-	profiles, err := cover.ParseProfiles(filepath.Join(path, file))
+	profiles, err := readProfiles(path, file, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse profiles data: %w", err)
+		return nil, err
 	}
 
-	results := make([]*FileCoverage, 0)
+	results := make([]*FileCoverage, 0, len(profiles))
 
 	for _, profile := range profiles {
-		if opts.ExcludeRegex != nil && opts.ExcludeRegex.MatchString(profile.FileName) {
-			continue
-		}
-
-		total := 0
-		covered := 0
-
-		if profile.Mode != "set" {
-			return nil, fmt.Errorf("%w: %s", errUnsupportedMode, profile.Mode)
-		}
-
-		for _, block := range profile.Blocks {
-			total += block.NumStmt
-
-			if block.Count > 0 {
-				covered += block.NumStmt
-			}
-		}
+		total, covered, hits := countBlocks(profile.Blocks, profile.Mode)
 
 		coverage := 0.0
 		if total > 0 {
@@ -133,33 +224,153 @@ func ReadCoverage(path, file string, opts *Options) ([]*FileCoverage, error) {
 		}
 
 		results = append(results, &FileCoverage{
-			File:       extractRelativePath(profile.FileName),
+			File:       profile.FileName,
 			Coverage:   coverage,
 			Statements: total,
 			Covered:    covered,
+			Mode:       profile.Mode,
+			Hits:       hits,
 		})
 	}
 
 	return results, nil
 }
 
-const minPathPaths = 3
+// readProfiles parses profile file merged with opts.Profiles, files of returned profiles are repository relative
+// paths and files excluded by opts are skipped. Any of sources can be a directory with binary coverage data written
+// by binaries built with -cover or a report of other languages in one of Formats. When any of sources is native,
+// Go files missing from them are added with zero counts, so that packages without tests are reported too,
+// unless opts.NoUntested is set.
+func readProfiles(path, file string, opts *Options) ([]*cover.Profile, error) {
+	modules, err := repositoryModules(path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	files := newFileResolver(path)
+	resolvers := map[bool]resolver{
+		true:  {resolve: modules.Resolve, reason: ErrNoModule},
+		false: {resolve: files.resolve, reason: ErrFileNotFound},
+	}
+
+	if len(modules) == 0 {
+		// import paths of GOPATH projects are matched to repository files by their trailing components
+		resolvers[true] = resolver{resolve: files.resolveImportPath, reason: ErrFileNotFound}
+	}
+
+	sources := make([][]*cover.Profile, 0, len(opts.Profiles)+1)
+	hasNative := false
+
+	for _, source := range append([]string{file}, opts.Profiles...) {
+		profiles, native, err := parseSource(sourcePath(path, source), opts.Format)
+		if err != nil {
+			return nil, err
+		}
+
+		hasNative = hasNative || native
+		sources = append(sources, filterProfiles(profiles, resolvers[native], opts))
+	}
+
+	profiles, err := mergeCoverProfiles(sources)
+	if err != nil || !hasNative || opts.NoUntested {
+		return profiles, err
+	}
 
-func extractRelativePath(fullPath string) string {
-	// Convert to consistent path format
-	fullPath = filepath.FromSlash(fullPath)
+	untested, err := untestedProfiles(path, profiles, modules, opts)
+	if err != nil {
+		return nil, err
+	}
 
-	// Split the path by separator
-	parts := strings.Split(fullPath, string(os.PathSeparator))
+	profiles = append(profiles, untested...)
+	slices.SortFunc(profiles, func(a, b *cover.Profile) int {
+		return strings.Compare(a.FileName, b.FileName)
+	})
 
-	// If we have at least 3 components (typically github.com/username/module/...)
-	if len(parts) > minPathPaths {
-		// Skip the first two components (domain and username)
-		return filepath.Join(parts[minPathPaths:]...)
+	return profiles, nil
+}
+
+// repositoryModules returns opts.Modules or modules discovered at repoPath when they are not set.
+func repositoryModules(repoPath string, opts *Options) (module.Modules, error) {
+	if opts.Modules != nil {
+		return opts.Modules, nil
+	}
+
+	modules, err := module.Discover(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover modules: %w", err)
 	}
 
-	// Fallback to original path if it doesn't have enough components
-	return fullPath
+	return modules, nil
+}
+
+// resolver maps file names of a coverage source to repository relative paths, reason explains failures.
+type resolver struct {
+	resolve func(file string) (string, bool)
+	reason  error
+}
+
+// filterProfiles replaces names of files with repository relative paths and skips files excluded by opts.
+// Test patterns are matched against relative paths, the same way churn and complexity match them. Exclude pattern
+// is matched against file names as written to the source too, so that patterns of import paths keep working.
+func filterProfiles(profiles []*cover.Profile, files resolver, opts *Options) []*cover.Profile {
+	results := make([]*cover.Profile, 0, len(profiles))
+
+	for _, profile := range profiles {
+		relPath, ok := files.resolve(profile.FileName)
+		if !ok {
+			if opts.OnUnresolved != nil {
+				opts.OnUnresolved(profile.FileName, files.reason)
+			}
+
+			continue
+		}
+
+		if isExcluded(opts.ExcludeRegex, profile.FileName, relPath) {
+			continue
+		}
+
+		if opts.Tests.Skip(relPath) {
+			continue
+		}
+
+		profile.FileName = relPath
+		results = append(results, profile)
+	}
+
+	return results
+}
+
+func isExcluded(excludeRegex *regexp.Regexp, fileName, relPath string) bool {
+	return excludeRegex != nil && (excludeRegex.MatchString(filepath.ToSlash(relPath)) ||
+		excludeRegex.MatchString(fileName))
+}
+
+// sourcePath returns path of coverage source relative to repoPath unless it is absolute.
+func sourcePath(repoPath, source string) string {
+	if filepath.IsAbs(source) {
+		return source
+	}
+
+	return filepath.Join(repoPath, source)
+}
+
+// countBlocks returns the number of statements, covered statements and statement executions of blocks.
+func countBlocks(blocks []cover.ProfileBlock, mode string) (int, int, int) {
+	total, covered, hits := 0, 0, 0
+
+	for _, block := range blocks {
+		total += block.NumStmt
+
+		if block.Count > 0 {
+			covered += block.NumStmt
+		}
+
+		if mode != ModeSet {
+			hits += block.NumStmt * block.Count
+		}
+	}
+
+	return total, covered, hits
 }
 
 func SortAndLimit(result []*FileCoverage, sortBy SortType, limit int) []*FileCoverage {
@@ -183,33 +394,163 @@ func SortAndLimit(result []*FileCoverage, sortBy SortType, limit int) []*FileCov
 	return result
 }
 
-func RunCoverage(repoPath, coverageFile string) error {
-	cmd := exec.Command( //nolint:gosec // go test is allowed command
-		"go",
-		"test",
-		"./...",
-		"-v",
-		"-coverprofile="+coverageFile)
-	cmd.Dir = repoPath
+// RunCoverage runs tests of every module in repoPath and writes coverage profile to coverageFile.
+// Nested modules are tested separately and their profiles are merged, since 'go test ./...' doesn't cross
+// module boundaries. Tests are run with opts.Runner and their failures are reported to opts.OnTestFailure.
+func RunCoverage(repoPath, coverageFile string, opts *Options) error {
+	modules, err := repositoryModules(repoPath, opts)
+	if err != nil {
+		return err
+	}
+
+	if len(modules) == 0 || (len(modules) == 1 && modules[0].Dir == ".") {
+		return runModuleCoverage(repoPath, coverageFile, opts)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "grit-coverage-")
+	if err != nil {
+		return fmt.Errorf("failed to create directory for module profiles: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	profiles := make([]string, 0, len(modules))
+
+	for pos, mod := range modules {
+		flag.LogIfVerbose("Running tests of module %s\n", mod.Path)
+
+		profile := filepath.Join(tmpDir, fmt.Sprintf("%d.out", pos))
+
+		err := runModuleCoverage(filepath.Join(repoPath, mod.Dir), profile, opts)
+		if errors.Is(err, errTestsFailed) {
+			// failures are already reported, coverage of other modules is kept
+			flag.LogIfVerbose("Skipping coverage of module %s: %v\n", mod.Path, err)
+
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("module %s: %w", mod.Path, err)
+		}
+
+		profiles = append(profiles, profile)
+	}
+
+	if len(profiles) == 0 {
+		return fmt.Errorf("%w: no module has coverage data", errTestsFailed)
+	}
+
+	return mergeProfiles(profiles, sourcePath(repoPath, coverageFile))
+}
+
+// runModuleCoverage runs tests of module at dir configured by opts.Runner. Failures of packages don't fail
+// the run as long as go test writes coverage data, they are passed to opts.OnTestFailure instead, so that coverage
+// of other packages, and of failed packages up to the failure, is kept.
+func runModuleCoverage(dir, coverageFile string, opts *Options) error {
+	cmd := exec.Command("go", opts.Runner.Args(coverageFile)...) //nolint:gosec // go test is allowed command
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), opts.Runner.Env...)
 
 	flag.LogIfVerbose("Running command: %s\n", cmd.String())
 
 	var stderr bytes.Buffer
 
+	output := io.Discard
+	cmd.Stderr = &stderr
+
 	if flag.Verbose {
-		cmd.Stdout = os.Stdout
+		output = os.Stdout
 		cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
-	} else {
-		cmd.Stderr = &stderr
 	}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to run tests: %w\nstderr: %s", err, stderr.String())
+	events, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to run tests: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to run tests: %w", err)
+	}
+
+	summary, readErr := gotest.ReadEvents(events, output)
+
+	err = errors.Join(cmd.Wait(), readErr)
+	if err == nil {
+		return nil
+	}
+
+	return testFailuresError(sourcePath(dir, coverageFile), summary, err, stderr.String(), opts)
+}
+
+// testFailuresError reports failed packages of summary to opts.OnTestFailure and returns nil
+// when go test still wrote coverage data to profile.
+func testFailuresError(profile string, summary gotest.Summary, err error, stderr string, opts *Options) error {
+	// failed tests make go test exit with error, other errors mean that the profile can't be trusted
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || len(summary.Failures) == 0 {
+		return fmt.Errorf("failed to run tests: %w\nstderr: %s", err, stderr)
+	}
+
+	if opts.OnTestFailure != nil {
+		for _, failure := range summary.Failures {
+			opts.OnTestFailure(failure)
+		}
+	}
+
+	// packages that failed to build write no blocks, the profile may consist of the mode line only
+	if profiles, err := cover.ParseProfiles(profile); err != nil || len(profiles) == 0 {
+		return fmt.Errorf("%w: no coverage data was written, %d packages failed\nstderr: %s",
+			errTestsFailed, len(summary.Failures), stderr)
+	}
+
+	return nil
+}
+
+// mergeProfiles concatenates profiles of the same mode into output keeping a single mode line.
+// Count and atomic profiles are compatible and merged as count of the first of them.
+func mergeProfiles(profiles []string, output string) error {
+	var (
+		merged bytes.Buffer
+		mode   string
+	)
+
+	for _, profile := range profiles {
+		data, err := os.ReadFile(profile)
+		if errors.Is(err, os.ErrNotExist) {
+			continue // module without packages
+		} else if err != nil {
+			return fmt.Errorf("failed to read coverage profile: %w", err)
+		}
+
+		modeLine, blocks, _ := strings.Cut(string(data), "\n")
+		profileMode := strings.TrimSpace(strings.TrimPrefix(modeLine, "mode:"))
+
+		if merged.Len() == 0 {
+			mode = profileMode
+			merged.WriteString(modeLine + "\n")
+		} else if !compatibleModes(mode, profileMode) {
+			return fmt.Errorf("%w: profiles have different modes %s and %s", errUnsupportedMode, mode, profileMode)
+		}
+
+		merged.WriteString(blocks)
+	}
+
+	if err := os.WriteFile(output, merged.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write coverage profile: %w", err)
 	}
 
 	return nil
 }
 
+func compatibleModes(first, second string) bool {
+	if first == second {
+		return true
+	}
+
+	counts := func(mode string) bool { return mode == ModeCount || mode == ModeAtomic }
+
+	return counts(first) && counts(second)
+}
+
 func sortByCoverage(files []FileCoverage, asc bool) []FileCoverage {
 	sorted := make([]FileCoverage, len(files))
 	copy(sorted, files)
@@ -8,6 +8,10 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vbvictor/grit/internal/testutil"
+	"github.com/vbvictor/grit/pkg/gotest"
+	"github.com/vbvictor/grit/pkg/module"
+	"github.com/vbvictor/grit/pkg/testfiles"
 )
 
 func createTempFile(t *testing.T, dir, content string) *os.File {
@@ -25,6 +29,13 @@ func createTempFile(t *testing.T, dir, content string) *os.File {
 	return tmpfile
 }
 
+// writeGoMod declares module of test profiles in dir.
+func writeGoMod(t *testing.T, dir string) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/name/module\n"), 0o600))
+}
+
 func TestReadCoverage(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -44,24 +55,52 @@ example.com/name/module/path1/path2/file2.go:5.20,8.2 2 1`,
 					Coverage:   100.0,
 					Statements: 2,
 					Covered:    2,
+					Mode:       ModeSet,
 				},
 				{
 					File:       filepath.Join("path1", "file1.go"),
 					Coverage:   60.0,
 					Statements: 5,
 					Covered:    3,
+					Mode:       ModeSet,
 				},
 			},
 		},
 		{
-			name: "Unsupported count mode",
+			name: "Count mode with hits",
 			content: `mode: count
-example.com/name/module/file1.go:10.20,30.2 3 1`,
-			wantErr: errUnsupportedMode,
+example.com/name/module/file1.go:10.20,30.2 3 4
+example.com/name/module/file1.go:32.20,35.2 4 0
+example.com/name/module/file1.go:36.20,38.2 1 7`,
+			want: []*FileCoverage{
+				{
+					File:       "file1.go",
+					Coverage:   50.0,
+					Statements: 8,
+					Covered:    4,
+					Mode:       ModeCount,
+					Hits:       19,
+				},
+			},
 		},
 		{
-			name: "Unsupported atomic mode",
+			name: "Atomic mode with hits",
 			content: `mode: atomic
+example.com/name/module/file1.go:10.20,30.2 3 2`,
+			want: []*FileCoverage{
+				{
+					File:       "file1.go",
+					Coverage:   100.0,
+					Statements: 3,
+					Covered:    3,
+					Mode:       ModeAtomic,
+					Hits:       6,
+				},
+			},
+		},
+		{
+			name: "Unsupported mode",
+			content: `mode: unknown
 example.com/name/module/file1.go:10.20,30.2 3 1`,
 			wantErr: errUnsupportedMode,
 		},
@@ -70,6 +109,7 @@ example.com/name/module/file1.go:10.20,30.2 3 1`,
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tmpDir := t.TempDir()
+			writeGoMod(t, tmpDir)
 			tmpfile := createTempFile(t, tmpDir, tt.content)
 
 			got, err := ReadCoverage(tmpDir, filepath.Base(tmpfile.Name()), &Options{Top: 10, SortBy: Worst, ExcludeRegex: nil})
@@ -109,12 +149,14 @@ example.com/name/module/cmd/app.go:15.30,20.2 3 1`,
 					Coverage:   100.0,
 					Statements: 3,
 					Covered:    3,
+					Mode:       ModeSet,
 				},
 				{
 					File:       filepath.Join("cmd", "app.go"),
 					Coverage:   100.0,
 					Statements: 3,
 					Covered:    3,
+					Mode:       ModeSet,
 				},
 			},
 		},
@@ -132,6 +174,23 @@ example.com/name/module/cmd/app.go:15.30,20.2 3 1`,
 					Coverage:   100.0,
 					Statements: 3,
 					Covered:    3,
+					Mode:       ModeSet,
+				},
+			},
+		},
+		{
+			name:         "pattern anchored at repository root",
+			excludeRegex: regexp.MustCompile(`^pkg/`),
+			content: `mode: set
+example.com/name/module/pkg/file1.go:10.20,30.2 3 1
+example.com/name/module/cmd/app.go:15.30,20.2 3 1`,
+			want: []*FileCoverage{
+				{
+					File:       filepath.Join("cmd", "app.go"),
+					Coverage:   100.0,
+					Statements: 3,
+					Covered:    3,
+					Mode:       ModeSet,
 				},
 			},
 		},
@@ -147,12 +206,14 @@ example.com/name/module/file2.go:5.20,8.2 2 1`,
 					Coverage:   100.0,
 					Statements: 3,
 					Covered:    3,
+					Mode:       ModeSet,
 				},
 				{
 					File:       "file2.go",
 					Coverage:   100.0,
 					Statements: 2,
 					Covered:    2,
+					Mode:       ModeSet,
 				},
 			},
 		},
@@ -161,6 +222,7 @@ example.com/name/module/file2.go:5.20,8.2 2 1`,
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tmpDir := t.TempDir()
+			writeGoMod(t, tmpDir)
 			tmpfile := createTempFile(t, tmpDir, tt.content)
 			got, err := ReadCoverage(tmpDir, filepath.Base(tmpfile.Name()),
 				&Options{Top: 10, SortBy: Worst, ExcludeRegex: tt.excludeRegex})
@@ -173,6 +235,243 @@ example.com/name/module/file2.go:5.20,8.2 2 1`,
 	}
 }
 
+func TestReadCoverageTestsFilter(t *testing.T) {
+	content := `mode: set
+example.com/name/module/pkg/file1.go:10.20,30.2 3 1
+example.com/name/module/internal/testutil/helpers.go:5.20,8.2 2 0`
+
+	tests := []struct {
+		name     string
+		filter   testfiles.Filter
+		wantFile string
+	}{
+		{
+			name:     "exclude test helpers",
+			filter:   testfiles.Filter{Mode: testfiles.Exclude, Patterns: []string{"testutil/"}},
+			wantFile: filepath.Join("pkg", "file1.go"),
+		},
+		{
+			name:     "only test helpers",
+			filter:   testfiles.Filter{Mode: testfiles.Only, Patterns: []string{"testutil/"}},
+			wantFile: filepath.Join("internal", "testutil", "helpers.go"),
+		},
+		{
+			name:     "patterns are anchored at repository root",
+			filter:   testfiles.Filter{Mode: testfiles.Exclude, Patterns: []string{"^internal/testutil/"}},
+			wantFile: filepath.Join("pkg", "file1.go"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.filter.Compile())
+
+			tmpDir := t.TempDir()
+			writeGoMod(t, tmpDir)
+			tmpfile := createTempFile(t, tmpDir, content)
+
+			got, err := ReadCoverage(tmpDir, filepath.Base(tmpfile.Name()), &Options{Tests: tt.filter})
+			require.NoError(t, err)
+			require.Len(t, got, 1)
+			assert.Equal(t, tt.wantFile, got[0].File)
+		})
+	}
+}
+
+func TestReadCoverageModules(t *testing.T) {
+	content := `mode: set
+example.com/root/main.go:10.20,30.2 3 1
+example.com/root/tools/gen.go:5.20,8.2 2 0
+example.com/api/v2/client.go:5.20,8.2 4 1
+github.com/other/repo/pkg/file.go:5.20,8.2 1 1`
+
+	tmpDir := t.TempDir()
+	tmpfile := createTempFile(t, tmpDir, content)
+
+	unresolved := make([]string, 0)
+
+	got, err := ReadCoverage(tmpDir, filepath.Base(tmpfile.Name()), &Options{
+		Modules: module.Modules{
+			{Path: "example.com/root", Dir: "."},
+			{Path: "example.com/root/tools", Dir: "tools"},
+			{Path: "example.com/api/v2", Dir: "api/v2"},
+		},
+		OnUnresolved: func(file string, reason error) {
+			assert.ErrorIs(t, reason, ErrNoModule)
+			unresolved = append(unresolved, file)
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github.com/other/repo/pkg/file.go"}, unresolved)
+
+	files := make([]string, 0, len(got))
+	for _, file := range got {
+		files = append(files, file.File)
+	}
+
+	assert.ElementsMatch(t, []string{
+		"main.go",
+		filepath.Join("tools", "gen.go"),
+		filepath.Join("api", "v2", "client.go"),
+	}, files)
+}
+
+func TestReadCoverageWithoutModules(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"main.go":  "package main\n",
+		"pkg/a.go": "package pkg\n",
+		"coverage.out": `mode: set
+github.com/user/repo/main.go:1.1,2.2 1 1
+github.com/user/repo/pkg/a.go:1.1,2.2 2 0
+github.com/user/repo/pkg/b.go:1.1,2.2 1 1`,
+	})
+
+	var unresolved []string
+
+	opts := &Options{OnUnresolved: func(file string, reason error) {
+		assert.ErrorIs(t, reason, ErrFileNotFound)
+		unresolved = append(unresolved, file)
+	}}
+
+	got, err := ReadCoverage(tmpDir, "coverage.out", opts)
+	require.NoError(t, err)
+	assert.Nil(t, opts.Modules)
+	assert.Equal(t, []string{"github.com/user/repo/pkg/b.go"}, unresolved)
+
+	assert.Equal(t, []*FileCoverage{
+		{File: "main.go", Coverage: 100, Statements: 1, Covered: 1, Mode: ModeSet},
+		{File: filepath.Join("pkg", "a.go"), Coverage: 0, Statements: 2, Covered: 0, Mode: ModeSet},
+	}, got)
+}
+
+func TestReadCoverageWorkspace(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"go.work":     "go 1.22\n\nuse (\n\t.\n\t./yaml\n)\n",
+		"go.mod":      "module myapp\n",
+		"yaml/go.mod": "module gopkg.in/yaml.v3\n",
+	})
+
+	tmpfile := createTempFile(t, tmpDir, `mode: set
+myapp/cmd/main.go:10.20,30.2 3 1
+gopkg.in/yaml.v3/decode.go:5.20,8.2 2 0`)
+
+	got, err := ReadCoverage(tmpDir, filepath.Base(tmpfile.Name()), &Options{})
+	require.NoError(t, err)
+
+	files := make([]string, 0, len(got))
+	for _, file := range got {
+		files = append(files, file.File)
+	}
+
+	assert.ElementsMatch(t, []string{filepath.Join("cmd", "main.go"), filepath.Join("yaml", "decode.go")}, files)
+}
+
+func TestMergeProfiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	first := createTempFile(t, tmpDir, "mode: set\nexample.com/a/a.go:1.1,2.2 1 1\n")
+	second := createTempFile(t, tmpDir, "mode: set\nexample.com/b/b.go:1.1,2.2 2 0\n")
+	output := filepath.Join(tmpDir, "merged.out")
+
+	require.NoError(t, mergeProfiles([]string{first.Name(), filepath.Join(tmpDir, "missing.out"), second.Name()}, output))
+
+	merged, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, "mode: set\nexample.com/a/a.go:1.1,2.2 1 1\nexample.com/b/b.go:1.1,2.2 2 0\n", string(merged))
+
+	atomic := createTempFile(t, tmpDir, "mode: atomic\nexample.com/c/c.go:1.1,2.2 1 1\n")
+	require.ErrorIs(t, mergeProfiles([]string{first.Name(), atomic.Name()}, output), errUnsupportedMode)
+
+	count := createTempFile(t, tmpDir, "mode: count\nexample.com/d/d.go:1.1,2.2 1 3\n")
+	require.NoError(t, mergeProfiles([]string{count.Name(), atomic.Name()}, output))
+
+	merged, err = os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, "mode: count\nexample.com/d/d.go:1.1,2.2 1 3\nexample.com/c/c.go:1.1,2.2 1 1\n", string(merged))
+}
+
+func TestRunCoverageTestFailures(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFile(t, filepath.Join(tmpDir, "go.mod"), "module example.com/m\n\ngo 1.23\n")
+	testutil.WriteFile(t, filepath.Join(tmpDir, "ok", "ok.go"), "package ok\n\nfunc A() int {\n\treturn 1\n}\n")
+	testutil.WriteFile(t, filepath.Join(tmpDir, "ok", "ok_test.go"),
+		"package ok\n\nimport \"testing\"\n\nfunc TestA(t *testing.T) { A() }\n")
+	testutil.WriteFile(t, filepath.Join(tmpDir, "bad", "bad.go"), "package bad\n\nfunc B() int {\n\treturn 1\n}\n")
+	testutil.WriteFile(t, filepath.Join(tmpDir, "bad", "bad_test.go"),
+		"package bad\n\nimport (\n\t\"os\"\n\t\"testing\"\n)\n\n"+
+			"func TestB(t *testing.T) {\n\tif os.Getenv(\"PASS\") == \"\" {\n\t\tt.Fatal(\"failed\")\n\t}\n}\n")
+
+	var failures []gotest.Failure
+
+	opts := &Options{OnTestFailure: func(failure gotest.Failure) {
+		failures = append(failures, failure)
+	}}
+
+	require.NoError(t, RunCoverage(tmpDir, "coverage.out", opts))
+	assert.Equal(t, []gotest.Failure{{Package: "example.com/m/bad", Tests: []string{"TestB"}}}, failures)
+
+	got, err := ReadCoverage(tmpDir, "coverage.out", &Options{})
+	require.NoError(t, err)
+	assert.Len(t, got, 2, "coverage of passing package is kept")
+
+	failures = nil
+	opts.Runner = gotest.Options{Packages: []string{"./bad"}, Env: []string{"PASS=1"}}
+
+	require.NoError(t, RunCoverage(tmpDir, "coverage.out", opts))
+	assert.Empty(t, failures)
+
+	opts.Runner = gotest.Options{Packages: []string{"./bad"}}
+
+	require.NoError(t, RunCoverage(tmpDir, "coverage.out", opts), "single flaky package keeps its coverage")
+	assert.Equal(t, []gotest.Failure{{Package: "example.com/m/bad", Tests: []string{"TestB"}}}, failures)
+
+	testutil.WriteFile(t, filepath.Join(tmpDir, "bad", "bad.go"), "package bad\n\nfunc B() int {\n\treturn b\n}\n")
+	require.ErrorIs(t, RunCoverage(tmpDir, "coverage.out", opts), errTestsFailed, "build failure writes no coverage")
+}
+
+func TestRunCoverageModuleFailures(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"app/go.mod":      "module example.com/app\n\ngo 1.23\n",
+		"app/app.go":      "package app\n\nfunc A() int {\n\treturn 1\n}\n",
+		"app/app_test.go": "package app\n\nimport \"testing\"\n\nfunc TestA(t *testing.T) { A() }\n",
+		"lib/go.mod":      "module example.com/lib\n\ngo 1.23\n",
+		"lib/lib.go":      "package lib\n\nfunc B() int {\n\treturn b\n}\n",
+		"lib/lib_test.go": "package lib\n\nimport \"testing\"\n\nfunc TestB(t *testing.T) { B() }\n",
+	})
+
+	var failures []gotest.Failure
+
+	opts := &Options{OnTestFailure: func(failure gotest.Failure) {
+		failures = append(failures, failure)
+	}}
+
+	require.NoError(t, RunCoverage(tmpDir, "coverage.out", opts))
+	assert.Equal(t, []gotest.Failure{{Package: "example.com/lib"}}, failures)
+
+	got, err := ReadCoverage(tmpDir, "coverage.out", &Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []*FileCoverage{
+		{File: filepath.Join("app", "app.go"), Coverage: 100, Statements: 1, Covered: 1, Mode: ModeSet},
+		{File: filepath.Join("lib", "lib.go"), Coverage: 0, Statements: 1, Covered: 0, Mode: ModeSet},
+	}, got, "coverage of module that builds is kept")
+}
+
+func TestRollupByModule(t *testing.T) {
+	modules := module.Modules{{Path: "example.com/root", Dir: "."}, {Path: "example.com/root/tools", Dir: "tools"}}
+	files := []*FileCoverage{
+		{File: "main.go", Statements: 10, Covered: 10, Coverage: 100},
+		{File: filepath.Join("pkg", "a.go"), Statements: 30, Covered: 10, Coverage: 33.33},
+		{File: filepath.Join("tools", "gen.go"), Statements: 4, Covered: 1, Coverage: 25},
+	}
+
+	assert.Equal(t, []*FileCoverage{
+		{File: "example.com/root", Statements: 40, Covered: 20, Coverage: 50},
+		{File: "example.com/root/tools", Statements: 4, Covered: 1, Coverage: 25},
+	}, RollupByModule(files, modules))
+}
+
 func TestSortByCoverage(t *testing.T) {
 	tests := []struct {
 		name     string
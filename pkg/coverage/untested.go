@@ -0,0 +1,286 @@
+package coverage
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/vbvictor/grit/pkg/module"
+	"golang.org/x/tools/cover"
+)
+
+// packageKeywords are patterns of go test that don't name packages, see 'go help packages'.
+var packageKeywords = []string{"all", "std", "cmd", "tool", "..."}
+
+// untestedProfiles returns profiles with zero counts for Go files of modules missing from profiles, which
+// happens to files of packages without tests. Only files go test builds for the current platform with tags
+// of opts.Runner are reported, so that files of other platforms don't show up as untested, and only files of
+// packages matching package patterns of opts.Runner, so that packages left out of the run aren't reported.
+func untestedProfiles(
+	repoPath string, profiles []*cover.Profile, modules module.Modules, opts *Options,
+) ([]*cover.Profile, error) {
+	mode := ModeSet
+	covered := make(map[string]bool, len(profiles))
+
+	for _, profile := range profiles {
+		mode = profile.Mode
+		covered[filepath.Clean(profile.FileName)] = true
+	}
+
+	filter := newUntestedFilter(modules, covered, opts)
+
+	files, err := moduleFiles(repoPath, modules, filter.selects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find untested files: %w", err)
+	}
+
+	return zeroProfiles(repoPath, files, mode)
+}
+
+// untestedFilter selects files that go test builds but profiles have no coverage data for.
+type untestedFilter struct {
+	buildContext build.Context
+	patterns     []string
+	modules      module.Modules
+	covered      map[string]bool
+	opts         *Options
+}
+
+func newUntestedFilter(modules module.Modules, covered map[string]bool, opts *Options) *untestedFilter {
+	patterns := opts.Runner.Packages
+	if opts.Runner.CoverPkg != "" {
+		patterns = strings.Split(opts.Runner.CoverPkg, ",")
+	}
+
+	return &untestedFilter{
+		buildContext: testBuildContext(opts),
+		patterns:     patterns,
+		modules:      modules,
+		covered:      covered,
+		opts:         opts,
+	}
+}
+
+func (f *untestedFilter) selects(path, relPath string) bool {
+	if f.covered[relPath] || !isBuiltGoFile(&f.buildContext, path) {
+		return false
+	}
+
+	// files are filtered the same way files of profiles are, import path stands for file name of profiles
+	importPath, ok := f.modules.ImportPath(relPath)
+
+	return ok && !f.opts.Tests.Skip(relPath) && !isExcluded(f.opts.ExcludeRegex, importPath, relPath) &&
+		matchesPackages(f.patterns, f.modules, relPath, importPath)
+}
+
+// moduleFiles returns repository relative paths of files of modules under repoPath that selects accepts.
+// Directories go tool ignores and roots of modules that aren't analyzed aren't walked.
+func moduleFiles(
+	repoPath string, modules module.Modules, selects func(path, relPath string) bool,
+) ([]string, error) {
+	moduleDirs := make(map[string]bool, len(modules))
+	for _, mod := range modules {
+		moduleDirs[filepath.FromSlash(mod.Dir)] = true
+	}
+
+	files := make([]string, 0)
+
+	err := filepath.WalkDir(repoPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to walk path: %w", err)
+		}
+
+		relPath, err := filepath.Rel(repoPath, path)
+		if err != nil {
+			return fmt.Errorf("failed to get relative path: %w", err)
+		}
+
+		if entry.IsDir() {
+			if path != repoPath && (module.IsIgnoredDir(entry.Name()) || isForeignModule(path, relPath, moduleDirs)) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if selects(path, relPath) {
+			files = append(files, relPath)
+		}
+
+		return nil
+	})
+
+	return files, err
+}
+
+// zeroProfiles returns profiles of mode with zero counts for statements of files, files without statements,
+// e.g. files with type declarations only, get no profile.
+func zeroProfiles(repoPath string, files []string, mode string) ([]*cover.Profile, error) {
+	results := make([]*cover.Profile, 0, len(files))
+
+	for _, relPath := range files {
+		blocks, err := statementBlocks(filepath.Join(repoPath, relPath))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(blocks) > 0 {
+			results = append(results, &cover.Profile{FileName: relPath, Mode: mode, Blocks: blocks})
+		}
+	}
+
+	return results, nil
+}
+
+// matchesPackages reports whether package of file at relPath with importPath matches one of go test patterns,
+// relative patterns are relative to the module of the file, the way go test is run in every module.
+// Package list keywords like 'all' match every package, no patterns is the default './...'.
+func matchesPackages(patterns []string, modules module.Modules, relPath, importPath string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	owner, _ := modules.Owner(relPath)
+	pkg := path.Dir(importPath)
+
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "." || strings.HasPrefix(pattern, "./") {
+			pattern = owner.Path + strings.TrimPrefix(pattern, ".")
+		}
+
+		prefix, recursive := strings.CutSuffix(pattern, "/...")
+
+		switch {
+		case slices.Contains(packageKeywords, pattern):
+			return true
+		case pkg == prefix:
+			return true
+		case recursive && strings.HasPrefix(pkg, prefix+"/"):
+			return true
+		}
+	}
+
+	return false
+}
+
+// isForeignModule reports whether directory is root of a module that isn't one of analyzed modules,
+// e.g. a module left out of go.work.
+func isForeignModule(path, relPath string, moduleDirs map[string]bool) bool {
+	if moduleDirs[relPath] {
+		return false
+	}
+
+	_, err := os.Stat(filepath.Join(path, module.GoMod))
+
+	return err == nil
+}
+
+// testBuildContext returns build context of go test run configured by opts.Runner.
+func testBuildContext(opts *Options) build.Context {
+	buildContext := build.Default
+	buildContext.BuildTags = append(slices.Clone(buildContext.BuildTags), opts.Runner.Tags...)
+
+	return buildContext
+}
+
+func isBuiltGoFile(buildContext *build.Context, path string) bool {
+	name := filepath.Base(path)
+	if filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") {
+		return false
+	}
+
+	match, err := buildContext.MatchFile(filepath.Dir(path), name)
+
+	return err == nil && match
+}
+
+// statementBlocks returns a block with zero count for every statement of function bodies of file, which are
+// statements 'go tool cover' instruments, nested blocks included. Blocks of compound statements end where their
+// bodies start.
+func statementBlocks(path string) ([]cover.ProfileBlock, error) {
+	fileSet := token.NewFileSet()
+
+	file, err := parser.ParseFile(fileSet, path, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	blocks := make([]cover.ProfileBlock, 0)
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		for _, stmt := range nodeStatements(node) {
+			start, end := fileSet.Position(stmt.Pos()), fileSet.Position(statementEnd(stmt))
+			blocks = append(blocks, cover.ProfileBlock{
+				StartLine: start.Line, StartCol: start.Column, EndLine: end.Line, EndCol: end.Column, NumStmt: 1,
+			})
+		}
+
+		return true
+	})
+
+	sortBlocks(blocks)
+
+	return blocks, nil
+}
+
+// nodeStatements returns statements held directly by node.
+func nodeStatements(node ast.Node) []ast.Stmt {
+	switch node := node.(type) {
+	case *ast.BlockStmt:
+		// clauses of switch and select bodies hold statements
+		return slices.DeleteFunc(slices.Clone(node.List), isClause)
+	case *ast.CaseClause:
+		return node.Body
+	case *ast.CommClause:
+		return node.Body
+	case *ast.IfStmt:
+		// 'else if' is counted as a statement of implicit else block
+		if elseIf, ok := node.Else.(*ast.IfStmt); ok {
+			return []ast.Stmt{elseIf}
+		}
+	}
+
+	return nil
+}
+
+func isClause(stmt ast.Stmt) bool {
+	switch stmt.(type) {
+	case *ast.CaseClause, *ast.CommClause:
+		return true
+	default:
+		return false
+	}
+}
+
+// statementEnd returns end of statement header for statements with bodies, bodies hold statements of their own.
+func statementEnd(stmt ast.Stmt) token.Pos {
+	switch stmt := stmt.(type) {
+	case *ast.IfStmt:
+		return stmt.Body.Lbrace + 1
+	case *ast.ForStmt:
+		return stmt.Body.Lbrace + 1
+	case *ast.RangeStmt:
+		return stmt.Body.Lbrace + 1
+	case *ast.SwitchStmt:
+		return stmt.Body.Lbrace + 1
+	case *ast.TypeSwitchStmt:
+		return stmt.Body.Lbrace + 1
+	case *ast.SelectStmt:
+		return stmt.Body.Lbrace + 1
+	case *ast.BlockStmt:
+		return stmt.Lbrace + 1
+	case *ast.LabeledStmt:
+		return statementEnd(stmt.Stmt)
+	default:
+		return stmt.End()
+	}
+}
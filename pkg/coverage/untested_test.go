@@ -0,0 +1,168 @@
+package coverage
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vbvictor/grit/internal/testutil"
+	"github.com/vbvictor/grit/pkg/gotest"
+	"github.com/vbvictor/grit/pkg/testfiles"
+	"golang.org/x/tools/cover"
+)
+
+const untestedSource = `package untested
+
+func Untested(x int) int {
+	if x > 0 {
+		return 1
+	} else if x < 0 {
+		return -1
+	}
+
+	switch x {
+	case 0:
+		x++
+	default:
+	}
+
+	f := func() {
+		x++
+	}
+	f()
+
+	return x
+}
+`
+
+func TestStatementBlocks(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFile(t, filepath.Join(tmpDir, "a.go"), untestedSource)
+
+	blocks, err := statementBlocks(filepath.Join(tmpDir, "a.go"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []cover.ProfileBlock{
+		{StartLine: 4, StartCol: 2, EndLine: 4, EndCol: 12, NumStmt: 1},
+		{StartLine: 5, StartCol: 3, EndLine: 5, EndCol: 11, NumStmt: 1},
+		{StartLine: 6, StartCol: 9, EndLine: 6, EndCol: 19, NumStmt: 1},
+		{StartLine: 7, StartCol: 3, EndLine: 7, EndCol: 12, NumStmt: 1},
+		{StartLine: 10, StartCol: 2, EndLine: 10, EndCol: 12, NumStmt: 1},
+		{StartLine: 12, StartCol: 3, EndLine: 12, EndCol: 6, NumStmt: 1},
+		{StartLine: 16, StartCol: 2, EndLine: 18, EndCol: 3, NumStmt: 1},
+		{StartLine: 17, StartCol: 3, EndLine: 17, EndCol: 6, NumStmt: 1},
+		{StartLine: 19, StartCol: 2, EndLine: 19, EndCol: 5, NumStmt: 1},
+		{StartLine: 21, StartCol: 2, EndLine: 21, EndCol: 10, NumStmt: 1},
+	}, blocks)
+}
+
+func TestReadCoverageUntestedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeGoMod(t, tmpDir)
+
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"tested/a.go":         "package tested\n\nfunc A() {\n\tprintln()\n}\n",
+		"tested/a_test.go":    "package tested\n\nfunc helper() {\n\tprintln()\n}\n",
+		"untested/b.go":       untestedSource,
+		"untested/types.go":   "package untested\n\ntype T struct{}\n",
+		"untested/ignored.go": "//go:build never\n\npackage untested\n\nfunc I() {\n\tprintln()\n}\n",
+		"untested/tagged.go":  "//go:build integration\n\npackage untested\n\nfunc T() {\n\tprintln()\n}\n",
+		"testdata/c.go":       "package c\n\nfunc C() {\n\tprintln()\n}\n",
+		"vendor/d/d.go":       "package d\n\nfunc D() {\n\tprintln()\n}\n",
+		"nested/go.mod":       "module example.com/nested\n",
+		"nested/e.go":         "package nested\n\nfunc E() {\n\tprintln()\n}\n",
+		"gen/gen.go":          "package gen\n\nfunc G() {\n\tprintln()\n}\n",
+		"coverage.out":        "mode: count\nexample.com/name/module/tested/a.go:3.10,5.2 1 2\n",
+	})
+
+	got, err := ReadCoverage(tmpDir, "coverage.out", &Options{ExcludeRegex: regexp.MustCompile("gen/")})
+	require.NoError(t, err)
+
+	// files of nested module are reported too, since every go.mod is a module of repository without go.work
+	assert.Equal(t, []*FileCoverage{
+		{File: filepath.Join("nested", "e.go"), Coverage: 0, Statements: 1, Covered: 0, Mode: ModeCount},
+		{File: filepath.Join("tested", "a.go"), Coverage: 100, Statements: 1, Covered: 1, Mode: ModeCount, Hits: 2},
+		{File: filepath.Join("untested", "b.go"), Coverage: 0, Statements: 10, Covered: 0, Mode: ModeCount},
+	}, got)
+
+	// build tags of tests select files the same way go test does, modules left out of go.work are skipped
+	testutil.WriteFile(t, filepath.Join(tmpDir, "go.work"), "go 1.23\n\nuse .\n")
+
+	got, err = ReadCoverage(tmpDir, "coverage.out", &Options{
+		ExcludeRegex: regexp.MustCompile("gen/"),
+		Runner:       gotest.Options{Tags: []string{"integration"}},
+	})
+	require.NoError(t, err)
+
+	files := make([]string, 0, len(got))
+	for _, file := range got {
+		files = append(files, file.File)
+	}
+
+	assert.Equal(t, []string{
+		filepath.Join("tested", "a.go"), filepath.Join("untested", "b.go"), filepath.Join("untested", "tagged.go"),
+	}, files)
+
+	// tests filter applies to untested files too
+	tests := testfiles.Filter{Mode: testfiles.Only, Patterns: []string{"^untested/"}}
+	require.NoError(t, tests.Compile())
+
+	got, err = ReadCoverage(tmpDir, "coverage.out", &Options{Tests: tests})
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+	assert.Equal(t, filepath.Join("untested", "b.go"), got[0].File)
+}
+
+func TestReadCoverageForeignReportSkipsUntested(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeGoMod(t, tmpDir)
+	testutil.WriteFile(t, filepath.Join(tmpDir, "untested", "b.go"), untestedSource)
+	testutil.WriteFile(t, filepath.Join(tmpDir, "app.js"), "")
+	testutil.WriteFile(t, filepath.Join(tmpDir, "lcov.info"), "SF:app.js\nDA:1,1\nend_of_record\n")
+
+	got, err := ReadCoverage(tmpDir, "lcov.info", &Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []*FileCoverage{
+		{File: "app.js", Coverage: 100, Statements: 1, Covered: 1, Mode: ModeCount, Hits: 1},
+	}, got)
+}
+
+func TestReadCoverageUntestedPackages(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeGoMod(t, tmpDir)
+
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"pkg/a/a.go":     "package a\n\nfunc A() {\n\tprintln()\n}\n",
+		"pkg/a/sub/s.go": "package sub\n\nfunc S() {\n\tprintln()\n}\n",
+		"pkg/b/b.go":     "package b\n\nfunc B() {\n\tprintln()\n}\n",
+		"cmd/main.go":    "package main\n\nfunc main() {\n\tprintln()\n}\n",
+	})
+
+	// profiles are stored outside of the repository, e.g. by history command
+	profile := filepath.Join(t.TempDir(), "coverage.out")
+	testutil.WriteFile(t, profile, "mode: set\nexample.com/name/module/pkg/b/b.go:3.10,5.2 1 1\n")
+
+	files := func(opts *Options) []string {
+		got, err := ReadCoverage(tmpDir, profile, opts)
+		require.NoError(t, err)
+
+		result := make([]string, 0, len(got))
+		for _, file := range got {
+			result = append(result, filepath.ToSlash(file.File))
+		}
+
+		return result
+	}
+
+	assert.Equal(t, []string{"cmd/main.go", "pkg/a/a.go", "pkg/a/sub/s.go", "pkg/b/b.go"}, files(&Options{}))
+	assert.Equal(t, []string{"pkg/a/a.go", "pkg/a/sub/s.go", "pkg/b/b.go"},
+		files(&Options{Runner: gotest.Options{Packages: []string{"./pkg/..."}}}))
+	assert.Equal(t, []string{"pkg/a/a.go", "pkg/b/b.go"},
+		files(&Options{Runner: gotest.Options{Packages: []string{"./pkg/a", "example.com/name/module/pkg/b"}}}))
+	assert.Equal(t, []string{"cmd/main.go", "pkg/b/b.go"},
+		files(&Options{Runner: gotest.Options{Packages: []string{"./pkg/a"}, CoverPkg: "./cmd,./pkg/b"}}))
+	assert.Equal(t, []string{"pkg/b/b.go"}, files(&Options{NoUntested: true}))
+}
@@ -0,0 +1,128 @@
+package coverage
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"golang.org/x/tools/cover"
+)
+
+type coberturaReport struct {
+	Sources  []string `xml:"sources>source"`
+	Packages []struct {
+		Classes []struct {
+			Filename string `xml:"filename,attr"`
+			Lines    []struct {
+				Number int `xml:"number,attr"`
+				Hits   int `xml:"hits,attr"`
+			} `xml:"lines>line"`
+		} `xml:"classes>class"`
+	} `xml:"packages>package"`
+}
+
+// parseCobertura converts lines of classes of Cobertura XML report to profiles of count mode.
+// Files are relative to one of report sources, the first source containing the file is used.
+func parseCobertura(data []byte) ([]*cover.Profile, error) {
+	var report coberturaReport
+	if err := decodeXML(data, &report); err != nil {
+		return nil, err
+	}
+
+	profiles := newLineProfiles(ModeCount)
+
+	for _, pkg := range report.Packages {
+		for _, class := range pkg.Classes {
+			file := coberturaFile(report.Sources, class.Filename)
+
+			for _, line := range class.Lines {
+				profiles.add(file, line.Number, max(line.Hits, 0))
+			}
+		}
+	}
+
+	return profiles.profiles(), nil
+}
+
+// coberturaFile joins filename with the first source it exists in. Sources usually are absolute paths
+// of the machine that produced the report, so filename is returned as is when none of them exists.
+func coberturaFile(sources []string, filename string) string {
+	if filepath.IsAbs(filename) {
+		return filename
+	}
+
+	for _, source := range sources {
+		file := filepath.Join(source, filename)
+		if _, err := os.Stat(file); err == nil && filepath.IsAbs(file) {
+			return file
+		}
+	}
+
+	return filename
+}
+
+type jacocoLine struct {
+	Number        int `xml:"nr,attr"`
+	CoveredInstrs int `xml:"ci,attr"`
+	MissedInstrs  int `xml:"mi,attr"`
+}
+
+type jacocoGroup struct {
+	Groups   []jacocoGroup `xml:"group"`
+	Packages []struct {
+		Name        string `xml:"name,attr"`
+		SourceFiles []struct {
+			Name  string       `xml:"name,attr"`
+			Lines []jacocoLine `xml:"line"`
+		} `xml:"sourcefile"`
+	} `xml:"package"`
+}
+
+// parseJaCoCo converts lines of source files of JaCoCo XML report to profiles of set mode, since JaCoCo
+// reports covered instructions rather than executions. Files are named '<package>/<source file>'.
+func parseJaCoCo(data []byte) ([]*cover.Profile, error) {
+	var report jacocoGroup
+	if err := decodeXML(data, &report); err != nil {
+		return nil, err
+	}
+
+	profiles := newLineProfiles(ModeSet)
+	addJaCoCoGroup(profiles, report)
+
+	return profiles.profiles(), nil
+}
+
+func addJaCoCoGroup(profiles *lineProfiles, group jacocoGroup) {
+	for _, child := range group.Groups {
+		addJaCoCoGroup(profiles, child)
+	}
+
+	for _, pkg := range group.Packages {
+		for _, sourceFile := range pkg.SourceFiles {
+			file := path.Join(pkg.Name, sourceFile.Name)
+
+			for _, line := range sourceFile.Lines {
+				if line.CoveredInstrs+line.MissedInstrs == 0 {
+					continue
+				}
+
+				profiles.add(file, line.Number, min(line.CoveredInstrs, 1))
+			}
+		}
+	}
+}
+
+// decodeXML decodes report ignoring its DTD, which reports reference but don't ship.
+func decodeXML(data []byte, report any) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+
+	if err := decoder.Decode(report); err != nil {
+		return fmt.Errorf("failed to decode XML report: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package crap
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/bndr/gotabulate"
+)
+
+func PrintTabular(results []*Function, summary Summary, out io.Writer) {
+	_, _ = io.WriteString(out, "\nCRAP analysis results:\n")
+
+	data := make([][]any, len(results))
+	for i, result := range results {
+		data[i] = []any{
+			result.File,
+			result.Line,
+			strings.Join(result.Package, "."),
+			result.Name,
+			result.Complexity,
+			fmt.Sprintf("%.2f%%", result.Coverage),
+			fmt.Sprintf("%.2f", result.Score),
+		}
+	}
+
+	table := gotabulate.Create(data)
+	table.SetHeaders([]string{"FILEPATH", "LINE", "PACKAGE", "FUNCTION", "COMPLEXITY", "COVERAGE", "CRAP"})
+	table.SetAlign("left")
+
+	_, _ = io.WriteString(out, table.Render("grid"))
+	PrintSummary(summary, out)
+}
+
+// PrintSummary prints the number of functions above threshold.
+func PrintSummary(summary Summary, out io.Writer) {
+	_, _ = fmt.Fprintf(out, "%d of %d functions (%.2f%%) have CRAP score above %.2f\n",
+		summary.Crappy, summary.Functions, summary.Percentage, summary.Threshold)
+}
+
+func PrintCSV(results []*Function, out io.Writer) {
+	writer := csv.NewWriter(out)
+	defer writer.Flush()
+
+	_ = writer.Write([]string{"FILEPATH", "LINE", "PACKAGE", "FUNCTION", "COMPLEXITY", "COVERAGE", "CRAP"})
+
+	for _, result := range results {
+		_ = writer.Write([]string{
+			result.File,
+			strconv.Itoa(result.Line),
+			strings.Join(result.Package, ";"),
+			result.Name,
+			strconv.Itoa(result.Complexity),
+			strconv.FormatFloat(result.Coverage, 'f', 2, 64),
+			strconv.FormatFloat(result.Score, 'f', 2, 64),
+		})
+	}
+}
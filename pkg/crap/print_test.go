@@ -0,0 +1,28 @@
+package crap
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintCSV(t *testing.T) {
+	var buf bytes.Buffer
+
+	PrintCSV([]*Function{
+		{File: "a.go", Line: 3, Package: []string{"a"}, Name: "run", Complexity: 10, Coverage: 50, Score: 22.5},
+	}, &buf)
+
+	assert.Equal(t, "FILEPATH,LINE,PACKAGE,FUNCTION,COMPLEXITY,COVERAGE,CRAP\na.go,3,a,run,10,50.00,22.50\n", buf.String())
+}
+
+func TestPrintTabular(t *testing.T) {
+	var buf bytes.Buffer
+
+	PrintTabular([]*Function{{File: "a.go", Line: 3, Name: "run", Complexity: 10, Score: 110}},
+		Summary{Functions: 4, Crappy: 1, Percentage: 25, Threshold: 30}, &buf)
+
+	assert.Contains(t, buf.String(), "110.00")
+	assert.Contains(t, buf.String(), "1 of 4 functions (25.00%) have CRAP score above 30.00")
+}
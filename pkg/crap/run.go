@@ -0,0 +1,157 @@
+// Package crap computes CRAP, Change Risk Anti-Patterns, score of functions from their cyclomatic complexity
+// and coverage: complexity^2 * (1 - coverage)^3 + complexity.
+package crap
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/coverage"
+)
+
+// DefaultThreshold is the score above which functions are considered crappy, as proposed by the metric authors.
+const DefaultThreshold = 30.0
+
+const (
+	percentMultiplier = 100.0
+	coverageExponent  = 3
+	goExt             = ".go"
+)
+
+var ErrInvalidThreshold = errors.New("threshold must be positive")
+
+type Options struct {
+	// Threshold is the score above which functions are considered crappy.
+	Threshold float64
+	// MaxCrappy is the allowed percentage of crappy functions.
+	MaxCrappy    float64
+	Top          int
+	OutputFormat string
+}
+
+// Function is CRAP score of a function with its inputs.
+type Function struct {
+	File       string
+	Line       int
+	Package    []string
+	Name       string
+	Complexity int
+	Coverage   float64
+	Score      float64
+}
+
+// Summary describes how many functions exceed threshold.
+type Summary struct {
+	Functions int
+	Crappy    int
+	// Percentage is the share of crappy functions.
+	Percentage float64
+	Threshold  float64
+}
+
+func PopulateOpts(opts *Options) error {
+	if opts.Threshold <= 0 {
+		return fmt.Errorf("%w, got %.2f", ErrInvalidThreshold, opts.Threshold)
+	}
+
+	return nil
+}
+
+// Score returns CRAP score of function with given cyclomatic complexity and coverage percentage.
+func Score(complexity int, coverage float64) float64 {
+	uncovered := 1 - coverage/percentMultiplier
+	comp := float64(complexity)
+
+	return comp*comp*math.Pow(uncovered, coverageExponent) + comp
+}
+
+// Combine joins functions by file and line. Functions without coverage are considered not covered, including
+// functions of Go files missing from coverage data, e.g. files of packages without tests in LCOV reports.
+// Functions of other files are scored only when their file is present in coverage data.
+func Combine(files []*complexity.FileStat, functions []*coverage.FunctionCoverage) []*Function {
+	type key struct {
+		file string
+		line int
+	}
+
+	covered := make(map[key]float64, len(functions))
+	profiled := make(map[string]bool)
+
+	for _, function := range functions {
+		file := filepath.Clean(function.File)
+		covered[key{file: file, line: function.Line}] = function.Coverage
+		profiled[file] = true
+	}
+
+	result := make([]*Function, 0)
+
+	for _, file := range files {
+		for _, function := range file.Functions {
+			path := filepath.Clean(function.File)
+			if !profiled[path] && filepath.Ext(path) != goExt {
+				continue
+			}
+
+			functionCoverage := covered[key{file: path, line: function.Line}]
+
+			result = append(result, &Function{
+				File:       path,
+				Line:       function.Line,
+				Package:    function.Package,
+				Name:       function.Name,
+				Complexity: function.Complexity,
+				Coverage:   functionCoverage,
+				Score:      Score(function.Complexity, functionCoverage),
+			})
+		}
+	}
+
+	return result
+}
+
+// Summarize counts functions with score above threshold.
+func Summarize(functions []*Function, threshold float64) Summary {
+	summary := Summary{Functions: len(functions), Threshold: threshold}
+
+	for _, function := range functions {
+		if function.Score > threshold {
+			summary.Crappy++
+		}
+	}
+
+	if summary.Functions > 0 {
+		summary.Percentage = float64(summary.Crappy) * percentMultiplier / float64(summary.Functions)
+	}
+
+	return summary
+}
+
+// SortAndLimit sorts functions by score, highest first, and returns top of them.
+func SortAndLimit(functions []*Function, top int) []*Function {
+	slices.SortStableFunc(functions, func(a, b *Function) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+
+			return 1
+		}
+
+		if a.File != b.File {
+			return strings.Compare(a.File, b.File)
+		}
+
+		return a.Line - b.Line
+	})
+
+	if top > 0 && top < len(functions) {
+		return functions[:top]
+	}
+
+	return functions
+}
@@ -0,0 +1,67 @@
+package crap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/coverage"
+)
+
+func TestScore(t *testing.T) {
+	assert.InDelta(t, 2.0, Score(1, 0), 1e-9)
+	assert.InDelta(t, 110.0, Score(10, 0), 1e-9)
+	assert.InDelta(t, 10.0, Score(10, 100), 1e-9)
+	assert.InDelta(t, 22.5, Score(10, 50), 1e-9)
+}
+
+func TestPopulateOpts(t *testing.T) {
+	require.NoError(t, PopulateOpts(&Options{Threshold: DefaultThreshold}))
+	require.ErrorIs(t, PopulateOpts(&Options{}), ErrInvalidThreshold)
+}
+
+func TestCombine(t *testing.T) {
+	files := []*complexity.FileStat{
+		{
+			Path: "a.go",
+			Functions: []complexity.FunctionStat{
+				{File: "a.go", Line: 3, Name: "covered", Complexity: 10},
+				{File: "a.go", Line: 20, Name: "empty", Complexity: 1},
+			},
+		},
+		{
+			Path:      "untested.go",
+			Functions: []complexity.FunctionStat{{File: "untested.go", Line: 5, Name: "run", Complexity: 4}},
+		},
+		{
+			Path:      "lib.rs",
+			Functions: []complexity.FunctionStat{{File: "lib.rs", Line: 1, Name: "parse", Complexity: 3}},
+		},
+	}
+	functions := []*coverage.FunctionCoverage{{File: "a.go", Line: 3, Name: "covered", Coverage: 50}}
+
+	assert.Equal(t, []*Function{
+		{File: "a.go", Line: 3, Name: "covered", Complexity: 10, Coverage: 50, Score: 22.5},
+		{File: "a.go", Line: 20, Name: "empty", Complexity: 1, Coverage: 0, Score: 2},
+		{File: "untested.go", Line: 5, Name: "run", Complexity: 4, Coverage: 0, Score: 20},
+	}, Combine(files, functions))
+}
+
+func TestSummarizeAndSort(t *testing.T) {
+	functions := []*Function{
+		{Name: "low", Score: 5},
+		{Name: "high", Score: 110},
+		{Name: "threshold", Score: 30},
+		{Name: "middle", Score: 42},
+	}
+
+	assert.Equal(t, Summary{Functions: 4, Crappy: 2, Percentage: 50, Threshold: 30}, Summarize(functions, 30))
+
+	names := make([]string, 0)
+	for _, function := range SortAndLimit(functions, 3) {
+		names = append(names, function.Name)
+	}
+
+	assert.Equal(t, []string{"high", "middle", "threshold"}, names)
+}
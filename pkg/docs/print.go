@@ -0,0 +1,52 @@
+package docs
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/bndr/gotabulate"
+)
+
+func PrintTabular(results []*FileDocs, out io.Writer) {
+	_, _ = io.WriteString(out, "\nDocumentation analysis results:\n")
+
+	data := make([][]any, len(results))
+	for i, result := range results {
+		data[i] = []any{
+			result.File,
+			fmt.Sprintf("%.2f%%", result.Coverage),
+			result.Documented,
+			result.Exported,
+			fmt.Sprintf("%.2f%%", result.Density),
+		}
+	}
+
+	table := gotabulate.Create(data)
+	table.SetHeaders([]string{"FILEPATH", "DOC COVERAGE", "DOCUMENTED", "EXPORTED", "COMMENT DENSITY"})
+	table.SetAlign("left")
+
+	_, _ = io.WriteString(out, table.Render("grid"))
+}
+
+func PrintCSV(results []*FileDocs, out io.Writer) {
+	writer := csv.NewWriter(out)
+	defer writer.Flush()
+
+	_ = writer.Write([]string{
+		"FILEPATH", "DOC_COVERAGE", "DOCUMENTED", "EXPORTED", "COMMENT_DENSITY", "CODE_LINES", "COMMENT_LINES",
+	})
+
+	for _, result := range results {
+		_ = writer.Write([]string{
+			result.File,
+			strconv.FormatFloat(result.Coverage, 'f', 2, 64),
+			strconv.Itoa(result.Documented),
+			strconv.Itoa(result.Exported),
+			strconv.FormatFloat(result.Density, 'f', 2, 64),
+			strconv.Itoa(result.CodeLines),
+			strconv.Itoa(result.CommentLines),
+		})
+	}
+}
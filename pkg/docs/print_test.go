@@ -0,0 +1,31 @@
+package docs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var printDocs = []*FileDocs{
+	{File: "main.go", Exported: 4, Documented: 1, Coverage: 25, CodeLines: 40, CommentLines: 10, Density: 20},
+}
+
+func TestPrintCSV(t *testing.T) {
+	var buf bytes.Buffer
+
+	PrintCSV(printDocs, &buf)
+
+	assert.Equal(t, "FILEPATH,DOC_COVERAGE,DOCUMENTED,EXPORTED,COMMENT_DENSITY,CODE_LINES,COMMENT_LINES\n"+
+		"main.go,25.00,1,4,20.00,40,10\n", buf.String())
+}
+
+func TestPrintTabular(t *testing.T) {
+	var buf bytes.Buffer
+
+	PrintTabular(printDocs, &buf)
+
+	assert.Contains(t, buf.String(), "DOC COVERAGE")
+	assert.Contains(t, buf.String(), "main.go")
+	assert.Contains(t, buf.String(), "25.00%")
+}
@@ -0,0 +1,272 @@
+// Package docs measures how well exported identifiers of Go files are documented.
+package docs
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/module"
+)
+
+const percentMultiplier = 100.0
+
+type SortType = string
+
+const (
+	Worst SortType = "worst"
+	Best  SortType = "best"
+)
+
+var (
+	AvailableSorts = []SortType{Worst, Best}
+
+	ErrUnsupportedSort = errors.New("unsupported sort type")
+)
+
+type Options struct {
+	SortBy       SortType
+	Top          int
+	OutputFormat string
+	// Files selects analyzed Go files the same way complexity engines do, test files are never analyzed.
+	Files complexity.Options
+}
+
+// FileDocs holds documentation metrics of a file or a package.
+type FileDocs struct {
+	File string
+	// Exported is the number of exported functions, methods, types and constants.
+	Exported   int
+	Documented int
+	// Coverage is percentage of documented exported identifiers, 100 if there are none.
+	Coverage     float64
+	CodeLines    int
+	CommentLines int
+	// Density is percentage of comment lines among non-blank lines.
+	Density float64
+}
+
+func PopulateOpts(opts *Options, excludeRegex string) error {
+	if !slices.Contains(AvailableSorts, opts.SortBy) {
+		return fmt.Errorf("%w: %s, expected one of %v", ErrUnsupportedSort, opts.SortBy, AvailableSorts)
+	}
+
+	if err := complexity.PopulateOpts(&opts.Files, excludeRegex); err != nil {
+		return fmt.Errorf("invalid files options: %w", err)
+	}
+
+	return nil
+}
+
+// Analyze computes documentation metrics of every Go file under repoPath except tests,
+// which don't declare API.
+func Analyze(repoPath string, opts *Options) ([]*FileDocs, error) {
+	result := make([]*FileDocs, 0)
+
+	err := complexity.WalkGoFiles(repoPath, &opts.Files, func(path string, file *ast.File, fileSet *token.FileSet) error {
+		if strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(repoPath, path)
+		if err != nil {
+			return fmt.Errorf("failed to get relative path: %w", err)
+		}
+
+		result = append(result, analyzeFile(relPath, file, fileSet))
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk repository: %w", err)
+	}
+
+	return result, nil
+}
+
+func analyzeFile(path string, file *ast.File, fileSet *token.FileSet) *FileDocs {
+	stat := &FileDocs{File: path}
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			countFuncDecl(stat, decl)
+		case *ast.GenDecl:
+			countGenDecl(stat, decl)
+		}
+	}
+
+	stat.CodeLines, stat.CommentLines = countLines(file, fileSet)
+	calculate(stat)
+
+	return stat
+}
+
+// countFuncDecl counts exported functions and methods of exported types.
+func countFuncDecl(stat *FileDocs, decl *ast.FuncDecl) {
+	if decl.Recv == nil || isExportedReceiver(decl.Recv) {
+		countName(stat, decl.Name, decl.Doc)
+	}
+}
+
+// countGenDecl counts exported types and constants, variables aren't counted.
+func countGenDecl(stat *FileDocs, decl *ast.GenDecl) {
+	for _, spec := range decl.Specs {
+		switch spec := spec.(type) {
+		case *ast.TypeSpec:
+			countName(stat, spec.Name, spec.Doc, decl.Doc)
+		case *ast.ValueSpec:
+			if decl.Tok != token.CONST {
+				continue
+			}
+
+			// Comment of const block documents all of its constants.
+			for _, name := range spec.Names {
+				countName(stat, name, spec.Doc, decl.Doc)
+			}
+		}
+	}
+}
+
+// countName counts exported name as documented when any of docs is present.
+func countName(stat *FileDocs, name *ast.Ident, docs ...*ast.CommentGroup) {
+	if !name.IsExported() {
+		return
+	}
+
+	stat.Exported++
+
+	if slices.ContainsFunc(docs, func(doc *ast.CommentGroup) bool { return doc != nil }) {
+		stat.Documented++
+	}
+}
+
+// isExportedReceiver reports whether method receiver type is exported, methods of unexported types aren't API.
+func isExportedReceiver(recv *ast.FieldList) bool {
+	if len(recv.List) == 0 {
+		return false
+	}
+
+	expr := recv.List[0].Type
+	for {
+		switch typ := expr.(type) {
+		case *ast.StarExpr:
+			expr = typ.X
+		case *ast.IndexExpr:
+			expr = typ.X
+		case *ast.IndexListExpr:
+			expr = typ.X
+		case *ast.Ident:
+			return typ.IsExported()
+		default:
+			return false
+		}
+	}
+}
+
+// countLines counts lines with code and lines with comments only, lines with both are code lines.
+func countLines(file *ast.File, fileSet *token.FileSet) (int, int) {
+	commentLines := make(map[int]bool)
+
+	for _, group := range file.Comments {
+		for line := fileSet.Position(group.Pos()).Line; line <= fileSet.Position(group.End()).Line; line++ {
+			commentLines[line] = true
+		}
+	}
+
+	codeLines := make(map[int]bool)
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		if node == nil {
+			return false
+		}
+
+		if _, ok := node.(*ast.CommentGroup); ok {
+			return false
+		}
+
+		codeLines[fileSet.Position(node.Pos()).Line] = true
+		codeLines[fileSet.Position(node.End()).Line] = true
+
+		return true
+	})
+
+	comments := 0
+
+	for line := range commentLines {
+		if !codeLines[line] {
+			comments++
+		}
+	}
+
+	return len(codeLines), comments
+}
+
+func calculate(stat *FileDocs) {
+	stat.Coverage = percentMultiplier
+	if stat.Exported > 0 {
+		stat.Coverage = float64(stat.Documented) * percentMultiplier / float64(stat.Exported)
+	}
+
+	stat.Density = 0
+	if lines := stat.CodeLines + stat.CommentLines; lines > 0 {
+		stat.Density = float64(stat.CommentLines) * percentMultiplier / float64(lines)
+	}
+}
+
+// RollupByModule merges files into one entry per module named after the module path.
+func RollupByModule(files []*FileDocs, modules module.Modules) []*FileDocs {
+	return Rollup(files, modules.Name)
+}
+
+// Rollup sums exported identifiers and code and comment lines of files of every group, coverage and density
+// of the group are recalculated from the sums.
+func Rollup(files []*FileDocs, group func(path string) string) []*FileDocs {
+	groups, names := module.GroupBy(files, func(file *FileDocs) string { return group(file.File) })
+	result := make([]*FileDocs, 0, len(names))
+
+	for _, name := range names {
+		rollup := &FileDocs{File: name}
+		for _, file := range groups[name] {
+			rollup.Exported += file.Exported
+			rollup.Documented += file.Documented
+			rollup.CodeLines += file.CodeLines
+			rollup.CommentLines += file.CommentLines
+		}
+
+		calculate(rollup)
+
+		result = append(result, rollup)
+	}
+
+	return result
+}
+
+// SortAndLimit sorts files by documentation coverage and returns top of them,
+// files without exported identifiers are skipped since there is nothing to document.
+func SortAndLimit(files []*FileDocs, sortBy SortType, top int) []*FileDocs {
+	result := slices.DeleteFunc(slices.Clone(files), func(file *FileDocs) bool { return file.Exported == 0 })
+
+	slices.SortStableFunc(result, func(a, b *FileDocs) int {
+		if a.Coverage == b.Coverage {
+			return strings.Compare(a.File, b.File)
+		}
+
+		if (a.Coverage < b.Coverage) == (sortBy == Worst) {
+			return -1
+		}
+
+		return 1
+	})
+
+	if top > 0 && top < len(result) {
+		return result[:top]
+	}
+
+	return result
+}
@@ -0,0 +1,138 @@
+package docs
+
+import (
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vbvictor/grit/internal/testutil"
+	"github.com/vbvictor/grit/pkg/complexity"
+)
+
+const source = `// Package a is documented.
+package a
+
+// Documented is a documented function.
+func Documented() {}
+
+func Undocumented() {}
+
+func unexported() {}
+
+// Type is a documented type.
+type Type struct{}
+
+// Method is a documented method.
+func (t *Type) Method() {}
+
+func (t Type) Other() {}
+
+type hidden struct{}
+
+// Method of unexported type is not API.
+func (h hidden) Method() {}
+
+// Limits of something.
+const (
+	Min = 1
+	Max = 10
+)
+
+const (
+	// Documented constant.
+	Low  = 1
+	High = 2
+	low  = 3
+)
+
+type (
+	// Grouped is documented.
+	Grouped  int
+	Generic[T any] struct{ value T }
+)
+
+func (g Generic[T]) Value() T { return g.value }
+
+var Exported = 1
+`
+
+func TestAnalyzeFile(t *testing.T) {
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, "a.go", source, parser.ParseComments)
+	require.NoError(t, err)
+
+	stat := analyzeFile("a.go", file, fileSet)
+
+	// Documented, Type, Method, Min, Max, Low, Grouped.
+	assert.Equal(t, 7, stat.Documented)
+	// Undocumented, Other, High, Generic, Value are not documented.
+	assert.Equal(t, 12, stat.Exported)
+	assert.InDelta(t, 7*100.0/12, stat.Coverage, 0.0001)
+	assert.Equal(t, 8, stat.CommentLines)
+	assert.Equal(t, 24, stat.CodeLines)
+	assert.InDelta(t, 25.0, stat.Density, 0.0001)
+}
+
+func TestAnalyze(t *testing.T) {
+	dir := t.TempDir()
+	testutil.WriteFiles(t, dir, map[string]string{
+		"a.go":      source,
+		"a_test.go": "package a\n\nfunc TestA() {}\n",
+		"b/b.go":    "package b\n\nconst B = 1\n",
+	})
+
+	opts := &Options{SortBy: Worst, Files: complexity.Options{NoGitignore: true}}
+	require.NoError(t, PopulateOpts(opts, ""))
+
+	results, err := Analyze(dir, opts)
+	require.NoError(t, err)
+
+	require.Len(t, results, 2)
+	assert.Equal(t, "a.go", results[0].File)
+	assert.Equal(t, filepath.Join("b", "b.go"), results[1].File)
+	assert.InDelta(t, 0.0, results[1].Coverage, 0.0001)
+}
+
+func TestPopulateOpts(t *testing.T) {
+	require.ErrorIs(t, PopulateOpts(&Options{SortBy: "random"}, ""), ErrUnsupportedSort)
+	require.Error(t, PopulateOpts(&Options{SortBy: Worst}, "("))
+	require.NoError(t, PopulateOpts(&Options{SortBy: Best}, ""))
+}
+
+func TestRollup(t *testing.T) {
+	files := []*FileDocs{
+		{File: filepath.Join("pkg", "a.go"), Exported: 4, Documented: 1, CodeLines: 30, CommentLines: 0},
+		{File: filepath.Join("pkg", "b.go"), Exported: 0, Documented: 0, CodeLines: 10, CommentLines: 10},
+		{File: "main.go", Exported: 0, CodeLines: 5},
+	}
+
+	assert.Equal(t, []*FileDocs{
+		{File: ".", Exported: 0, Documented: 0, Coverage: 100, CodeLines: 5},
+		{File: "pkg", Exported: 4, Documented: 1, Coverage: 25, CodeLines: 40, CommentLines: 10, Density: 20},
+	}, Rollup(files, filepath.Dir))
+}
+
+func TestSortAndLimit(t *testing.T) {
+	files := []*FileDocs{
+		{File: "a.go", Exported: 2, Coverage: 50},
+		{File: "b.go", Exported: 0, Coverage: 100},
+		{File: "c.go", Exported: 1, Coverage: 0},
+		{File: "d.go", Exported: 1, Coverage: 100},
+	}
+
+	names := func(files []*FileDocs) []string {
+		result := make([]string, 0, len(files))
+		for _, file := range files {
+			result = append(result, file.File)
+		}
+
+		return result
+	}
+
+	assert.Equal(t, []string{"c.go", "a.go", "d.go"}, names(SortAndLimit(files, Worst, 0)))
+	assert.Equal(t, []string{"d.go", "a.go"}, names(SortAndLimit(files, Best, 2)))
+	assert.Len(t, files, 4, "input must not be modified")
+}
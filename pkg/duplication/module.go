@@ -0,0 +1,32 @@
+package duplication
+
+import "github.com/vbvictor/grit/pkg/module"
+
+// RollupByModule merges files into one entry per module named after the module path,
+// module duplication is the share of duplicated lines of all its files.
+func RollupByModule(files []*FileDuplication, modules module.Modules) []*FileDuplication {
+	return Rollup(files, modules.Name)
+}
+
+// Rollup sums lines and duplicated lines of files of every group named by group function, percentage of the group
+// is computed from the sums.
+func Rollup(files []*FileDuplication, group func(path string) string) []*FileDuplication {
+	groups, names := module.GroupBy(files, func(file *FileDuplication) string { return group(file.File) })
+	result := make([]*FileDuplication, 0, len(names))
+
+	for _, name := range names {
+		rollup := &FileDuplication{File: name}
+		for _, file := range groups[name] {
+			rollup.Lines += file.Lines
+			rollup.DuplicatedLines += file.DuplicatedLines
+		}
+
+		if rollup.Lines > 0 {
+			rollup.Percentage = float64(rollup.DuplicatedLines) * percentMultiplier / float64(rollup.Lines)
+		}
+
+		result = append(result, rollup)
+	}
+
+	return result
+}
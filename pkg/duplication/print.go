@@ -0,0 +1,83 @@
+package duplication
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/bndr/gotabulate"
+)
+
+func PrintTabular(results []*FileDuplication, out io.Writer) {
+	_, _ = io.WriteString(out, "\nCode duplication analysis results:\n")
+
+	data := make([][]any, len(results))
+	for i, result := range results {
+		data[i] = []any{result.File, fmt.Sprintf("%.2f%%", result.Percentage), result.DuplicatedLines, result.Lines}
+	}
+
+	table := gotabulate.Create(data)
+	table.SetHeaders([]string{"FILEPATH", "DUPLICATION", "DUPLICATED LINES", "LINES"})
+	table.SetAlign("left")
+
+	_, _ = io.WriteString(out, table.Render("grid"))
+}
+
+func PrintCSV(results []*FileDuplication, out io.Writer) {
+	writer := csv.NewWriter(out)
+	defer writer.Flush()
+
+	_ = writer.Write([]string{"FILEPATH", "DUPLICATION", "DUPLICATED_LINES", "LINES"})
+
+	for _, result := range results {
+		_ = writer.Write([]string{
+			result.File,
+			strconv.FormatFloat(result.Percentage, 'f', 2, 64),
+			strconv.Itoa(result.DuplicatedLines),
+			strconv.Itoa(result.Lines),
+		})
+	}
+}
+
+func formatLines(fragment Fragment) string {
+	return fmt.Sprintf("%d-%d", fragment.StartLine, fragment.EndLine)
+}
+
+func PrintClonesTabular(results []Clone, out io.Writer) {
+	_, _ = io.WriteString(out, "\nDuplicated code fragments:\n")
+
+	data := make([][]any, len(results))
+	for i, result := range results {
+		data[i] = []any{
+			result.Tokens, result.First.File, formatLines(result.First), result.Second.File, formatLines(result.Second),
+		}
+	}
+
+	table := gotabulate.Create(data)
+	table.SetHeaders([]string{"TOKENS", "FILEPATH", "LINES", "CLONE FILEPATH", "CLONE LINES"})
+	table.SetAlign("left")
+
+	_, _ = io.WriteString(out, table.Render("grid"))
+}
+
+func PrintClonesCSV(results []Clone, out io.Writer) {
+	writer := csv.NewWriter(out)
+	defer writer.Flush()
+
+	_ = writer.Write([]string{
+		"TOKENS", "FILEPATH", "START_LINE", "END_LINE", "CLONE_FILEPATH", "CLONE_START_LINE", "CLONE_END_LINE",
+	})
+
+	for _, result := range results {
+		_ = writer.Write([]string{
+			strconv.Itoa(result.Tokens),
+			result.First.File,
+			strconv.Itoa(result.First.StartLine),
+			strconv.Itoa(result.First.EndLine),
+			result.Second.File,
+			strconv.Itoa(result.Second.StartLine),
+			strconv.Itoa(result.Second.EndLine),
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package duplication
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintCSV(t *testing.T) {
+	var buf bytes.Buffer
+
+	PrintCSV([]*FileDuplication{{File: "main.go", Lines: 40, DuplicatedLines: 10, Percentage: 25}}, &buf)
+
+	assert.Equal(t, "FILEPATH,DUPLICATION,DUPLICATED_LINES,LINES\nmain.go,25.00,10,40\n", buf.String())
+}
+
+func TestPrintTabular(t *testing.T) {
+	var buf bytes.Buffer
+
+	PrintTabular([]*FileDuplication{{File: "main.go", Lines: 40, DuplicatedLines: 10, Percentage: 25}}, &buf)
+
+	assert.Contains(t, buf.String(), "DUPLICATION")
+	assert.Contains(t, buf.String(), "main.go")
+	assert.Contains(t, buf.String(), "25.00%")
+}
+
+func TestPrintClones(t *testing.T) {
+	clones := []Clone{
+		{
+			First:  Fragment{File: "a.go", StartLine: 5, EndLine: 16},
+			Second: Fragment{File: "b.go", StartLine: 9, EndLine: 20},
+			Tokens: 42,
+		},
+	}
+
+	var buf bytes.Buffer
+
+	PrintClonesCSV(clones, &buf)
+	assert.Equal(t, "TOKENS,FILEPATH,START_LINE,END_LINE,CLONE_FILEPATH,CLONE_START_LINE,CLONE_END_LINE\n"+
+		"42,a.go,5,16,b.go,9,20\n", buf.String())
+
+	buf.Reset()
+	PrintClonesTabular(clones, &buf)
+	assert.Contains(t, buf.String(), "5-16")
+	assert.Contains(t, buf.String(), "9-20")
+}
@@ -0,0 +1,327 @@
+// Package duplication finds duplicated token sequences, clones, in Go files.
+package duplication
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/scanner"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/vbvictor/grit/pkg/complexity"
+)
+
+// DefaultMinTokens is the shortest token sequence reported as a clone by default.
+const DefaultMinTokens = 75
+
+const percentMultiplier = 100.0
+
+var ErrInvalidMinTokens = errors.New("minimal clone length must be positive")
+
+type Options struct {
+	// MinTokens is the shortest duplicated token sequence reported as a clone.
+	MinTokens    int
+	Top          int
+	OutputFormat string
+	// Clones makes commands list clone pairs instead of files.
+	Clones bool
+	// Files selects analyzed Go files the same way complexity engines do.
+	Files complexity.Options
+}
+
+// Fragment is a range of lines of a file that is a part of a clone.
+type Fragment struct {
+	File      string
+	StartLine int
+	EndLine   int
+}
+
+// Clone is a pair of fragments with the same normalized token sequence.
+type Clone struct {
+	First  Fragment
+	Second Fragment
+	Tokens int
+}
+
+// FileDuplication holds share of code lines of a file that are a part of any clone.
+type FileDuplication struct {
+	File            string
+	Lines           int
+	DuplicatedLines int
+	Percentage      float64
+}
+
+type Result struct {
+	Clones []Clone
+	Files  []*FileDuplication
+}
+
+func PopulateOpts(opts *Options, excludeRegex string) error {
+	if opts.MinTokens <= 0 {
+		return fmt.Errorf("%w, got %d", ErrInvalidMinTokens, opts.MinTokens)
+	}
+
+	if err := complexity.PopulateOpts(&opts.Files, excludeRegex); err != nil {
+		return fmt.Errorf("invalid files options: %w", err)
+	}
+
+	return nil
+}
+
+// Detect finds clones of at least opts.MinTokens tokens in Go files of repoPath.
+func Detect(repoPath string, opts *Options) (*Result, error) {
+	files := make([]*tokenFile, 0)
+
+	err := complexity.WalkGoFiles(repoPath, &opts.Files, func(path string, _ *ast.File, _ *token.FileSet) error {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+
+		relPath, err := filepath.Rel(repoPath, path)
+		if err != nil {
+			return fmt.Errorf("failed to get relative path: %w", err)
+		}
+
+		files = append(files, tokenize(relPath, src))
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk repository: %w", err)
+	}
+
+	return findClones(files, opts.MinTokens), nil
+}
+
+// tokenFile is a sequence of normalized tokens of a file. Identifiers and literals are normalized to their kind,
+// so that code differing only in names and constants is still a clone.
+type tokenFile struct {
+	path   string
+	tokens []token.Token
+	lines  []int
+	// duplicated marks tokens that are a part of any clone.
+	duplicated []bool
+}
+
+// tokenize scans src skipping comments, semicolons and the package clause with imports,
+// which are similar in every file.
+func tokenize(path string, src []byte) *tokenFile {
+	fileSet := token.NewFileSet()
+	file := fileSet.AddFile(path, -1, len(src))
+
+	var scan scanner.Scanner
+	scan.Init(file, src, nil, 0)
+
+	result := &tokenFile{path: path}
+	header := true
+
+	for {
+		pos, tok, _ := scan.Scan()
+		if tok == token.EOF {
+			break
+		}
+
+		if header {
+			// Top-level declarations start with one of these keywords.
+			if tok != token.FUNC && tok != token.VAR && tok != token.CONST && tok != token.TYPE {
+				continue
+			}
+
+			header = false
+		}
+
+		if tok == token.SEMICOLON {
+			continue
+		}
+
+		result.tokens = append(result.tokens, tok)
+		result.lines = append(result.lines, file.Line(pos))
+	}
+
+	result.duplicated = make([]bool, len(result.tokens))
+
+	return result
+}
+
+type position struct {
+	file  int
+	index int
+}
+
+const hashBase = 1_000_003
+
+// findClones matches windows of minTokens tokens by hash and extends matches as far as tokens are equal.
+// Every window is matched only against the first window with the same hash, so a fragment repeated k times
+// is reported as k-1 clones of its first occurrence, and frequent idioms don't make matching quadratic.
+func findClones(files []*tokenFile, minTokens int) *Result {
+	slices.SortFunc(files, func(a, b *tokenFile) int {
+		return strings.Compare(a.path, b.path)
+	})
+
+	windows := make(map[uint64]position)
+	clones := make([]Clone, 0)
+
+	for fileIdx, file := range files {
+		for index, hash := range windowHashes(file.tokens, minTokens) {
+			current := position{file: fileIdx, index: index}
+
+			first, found := windows[hash]
+			if !found {
+				windows[hash] = current
+
+				continue
+			}
+
+			if clone, ok := extendClone(files, first, current, minTokens); ok {
+				clones = append(clones, clone)
+			}
+		}
+	}
+
+	sortClones(clones)
+
+	return &Result{Clones: clones, Files: fileDuplication(files)}
+}
+
+// windowHashes returns rolling hashes of every window of size tokens.
+func windowHashes(tokens []token.Token, size int) []uint64 {
+	if len(tokens) < size {
+		return nil
+	}
+
+	var power, hash uint64 = 1, 0
+
+	for i := range size {
+		hash = hash*hashBase + uint64(tokens[i])
+
+		if i > 0 {
+			power *= hashBase
+		}
+	}
+
+	hashes := make([]uint64, 0, len(tokens)-size+1)
+	hashes = append(hashes, hash)
+
+	for i := size; i < len(tokens); i++ {
+		hash = (hash-uint64(tokens[i-size])*power)*hashBase + uint64(tokens[i])
+		hashes = append(hashes, hash)
+	}
+
+	return hashes
+}
+
+func extendClone(files []*tokenFile, first, second position, minTokens int) (Clone, bool) {
+	firstFile, secondFile := files[first.file], files[second.file]
+
+	// Clone starting at preceding tokens already covers this pair.
+	if first.index > 0 && second.index > 0 &&
+		firstFile.tokens[first.index-1] == secondFile.tokens[second.index-1] {
+		return Clone{}, false
+	}
+
+	maxLength := min(len(firstFile.tokens)-first.index, len(secondFile.tokens)-second.index)
+	if first.file == second.file {
+		// Fragments of the same file must not overlap.
+		maxLength = min(maxLength, second.index-first.index)
+	}
+
+	length := 0
+	for length < maxLength && firstFile.tokens[first.index+length] == secondFile.tokens[second.index+length] {
+		length++
+	}
+
+	if length < minTokens {
+		return Clone{}, false
+	}
+
+	return Clone{
+		First:  firstFile.fragment(first.index, length),
+		Second: secondFile.fragment(second.index, length),
+		Tokens: length,
+	}, true
+}
+
+// fragment returns lines of length tokens starting at index and marks them as duplicated.
+func (f *tokenFile) fragment(index, length int) Fragment {
+	for i := index; i < index+length; i++ {
+		f.duplicated[i] = true
+	}
+
+	return Fragment{File: f.path, StartLine: f.lines[index], EndLine: f.lines[index+length-1]}
+}
+
+func fileDuplication(files []*tokenFile) []*FileDuplication {
+	result := make([]*FileDuplication, 0, len(files))
+
+	for _, file := range files {
+		lines := make(map[int]bool)
+		for i, line := range file.lines {
+			lines[line] = lines[line] || file.duplicated[i]
+		}
+
+		stat := &FileDuplication{File: file.path, Lines: len(lines)}
+
+		for _, duplicated := range lines {
+			if duplicated {
+				stat.DuplicatedLines++
+			}
+		}
+
+		if stat.Lines > 0 {
+			stat.Percentage = float64(stat.DuplicatedLines) * percentMultiplier / float64(stat.Lines)
+		}
+
+		result = append(result, stat)
+	}
+
+	return result
+}
+
+func sortClones(clones []Clone) {
+	slices.SortStableFunc(clones, func(a, b Clone) int {
+		if a.Tokens != b.Tokens {
+			return b.Tokens - a.Tokens
+		}
+
+		if a.First.File != b.First.File {
+			return strings.Compare(a.First.File, b.First.File)
+		}
+
+		return a.First.StartLine - b.First.StartLine
+	})
+}
+
+// SortAndLimit sorts files by duplicated lines percentage and returns top of them.
+func SortAndLimit(files []*FileDuplication, top int) []*FileDuplication {
+	slices.SortStableFunc(files, func(a, b *FileDuplication) int {
+		if a.Percentage != b.Percentage {
+			if a.Percentage > b.Percentage {
+				return -1
+			}
+
+			return 1
+		}
+
+		return strings.Compare(a.File, b.File)
+	})
+
+	if top > 0 && top < len(files) {
+		return files[:top]
+	}
+
+	return files
+}
+
+// TopClones returns top of clones which are sorted by length.
+func TopClones(clones []Clone, top int) []Clone {
+	if top > 0 && top < len(clones) {
+		return clones[:top]
+	}
+
+	return clones
+}
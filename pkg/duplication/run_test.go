@@ -0,0 +1,198 @@
+package duplication
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vbvictor/grit/internal/testutil"
+	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/module"
+)
+
+const original = `package a
+
+import "fmt"
+
+func Sum(values []int) int {
+	total := 0
+	for _, value := range values {
+		if value > 0 {
+			total += value
+		}
+	}
+
+	fmt.Println(total)
+
+	return total
+}
+`
+
+// renamed differs from original only in names and literals.
+const renamed = `package b
+
+import (
+	"fmt"
+	"os"
+)
+
+// Add is a copy of Sum.
+func Add(items []int) int {
+	result := 10
+	for _, item := range items {
+		if item > 5 {
+			result += item
+		}
+	}
+
+	fmt.Println(result)
+
+	return result
+}
+
+func Other() { os.Exit(1) }
+`
+
+const unique = `package c
+
+type Point struct {
+	X, Y int
+}
+`
+
+func TestTokenize(t *testing.T) {
+	file := tokenize("a.go", []byte(original))
+
+	// Package clause and imports are skipped.
+	require.NotEmpty(t, file.tokens)
+	assert.Equal(t, "func", file.tokens[0].String())
+	assert.Equal(t, 5, file.lines[0])
+	assert.Len(t, file.lines, len(file.tokens))
+	assert.NotContains(t, file.tokens, "SEMICOLON")
+
+	assert.Equal(t, file.tokens, tokenize("b.go", []byte(original)).tokens)
+}
+
+func TestFindClones(t *testing.T) {
+	files := []*tokenFile{
+		tokenize("b.go", []byte(renamed)),
+		tokenize("c.go", []byte(unique)),
+		tokenize("a.go", []byte(original)),
+	}
+
+	result := findClones(files, 20)
+
+	require.Len(t, result.Clones, 1)
+	assert.Equal(t, Fragment{File: "a.go", StartLine: 5, EndLine: 16}, result.Clones[0].First)
+	assert.Equal(t, Fragment{File: "b.go", StartLine: 9, EndLine: 20}, result.Clones[0].Second)
+
+	assert.Equal(t, []*FileDuplication{
+		{File: "a.go", Lines: 10, DuplicatedLines: 10, Percentage: 100},
+		{File: "b.go", Lines: 11, DuplicatedLines: 10, Percentage: 10 * 100.0 / 11},
+		{File: "c.go", Lines: 3, DuplicatedLines: 0, Percentage: 0},
+	}, result.Files)
+
+	assert.Empty(t, findClones(files, 1000).Clones)
+}
+
+func TestFindClonesRepeatedFragment(t *testing.T) {
+	files := []*tokenFile{
+		tokenize("c.go", []byte(original)),
+		tokenize("a.go", []byte(original)),
+		tokenize("b.go", []byte(renamed)),
+	}
+
+	result := findClones(files, 20)
+
+	// Every copy is a clone of the first occurrence only.
+	first := Fragment{File: "a.go", StartLine: 5, EndLine: 16}
+	assert.Equal(t, []Clone{
+		{First: first, Second: Fragment{File: "b.go", StartLine: 9, EndLine: 20}, Tokens: 40},
+		{First: first, Second: Fragment{File: "c.go", StartLine: 5, EndLine: 16}, Tokens: 40},
+	}, result.Clones)
+}
+
+func TestFindClonesSameFile(t *testing.T) {
+	const repeated = `package a
+
+func A() {
+	x := 1
+	x++
+	x++
+	x++
+	x++
+}
+`
+
+	result := findClones([]*tokenFile{tokenize("a.go", []byte(repeated))}, 4)
+
+	// Overlapping fragments at lines 5-6 and 6-7 are not clones.
+	assert.Equal(t, []Clone{
+		{
+			First:  Fragment{File: "a.go", StartLine: 5, EndLine: 6},
+			Second: Fragment{File: "a.go", StartLine: 7, EndLine: 8},
+			Tokens: 4,
+		},
+	}, result.Clones)
+}
+
+func TestDetect(t *testing.T) {
+	dir := t.TempDir()
+	testutil.WriteFiles(t, dir, map[string]string{
+		"a/a.go":      original,
+		"b/b.go":      renamed,
+		"b/b_test.go": original,
+		"vendor/v.go": original,
+		"c/broken.go": "package c\nfunc {",
+	})
+
+	opts := &Options{MinTokens: 20, Files: complexity.Options{NoGitignore: true}}
+	opts.Files.Tests.Mode = "exclude"
+	require.NoError(t, PopulateOpts(opts, ""))
+
+	result, err := Detect(dir, opts)
+	require.NoError(t, err)
+
+	require.Len(t, result.Clones, 1)
+	assert.Equal(t, filepath.Join("a", "a.go"), result.Clones[0].First.File)
+	assert.Equal(t, filepath.Join("b", "b.go"), result.Clones[0].Second.File)
+	assert.Len(t, result.Files, 2)
+}
+
+func TestPopulateOpts(t *testing.T) {
+	require.ErrorIs(t, PopulateOpts(&Options{MinTokens: 0}, ""), ErrInvalidMinTokens)
+	require.Error(t, PopulateOpts(&Options{MinTokens: 10}, "("))
+	require.NoError(t, PopulateOpts(&Options{MinTokens: 10}, "_gen.go$"))
+}
+
+func TestSortAndLimit(t *testing.T) {
+	files := []*FileDuplication{
+		{File: "b.go", Percentage: 10},
+		{File: "c.go", Percentage: 50},
+		{File: "a.go", Percentage: 10},
+	}
+
+	result := SortAndLimit(files, 2)
+
+	require.Len(t, result, 2)
+	assert.Equal(t, "c.go", result[0].File)
+	assert.Equal(t, "a.go", result[1].File)
+
+	assert.Len(t, TopClones([]Clone{{Tokens: 3}, {Tokens: 2}, {Tokens: 1}}, 2), 2)
+	assert.Len(t, TopClones([]Clone{{Tokens: 3}}, 0), 1)
+}
+
+func TestRollupByModule(t *testing.T) {
+	modules := module.Modules{{Path: "example.com/root", Dir: "."}, {Path: "example.com/root/tools", Dir: "tools"}}
+	files := []*FileDuplication{
+		{File: "main.go", Lines: 10, DuplicatedLines: 5},
+		{File: "pkg/a.go", Lines: 30, DuplicatedLines: 5},
+		{File: "tools/gen.go", Lines: 4, DuplicatedLines: 1},
+	}
+
+	assert.Equal(t, []*FileDuplication{
+		{File: "example.com/root", Lines: 40, DuplicatedLines: 10, Percentage: 25},
+		{File: "example.com/root/tools", Lines: 4, DuplicatedLines: 1, Percentage: 25},
+	}, RollupByModule(files, modules))
+}
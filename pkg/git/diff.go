@@ -0,0 +1,84 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var errInvalidHunk = errors.New("invalid diff hunk header")
+
+// ChangedLines returns lines added or modified in the working tree of path since it diverged from base,
+// i.e. since the merge base of base and HEAD. Files are relative to path, deleted files aren't reported.
+func ChangedLines(path, base string) (map[string][]int, error) {
+	output, err := executeGitCommand(path,
+		[]string{"git", "diff", "--merge-base", base, "--unified=0", "--no-color", "--no-ext-diff", "--relative",
+			"--src-prefix=a/", "--dst-prefix=b/"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff with %s: %w", base, err)
+	}
+
+	return parseChangedLines(output)
+}
+
+// parseChangedLines reads added lines of hunks of unified diff with zero context lines.
+func parseChangedLines(diff []byte) (map[string][]int, error) {
+	changed := make(map[string][]int)
+	file := ""
+	scanner := bufio.NewScanner(bytes.NewReader(diff))
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			file = ""
+			if name, ok := strings.CutPrefix(line, "+++ b/"); ok {
+				file = filepath.FromSlash(name)
+			}
+		case strings.HasPrefix(line, "@@ ") && file != "":
+			start, count, err := parseHunk(line)
+			if err != nil {
+				return nil, err
+			}
+
+			for lineNumber := start; lineNumber < start+count; lineNumber++ {
+				changed[file] = append(changed[file], lineNumber)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read diff: %w", err)
+	}
+
+	return changed, nil
+}
+
+// parseHunk returns the first line and the number of lines of the new side of hunk '@@ -a,b +c,d @@'.
+func parseHunk(header string) (int, int, error) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") { //nolint:mnd // '@@', old and new ranges
+		return 0, 0, fmt.Errorf("%w: %s", errInvalidHunk, header)
+	}
+
+	startText, countText, hasCount := strings.Cut(strings.TrimPrefix(fields[2], "+"), ",")
+
+	start, err := strconv.Atoi(startText)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %s", errInvalidHunk, header)
+	}
+
+	count := 1
+	if hasCount {
+		if count, err = strconv.Atoi(countText); err != nil {
+			return 0, 0, fmt.Errorf("%w: %s", errInvalidHunk, header)
+		}
+	}
+
+	return start, count, nil
+}
@@ -0,0 +1,101 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChangedLines(t *testing.T) {
+	diff := `diff --git a/pkg/a.go b/pkg/a.go
+index 1111111..2222222 100644
+--- a/pkg/a.go
++++ b/pkg/a.go
+@@ -3,0 +4,2 @@ func A() {
++	b()
++	c()
+@@ -10 +12 @@ func A() {
+-	old()
++	updated()
+@@ -20,3 +21,0 @@ func B() {
+-	removed()
+diff --git a/removed.go b/removed.go
+deleted file mode 100644
+--- a/removed.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package removed
+-
+diff --git a/new.go b/new.go
+new file mode 100644
+--- /dev/null
++++ b/new.go
+@@ -0,0 +1,3 @@
++package new
++
++func New() {}
+`
+
+	changed, err := parseChangedLines([]byte(diff))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]int{
+		filepath.Join("pkg", "a.go"): {4, 5, 12},
+		"new.go":                     {1, 2, 3},
+	}, changed)
+}
+
+func TestParseHunk(t *testing.T) {
+	tests := []struct {
+		header    string
+		wantStart int
+		wantCount int
+		wantErr   bool
+	}{
+		{header: "@@ -1,2 +3,4 @@ func main() {", wantStart: 3, wantCount: 4},
+		{header: "@@ -1 +3 @@", wantStart: 3, wantCount: 1},
+		{header: "@@ -5,2 +4,0 @@", wantStart: 4, wantCount: 0},
+		{header: "@@ -1 3 @@", wantErr: true},
+		{header: "@@ -1 +x,2 @@", wantErr: true},
+		{header: "@@", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			start, count, err := parseHunk(tt.header)
+			if tt.wantErr {
+				require.ErrorIs(t, err, errInvalidHunk)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantCount, count)
+		})
+	}
+}
+
+func TestChangedLines(t *testing.T) {
+	repoDir := t.TempDir()
+	Unbundle(t, filepath.Join("..", "..", "testdata", "bundles", "churn-test.bundle"), repoDir)
+
+	// uncommitted changes of the working tree are part of the patch
+	data, err := os.ReadFile(filepath.Join(repoDir, "main.go"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "main.go"), append(data, "\nfunc extra() {}\n"...), 0o600))
+
+	changed, err := ChangedLines(repoDir, "HEAD~2")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]int{"main.go": {1, 2, 3, 4, 5, 6, 7, 8}}, changed)
+
+	changed, err = ChangedLines(repoDir, "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]int{"main.go": {7, 8}}, changed)
+
+	_, err = ChangedLines(repoDir, "unknown-revision")
+	require.Error(t, err)
+}
@@ -0,0 +1,144 @@
+package git
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Revision is a commit sampled from repository history.
+type Revision struct {
+	Hash string
+	Date time.Time
+	// Tag is set when revision was sampled by tag.
+	Tag string
+}
+
+const shortHashLength = 7
+
+// Name returns tag of the revision or its abbreviated hash.
+func (r Revision) Name() string {
+	if r.Tag != "" {
+		return r.Tag
+	}
+
+	if len(r.Hash) > shortHashLength {
+		return r.Hash[:shortHashLength]
+	}
+
+	return r.Hash
+}
+
+// SampleCommits returns up to samples commits evenly spaced between since and until, oldest first.
+// The first and the last commit of the range are always included. Only first-parent history is sampled,
+// so merged branches contribute their merge commits only.
+func SampleCommits(path string, since, until time.Time, samples int) ([]Revision, error) {
+	cmd := []string{"git", "log", "--first-parent", "--format=%H %cI"}
+	cmd = append(cmd, dateRange(since, until)...)
+
+	output, err := executeGitCommand(path, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]Revision, 0)
+
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		hash, date, found := strings.Cut(line, " ")
+		if !found {
+			continue
+		}
+
+		commitDate, err := time.Parse(time.RFC3339, date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse date of commit %s: %w", hash, err)
+		}
+
+		commits = append(commits, Revision{Hash: hash, Date: commitDate})
+	}
+
+	slices.Reverse(commits)
+
+	return evenlySpaced(commits, samples), nil
+}
+
+// TagRevisions returns commits of all tags created between since and until, oldest first.
+func TagRevisions(path string, since, until time.Time) ([]Revision, error) {
+	// Annotated tags point to commits through dereferenced '*' fields, lightweight tags point to commits directly.
+	output, err := executeGitCommand(path, []string{
+		"git", "for-each-ref", "refs/tags",
+		"--format=%(refname:short) %(objectname) %(*objectname) %(committerdate:iso-strict) %(*committerdate:iso-strict)",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]Revision, 0)
+
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Fields(line)
+
+		var tag, hash, date string
+
+		switch len(fields) {
+		case 3: //nolint:mnd // lightweight tag: name, commit and its date
+			tag, hash, date = fields[0], fields[1], fields[2]
+		case 4: //nolint:mnd // annotated tag: name, tag object, commit and its date
+			tag, hash, date = fields[0], fields[2], fields[3]
+		default:
+			continue // tags of trees and blobs
+		}
+
+		tagDate, err := time.Parse(time.RFC3339, date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse date of tag %s: %w", tag, err)
+		}
+
+		if (!since.IsZero() && tagDate.Before(since)) || (!until.IsZero() && tagDate.After(until)) {
+			continue
+		}
+
+		tags = append(tags, Revision{Hash: hash, Date: tagDate, Tag: tag})
+	}
+
+	slices.SortStableFunc(tags, func(a, b Revision) int {
+		return a.Date.Compare(b.Date)
+	})
+
+	return tags, nil
+}
+
+func dateRange(since, until time.Time) []string {
+	args := make([]string, 0, 2) //nolint:mnd // since and until
+
+	if !since.IsZero() {
+		args = append(args, "--since="+since.Format(time.DateOnly))
+	}
+
+	if !until.IsZero() {
+		args = append(args, "--until="+until.Format(time.DateOnly))
+	}
+
+	return args
+}
+
+// evenlySpaced picks samples revisions with equal distance between them, including the first and the last one.
+func evenlySpaced(revisions []Revision, samples int) []Revision {
+	if samples <= 0 || samples >= len(revisions) {
+		return revisions
+	}
+
+	if samples == 1 {
+		return revisions[len(revisions)-1:]
+	}
+
+	result := make([]Revision, 0, samples)
+	step := float64(len(revisions)-1) / float64(samples-1)
+
+	for i := range samples {
+		result = append(result, revisions[int(float64(i)*step+0.5)]) //nolint:mnd // round to nearest
+	}
+
+	return result
+}
@@ -0,0 +1,91 @@
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvenlySpaced(t *testing.T) {
+	revisions := make([]Revision, 10)
+	for i := range revisions {
+		revisions[i] = Revision{Hash: string(rune('a' + i))}
+	}
+
+	hashes := func(revisions []Revision) string {
+		result := ""
+		for _, revision := range revisions {
+			result += revision.Hash
+		}
+
+		return result
+	}
+
+	assert.Equal(t, "abcdefghij", hashes(evenlySpaced(revisions, 0)))
+	assert.Equal(t, "abcdefghij", hashes(evenlySpaced(revisions, 20)))
+	assert.Equal(t, "j", hashes(evenlySpaced(revisions, 1)))
+	assert.Equal(t, "aj", hashes(evenlySpaced(revisions, 2)))
+	assert.Equal(t, "afj", hashes(evenlySpaced(revisions, 3)))
+	assert.Equal(t, "adgj", hashes(evenlySpaced(revisions, 4)))
+}
+
+func TestRevisionName(t *testing.T) {
+	assert.Equal(t, "v1.0.0", Revision{Hash: "d4943f6ccc7403f3808b1d791dd086eee1b22036", Tag: "v1.0.0"}.Name())
+	assert.Equal(t, "d4943f6", Revision{Hash: "d4943f6ccc7403f3808b1d791dd086eee1b22036"}.Name())
+}
+
+func TestSampleCommits(t *testing.T) {
+	repoDir := t.TempDir()
+	Unbundle(t, filepath.Join("..", "..", "testdata", "bundles", "churn-test.bundle"), repoDir)
+
+	revisions, err := SampleCommits(repoDir, time.Time{}, time.Time{}, 3)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+
+	assert.Equal(t, "2cb21c0c7bd2e0861fbc7bac447c8e3040c47c36", revisions[0].Hash)
+	assert.Equal(t, "ed1fa5142da6bde01f6c1911fbe6898e27482edb", revisions[1].Hash)
+	assert.Equal(t, "d4943f6ccc7403f3808b1d791dd086eee1b22036", revisions[2].Hash)
+	assert.Equal(t, "2024-11-23", revisions[2].Date.Format(time.DateOnly))
+
+	revisions, err = SampleCommits(repoDir, time.Date(2024, 11, 20, 0, 0, 0, 0, time.UTC), time.Time{}, 0)
+	require.NoError(t, err)
+	assert.Len(t, revisions, 3)
+}
+
+func TestTagRevisions(t *testing.T) {
+	repoDir := t.TempDir()
+	Unbundle(t, filepath.Join("..", "..", "testdata", "bundles", "churn-test.bundle"), repoDir)
+
+	tag := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@test", "tag"}, args...)...)
+		cmd.Dir = repoDir
+		require.NoError(t, cmd.Run())
+	}
+
+	tag("v0.2.0", "HEAD")
+	tag("-a", "v0.1.0", "-m", "first release", "HEAD~3")
+
+	revisions, err := TagRevisions(repoDir, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, []Revision{
+		{
+			Hash: "10546ac19424f2e1aa302eea45049405e79224cc",
+			Date: time.Date(2024, 11, 19, 18, 20, 22, 0, time.FixedZone("", 3*60*60)),
+			Tag:  "v0.1.0",
+		},
+		{
+			Hash: "d4943f6ccc7403f3808b1d791dd086eee1b22036",
+			Date: time.Date(2024, 11, 23, 18, 22, 59, 0, time.FixedZone("", 3*60*60)),
+			Tag:  "v0.2.0",
+		},
+	}, revisions)
+
+	revisions, err = TagRevisions(repoDir, time.Date(2024, 11, 20, 0, 0, 0, 0, time.UTC), time.Time{})
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, "v0.2.0", revisions[0].Tag)
+}
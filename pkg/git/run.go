@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/spf13/pflag"
+	"github.com/vbvictor/grit/pkg/testfiles"
 	"golang.org/x/exp/maps"
 )
 
@@ -63,6 +64,10 @@ type ChurnOptions struct {
 	Since        time.Time
 	Until        time.Time
 	OutputFormat string
+	Tests        testfiles.Filter
+	// GroupBy maps file path to name of a group, e.g. module, whose churn is reported instead of files.
+	// Commit touching several files of a group is counted once.
+	GroupBy func(path string) string
 }
 
 type ChurnChunk struct {
@@ -112,6 +117,10 @@ func PopulateOpts(opts *ChurnOptions, extensionList []string,
 		}
 	}
 
+	if err := opts.Tests.Compile(); err != nil {
+		return fmt.Errorf("invalid tests option: %w", err)
+	}
+
 	return nil
 }
 
@@ -163,15 +172,7 @@ func ReadGitChurn(repoPath string, opts *ChurnOptions) ([]*ChurnChunk, error) {
 
 func buildGitCommand(opts *ChurnOptions) []string {
 	cmd := []string{"git", "log", "--pretty=format:%H", "--numstat"}
-
-	if !opts.Since.IsZero() {
-		cmd = append(cmd, "--since="+opts.Since.Format(time.DateOnly))
-	}
-
-	if !opts.Until.IsZero() {
-		cmd = append(cmd, "--until="+opts.Until.Format(time.DateOnly))
-	}
-
+	cmd = append(cmd, dateRange(opts.Since, opts.Until)...)
 	cmd = append(cmd, "--", ".")
 
 	return cmd
@@ -199,7 +200,7 @@ func processLines(lines []string, fileStats map[string]*ChurnChunk, opts *ChurnO
 		}
 
 		if len(line) == HashLength {
-			processCommit(currentCommit, modifiedInCommit, fileStats, opts)
+			processCommit(currentCommit, modifiedInCommit, fileStats)
 			currentCommit = line
 			modifiedInCommit = make(map[string]bool)
 		} else {
@@ -208,17 +209,10 @@ func processLines(lines []string, fileStats map[string]*ChurnChunk, opts *ChurnO
 	}
 }
 
-func processCommit(currentCommit string, modifiedInCommit map[string]bool, fileStats map[string]*ChurnChunk,
-	opts *ChurnOptions,
-) {
+func processCommit(currentCommit string, modifiedInCommit map[string]bool, fileStats map[string]*ChurnChunk) {
 	if currentCommit != "" && len(modifiedInCommit) > 0 {
-		for file := range modifiedInCommit {
-			path := file
-
-			if shouldSkipFile(path, opts) {
-				continue
-			}
-
+		// Skipped files never get into modifiedInCommit.
+		for path := range modifiedInCommit {
 			fileStats[path].Commits++
 		}
 	}
@@ -238,6 +232,10 @@ func processFileLine(line string, fileStats map[string]*ChurnChunk, modifiedInCo
 			return
 		}
 
+		if opts.GroupBy != nil {
+			path = opts.GroupBy(path)
+		}
+
 		updateFileStats(fileStats, path, additions, deletions)
 
 		modifiedInCommit[path] = true
@@ -265,6 +263,10 @@ func shouldSkipFile(file string, opts *ChurnOptions) bool {
 		return true
 	}
 
+	if opts.Tests.Skip(file) {
+		return true
+	}
+
 	if opts.Extensions != nil {
 		fileExt := filepath.Ext(file)
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vbvictor/grit/pkg/testfiles"
 )
 
 var testData = []*ChurnChunk{
@@ -193,6 +194,21 @@ func TestReadChurn(t *testing.T) {
 	}
 }
 
+func TestReadChurnGroupBy(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	Unbundle(t, filepath.Join("..", "..", "testdata", "bundles", "churn-test.bundle"), tmpDir)
+
+	results, err := ReadGitChurn(tmpDir, &ChurnOptions{
+		GroupBy: func(string) string { return "example.com/module" },
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []*ChurnChunk{
+		{File: "example.com/module", Added: 25, Removed: 8, Churn: 33, Commits: 5},
+	}, results)
+}
+
 func Unbundle(t *testing.T, src, dst string) {
 	t.Helper()
 
@@ -261,10 +277,24 @@ func TestShouldSkipFile(t *testing.T) {
 			},
 			expected: false,
 		},
+		{
+			name:     "test file excluded",
+			file:     "pkg/run_test.go",
+			opts:     &ChurnOptions{Tests: testfiles.Filter{Mode: testfiles.Exclude}},
+			expected: true,
+		},
+		{
+			name:     "production file skipped when only tests requested",
+			file:     "pkg/run.go",
+			opts:     &ChurnOptions{Tests: testfiles.Filter{Mode: testfiles.Only}},
+			expected: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.opts.Tests.Compile())
+
 			result := shouldSkipFile(tt.file, tt.opts)
 			assert.Equal(t, tt.expected, result)
 		})
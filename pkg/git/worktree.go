@@ -0,0 +1,54 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Worktree is a temporary checkout of a git revision, created to analyze code without touching the working tree.
+type Worktree struct {
+	// Path is the analyzed directory inside the checkout.
+	Path string
+	root string
+	repo string
+}
+
+// AddWorktree checks out ref into a temporary directory.
+// When path is a subdirectory of a repository, Worktree.Path points to the same subdirectory in the checkout.
+func AddWorktree(path, ref string) (*Worktree, error) {
+	prefix, err := executeGitCommand(path, []string{"git", "rev-parse", "--show-prefix"})
+	if err != nil {
+		return nil, fmt.Errorf("%s is not inside a git repository: %w", path, err)
+	}
+
+	root, err := os.MkdirTemp("", "grit-worktree-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+
+	args := []string{"git", "worktree", "add", "--detach", "--quiet", root, ref}
+	if _, err := executeGitCommand(path, args); err != nil {
+		_ = os.RemoveAll(root)
+
+		return nil, fmt.Errorf("failed to check out revision %s: %w", ref, err)
+	}
+
+	return &Worktree{
+		Path: filepath.Join(root, filepath.FromSlash(strings.TrimSpace(string(prefix)))),
+		root: root,
+		repo: path,
+	}, nil
+}
+
+// Remove deletes the checkout and unregisters it from the repository.
+func (w *Worktree) Remove() error {
+	if _, err := executeGitCommand(w.repo, []string{"git", "worktree", "remove", "--force", w.root}); err != nil {
+		_ = os.RemoveAll(w.root)
+
+		return fmt.Errorf("failed to remove worktree %s: %w", w.root, err)
+	}
+
+	return nil
+}
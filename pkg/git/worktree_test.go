@@ -0,0 +1,31 @@
+package git
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddWorktree(t *testing.T) {
+	repoDir := t.TempDir()
+	Unbundle(t, filepath.Join("..", "..", "testdata", "bundles", "churn-test.bundle"), repoDir)
+
+	worktree, err := AddWorktree(repoDir, "HEAD~2")
+	require.NoError(t, err)
+
+	assert.NoFileExists(t, filepath.Join(worktree.Path, "main.go"))
+	assert.FileExists(t, filepath.Join(worktree.Path, "main.cpp"))
+
+	require.NoError(t, worktree.Remove())
+	assert.NoDirExists(t, worktree.Path)
+
+	worktree, err = AddWorktree(repoDir, "HEAD~1")
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(worktree.Path, "main.go"))
+	require.NoError(t, worktree.Remove())
+
+	_, err = AddWorktree(repoDir, "unknown-revision")
+	require.Error(t, err)
+}
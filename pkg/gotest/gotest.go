@@ -0,0 +1,182 @@
+// Package gotest configures 'go test' runs collecting coverage and reads failures from their JSON output.
+package gotest
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultPackages = "./..."
+
+var ErrInvalidOptions = errors.New("invalid test options")
+
+// Options are options of 'go test', zero values leave defaults of go test.
+type Options struct {
+	Packages []string
+	Tags     []string
+	Race     bool
+	Short    bool
+	Timeout  time.Duration
+	// CoverPkg is a comma-separated list of package patterns coverage is collected for, see -coverpkg.
+	CoverPkg string
+	// Parallel is the number of packages tested in parallel, see -p.
+	Parallel int
+	// Env holds KEY=VALUE variables added to environment of go test.
+	Env []string
+}
+
+// Validate checks values that go test would reject after building packages.
+func (o *Options) Validate() error {
+	if o.Timeout < 0 {
+		return fmt.Errorf("%w: timeout must not be negative, got %s", ErrInvalidOptions, o.Timeout)
+	}
+
+	if o.Parallel < 0 {
+		return fmt.Errorf("%w: parallelism must not be negative, got %d", ErrInvalidOptions, o.Parallel)
+	}
+
+	for _, env := range o.Env {
+		if name, _, ok := strings.Cut(env, "="); !ok || name == "" {
+			return fmt.Errorf("%w: environment variable %q must have KEY=VALUE form", ErrInvalidOptions, env)
+		}
+	}
+
+	return nil
+}
+
+// Args returns arguments of 'go test' writing coverage profile to coverProfile and reporting events as JSON.
+func (o *Options) Args(coverProfile string) []string {
+	args := []string{"test", "-json", "-coverprofile=" + coverProfile}
+
+	if len(o.Tags) > 0 {
+		args = append(args, "-tags="+strings.Join(o.Tags, ","))
+	}
+
+	if o.Race {
+		args = append(args, "-race")
+	}
+
+	if o.Short {
+		args = append(args, "-short")
+	}
+
+	if o.Timeout > 0 {
+		args = append(args, "-timeout="+o.Timeout.String())
+	}
+
+	if o.CoverPkg != "" {
+		args = append(args, "-coverpkg="+o.CoverPkg)
+	}
+
+	if o.Parallel > 0 {
+		args = append(args, "-p="+strconv.Itoa(o.Parallel))
+	}
+
+	packages := o.Packages
+	if len(packages) == 0 {
+		packages = []string{DefaultPackages}
+	}
+
+	return append(args, packages...)
+}
+
+// Failure is a package whose tests failed or which failed to build. Tests are names of failed top-level tests,
+// they are empty when the package failed as a whole, e.g. on build error, panic or timeout.
+type Failure struct {
+	Package string
+	Tests   []string
+}
+
+func (f Failure) String() string {
+	if len(f.Tests) == 0 {
+		return "package " + f.Package + " failed"
+	}
+
+	return fmt.Sprintf("package %s failed: %s", f.Package, strings.Join(f.Tests, ", "))
+}
+
+// event is a line of 'go test -json' output, see 'go doc test2json'.
+type event struct {
+	Action  string
+	Package string
+	Test    string
+	Output  string
+}
+
+// Summary is the outcome of tests of packages.
+type Summary struct {
+	// Passed is the number of packages that didn't fail, including packages without tests.
+	Passed   int
+	Failures []Failure
+}
+
+// ReadEvents reads 'go test -json' output and summarizes results of packages, failures are in order they happened.
+// Output of tests and lines that aren't events, e.g. build errors of older Go versions, are copied to output.
+func ReadEvents(events io.Reader, output io.Writer) (Summary, error) {
+	results := &summarizer{
+		summary: Summary{Passed: 0, Failures: make([]Failure, 0)},
+		tests:   make(map[string][]string),
+	}
+	scanner := bufio.NewScanner(events)
+	scanner.Buffer(nil, bufio.MaxScanTokenSize*16) //nolint:mnd // long lines of test output
+
+	for scanner.Scan() {
+		var e event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.Action == "" {
+			_, _ = fmt.Fprintln(output, scanner.Text())
+
+			continue
+		}
+
+		results.handle(e, output)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return results.summary, fmt.Errorf("failed to read test events: %w", err)
+	}
+
+	return results.summary, nil
+}
+
+// summarizer collects results of packages from test events.
+type summarizer struct {
+	summary Summary
+	// tests holds failed top-level tests by package until the package fails.
+	tests map[string][]string
+}
+
+func (s *summarizer) handle(e event, output io.Writer) {
+	switch {
+	case e.Action == "output" || e.Action == "build-output":
+		_, _ = io.WriteString(output, e.Output)
+	case e.Test != "":
+		s.handleTest(e)
+	default:
+		s.handlePackage(e)
+	}
+}
+
+// handleTest remembers failed top-level tests, subtests fail with their parents.
+func (s *summarizer) handleTest(e event) {
+	if e.Action == "fail" && !strings.Contains(e.Test, "/") {
+		s.tests[e.Package] = append(s.tests[e.Package], e.Test)
+	}
+}
+
+func (s *summarizer) handlePackage(e event) {
+	switch {
+	case e.Action == "pass" || e.Action == "skip":
+		s.summary.Passed++
+	case e.Action == "fail" && e.Package != "":
+		if !slices.ContainsFunc(s.summary.Failures, func(f Failure) bool { return f.Package == e.Package }) {
+			s.summary.Failures = append(s.summary.Failures, Failure{Package: e.Package, Tests: s.tests[e.Package]})
+		}
+	}
+}
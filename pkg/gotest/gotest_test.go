@@ -0,0 +1,95 @@
+package gotest
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{
+			name: "defaults",
+			opts: Options{},
+			want: []string{"test", "-json", "-coverprofile=c.out", "./..."},
+		},
+		{
+			name: "all options",
+			opts: Options{
+				Packages: []string{"./pkg/...", "./cmd/..."},
+				Tags:     []string{"integration", "e2e"},
+				Race:     true,
+				Short:    true,
+				Timeout:  90 * time.Second,
+				CoverPkg: "./...",
+				Parallel: 2,
+				Env:      []string{"CI=1"},
+			},
+			want: []string{
+				"test", "-json", "-coverprofile=c.out", "-tags=integration,e2e", "-race", "-short",
+				"-timeout=1m30s", "-coverpkg=./...", "-p=2", "./pkg/...", "./cmd/...",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.opts.Args("c.out"))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, (&Options{Env: []string{"A=1", "B="}}).Validate())
+
+	for _, opts := range []Options{
+		{Timeout: -time.Second},
+		{Parallel: -1},
+		{Env: []string{"A"}},
+		{Env: []string{"=1"}},
+	} {
+		require.ErrorIs(t, opts.Validate(), ErrInvalidOptions)
+	}
+}
+
+func TestReadEvents(t *testing.T) {
+	events := strings.Join([]string{
+		`{"Action":"start","Package":"example.com/ok"}`,
+		`{"Action":"run","Package":"example.com/ok","Test":"TestA"}`,
+		`{"Action":"output","Package":"example.com/ok","Test":"TestA","Output":"=== RUN   TestA\n"}`,
+		`{"Action":"pass","Package":"example.com/ok","Test":"TestA"}`,
+		`{"Action":"pass","Package":"example.com/ok"}`,
+		`{"Action":"skip","Package":"example.com/notests"}`,
+		`{"Action":"fail","Package":"example.com/bad","Test":"TestB/sub"}`,
+		`{"Action":"fail","Package":"example.com/bad","Test":"TestB"}`,
+		`{"Action":"fail","Package":"example.com/bad","Test":"TestC"}`,
+		`{"Action":"fail","Package":"example.com/bad"}`,
+		`# example.com/broken [example.com/broken.test]`,
+		`{"Action":"fail","Package":"example.com/broken"}`,
+	}, "\n")
+
+	var output bytes.Buffer
+
+	summary, err := ReadEvents(strings.NewReader(events), &output)
+	require.NoError(t, err)
+
+	assert.Equal(t, Summary{Passed: 2, Failures: []Failure{
+		{Package: "example.com/bad", Tests: []string{"TestB", "TestC"}},
+		{Package: "example.com/broken"},
+	}}, summary)
+	assert.Equal(t, "=== RUN   TestA\n# example.com/broken [example.com/broken.test]\n", output.String())
+}
+
+func TestFailureString(t *testing.T) {
+	assert.Equal(t, "package example.com/bad failed: TestB, TestC",
+		Failure{Package: "example.com/bad", Tests: []string{"TestB", "TestC"}}.String())
+	assert.Equal(t, "package example.com/broken failed", Failure{Package: "example.com/broken"}.String())
+}
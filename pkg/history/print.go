@@ -0,0 +1,82 @@
+package history
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/bndr/gotabulate"
+)
+
+// formatCoverage returns missing value when coverage profile of a revision was not found.
+func formatCoverage(coverage *float64, format, missing string) string {
+	if coverage == nil {
+		return missing
+	}
+
+	return fmt.Sprintf(format, *coverage)
+}
+
+func PrintTabular(points []Point, out io.Writer) {
+	_, _ = io.WriteString(out, "\nComplexity history:\n")
+
+	// gotabulate can't render a table without rows
+	if len(points) == 0 {
+		_, _ = io.WriteString(out, "No commits in range\n")
+
+		return
+	}
+
+	data := make([][]any, len(points))
+	for i, point := range points {
+		data[i] = []any{
+			point.Revision.Date.Format(time.DateOnly),
+			point.Revision.Name(),
+			point.Level,
+			point.Name,
+			point.Files,
+			point.Functions,
+			fmt.Sprintf("%.2f", point.AvgComplexity),
+			point.MaxComplexity,
+			point.TotalComplexity,
+			formatCoverage(point.Coverage, "%.2f%%", "-"),
+		}
+	}
+
+	table := gotabulate.Create(data)
+	table.SetHeaders([]string{
+		"DATE", "REVISION", "LEVEL", "NAME", "FILES", "FUNCTIONS", "AVG COMPLEXITY", "MAX COMPLEXITY",
+		"TOTAL COMPLEXITY", "COVERAGE",
+	})
+	table.SetAlign("left")
+
+	_, _ = io.WriteString(out, table.Render("grid"))
+}
+
+func PrintCSV(points []Point, out io.Writer) {
+	writer := csv.NewWriter(out)
+	defer writer.Flush()
+
+	_ = writer.Write([]string{
+		"DATE", "REVISION", "COMMIT", "LEVEL", "NAME", "FILES", "FUNCTIONS", "AVG_COMPLEXITY", "MAX_COMPLEXITY",
+		"TOTAL_COMPLEXITY", "COVERAGE",
+	})
+
+	for _, point := range points {
+		_ = writer.Write([]string{
+			point.Revision.Date.Format(time.RFC3339),
+			point.Revision.Name(),
+			point.Revision.Hash,
+			point.Level,
+			point.Name,
+			strconv.Itoa(point.Files),
+			strconv.Itoa(point.Functions),
+			strconv.FormatFloat(point.AvgComplexity, 'f', 2, 64),
+			strconv.Itoa(point.MaxComplexity),
+			strconv.Itoa(point.TotalComplexity),
+			formatCoverage(point.Coverage, "%.2f", ""),
+		})
+	}
+}
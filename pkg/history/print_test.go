@@ -0,0 +1,60 @@
+package history
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vbvictor/grit/pkg/git"
+)
+
+var testPoints = []Point{
+	{
+		Revision: git.Revision{
+			Hash: "d4943f6ccc7403f3808b1d791dd086eee1b22036",
+			Date: time.Date(2024, 11, 23, 18, 22, 59, 0, time.UTC),
+		},
+		Level: Repo, Name: ".", Files: 3, Functions: 4, TotalComplexity: 12, MaxComplexity: 6, AvgComplexity: 3,
+	},
+	{
+		Revision: git.Revision{
+			Hash: "10546ac19424f2e1aa302eea45049405e79224cc",
+			Date: time.Date(2024, 12, 1, 10, 0, 0, 0, time.UTC),
+			Tag:  "v1.0.0",
+		},
+		Level: Package, Name: "pkg", Files: 2, Functions: 3, TotalComplexity: 9, MaxComplexity: 6, AvgComplexity: 3,
+		Coverage: ptr(75),
+	},
+}
+
+func TestPrintTabular(t *testing.T) {
+	var buf bytes.Buffer
+
+	PrintTabular(testPoints, &buf)
+
+	output := buf.String()
+	for _, expected := range []string{"2024-11-23", "d4943f6", "v1.0.0", "package", "3.00", "75.00%", "-"} {
+		assert.Contains(t, output, expected)
+	}
+}
+
+func TestPrintTabularEmpty(t *testing.T) {
+	var buf bytes.Buffer
+
+	PrintTabular(nil, &buf)
+
+	assert.Equal(t, "\nComplexity history:\nNo commits in range\n", buf.String())
+}
+
+func TestPrintCSV(t *testing.T) {
+	var buf bytes.Buffer
+
+	PrintCSV(testPoints, &buf)
+
+	header := "DATE,REVISION,COMMIT,LEVEL,NAME,FILES,FUNCTIONS,AVG_COMPLEXITY,MAX_COMPLEXITY,TOTAL_COMPLEXITY,COVERAGE\n"
+
+	assert.Equal(t, header+`2024-11-23T18:22:59Z,d4943f6,d4943f6ccc7403f3808b1d791dd086eee1b22036,repo,.,3,4,3.00,6,12,
+2024-12-01T10:00:00Z,v1.0.0,10546ac19424f2e1aa302eea45049405e79224cc,package,pkg,2,3,3.00,6,9,75.00
+`, buf.String())
+}
@@ -0,0 +1,226 @@
+// Package history samples repository at past revisions and aggregates metrics into a time series.
+package history
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/coverage"
+	"github.com/vbvictor/grit/pkg/git"
+	"github.com/vbvictor/grit/pkg/module"
+)
+
+// Level is granularity of aggregated metrics.
+type Level = string
+
+const (
+	Repo    Level = "repo"
+	Module  Level = "module"
+	Package Level = "package"
+	File    Level = "file"
+)
+
+var (
+	AvailableLevels = []Level{Repo, Module, Package, File}
+
+	ErrUnsupportedLevel = errors.New("unsupported history level")
+)
+
+const DefaultSamples = 10
+
+type Options struct {
+	Since time.Time
+	Until time.Time
+	// Samples is number of evenly spaced commits to analyze, ignored when Tags is set.
+	Samples int
+	// Tags makes history sample every tag instead of commits.
+	Tags   bool
+	Levels []Level
+	// ProfilesDir contains coverage profiles of sampled revisions named after their tag or commit hash.
+	ProfilesDir  string
+	OutputFormat string
+}
+
+// Point holds metrics aggregated over files of a repository, package or a single file at given revision.
+type Point struct {
+	Revision        git.Revision
+	Level           Level
+	Name            string
+	Files           int
+	Functions       int
+	TotalComplexity int
+	MaxComplexity   int
+	AvgComplexity   float64
+	// Coverage is set only when coverage profile of the revision was found.
+	Coverage *float64
+}
+
+func PopulateOpts(opts *Options, since, until string) error {
+	var err error
+
+	if since != "" {
+		if opts.Since, err = time.Parse(time.DateOnly, since); err != nil {
+			return fmt.Errorf("error parsing since date: %w", err)
+		}
+	} else {
+		opts.Since = time.Now().AddDate(-1, 0, 0)
+	}
+
+	if until != "" {
+		if opts.Until, err = time.Parse(time.DateOnly, until); err != nil {
+			return fmt.Errorf("error parsing until date: %w", err)
+		}
+	}
+
+	for _, level := range opts.Levels {
+		if !slices.Contains(AvailableLevels, level) {
+			return fmt.Errorf("%w: %s, expected one of %v", ErrUnsupportedLevel, level, AvailableLevels)
+		}
+	}
+
+	return nil
+}
+
+// Revisions returns revisions of path to sample according to opts.
+func Revisions(path string, opts *Options) ([]git.Revision, error) {
+	if opts.Tags {
+		return git.TagRevisions(path, opts.Since, opts.Until)
+	}
+
+	return git.SampleCommits(path, opts.Since, opts.Until, opts.Samples)
+}
+
+// FindProfile returns name of coverage profile stored for revision in dir,
+// profiles are named after tag or commit hash, abbreviated hashes are accepted.
+func FindProfile(dir string, revision git.Revision) (string, bool) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", false
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+
+		if (revision.Tag != "" && name == revision.Tag) ||
+			(len(name) >= 7 && strings.HasPrefix(revision.Hash, name)) { //nolint:mnd // shortest abbreviated hash
+			return entry.Name(), true
+		}
+	}
+
+	return "", false
+}
+
+type aggregate struct {
+	files      int
+	functions  int
+	total      int
+	max        int
+	statements int
+	covered    int
+}
+
+func (a *aggregate) addFile(file *complexity.FileStat) {
+	a.files++
+
+	for _, fn := range file.Functions {
+		a.functions++
+		a.total += fn.Complexity
+		a.max = max(a.max, fn.Complexity)
+	}
+}
+
+func (a *aggregate) addCoverage(cov *coverage.FileCoverage) {
+	a.statements += cov.Statements
+	a.covered += cov.Covered
+}
+
+// Aggregate summarizes complexity and coverage of files at revision on each of levels,
+// modules of the revision are used to group files on Module level.
+// Coverage is reported only if covData is not nil.
+func Aggregate(
+	revision git.Revision, files []*complexity.FileStat, covData []*coverage.FileCoverage, levels []Level,
+	modules module.Modules,
+) []Point {
+	points := make([]Point, 0)
+
+	for _, level := range levels {
+		groups := make(map[string]*aggregate)
+		group := func(path string) *aggregate {
+			name := groupName(path, level, modules)
+			if groups[name] == nil {
+				groups[name] = &aggregate{}
+			}
+
+			return groups[name]
+		}
+
+		for _, file := range files {
+			group(file.Path).addFile(file)
+		}
+
+		for _, cov := range covData {
+			group(cov.File).addCoverage(cov)
+		}
+
+		names := make([]string, 0, len(groups))
+		for name := range groups {
+			names = append(names, name)
+		}
+
+		slices.Sort(names)
+
+		for _, name := range names {
+			points = append(points, newPoint(revision, level, name, groups[name], covData != nil))
+		}
+	}
+
+	return points
+}
+
+func groupName(path string, level Level, modules module.Modules) string {
+	path = filepath.ToSlash(filepath.Clean(path))
+
+	switch level {
+	case Module:
+		return modules.Name(path)
+	case Package:
+		return filepath.ToSlash(filepath.Dir(path))
+	case File:
+		return path
+	default:
+		return "."
+	}
+}
+
+func newPoint(revision git.Revision, level Level, name string, agg *aggregate, withCoverage bool) Point {
+	point := Point{
+		Revision:        revision,
+		Level:           level,
+		Name:            name,
+		Files:           agg.files,
+		Functions:       agg.functions,
+		TotalComplexity: agg.total,
+		MaxComplexity:   agg.max,
+	}
+
+	if agg.functions > 0 {
+		point.AvgComplexity = float64(agg.total) / float64(agg.functions)
+	}
+
+	if withCoverage && agg.statements > 0 {
+		covered := float64(agg.covered) * 100 / float64(agg.statements) //nolint:mnd // percent
+		point.Coverage = &covered
+	}
+
+	return point
+}
@@ -0,0 +1,137 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/coverage"
+	"github.com/vbvictor/grit/pkg/git"
+	"github.com/vbvictor/grit/pkg/module"
+)
+
+func ptr(value float64) *float64 {
+	return &value
+}
+
+func TestAggregate(t *testing.T) {
+	revision := git.Revision{Hash: "d4943f6ccc7403f3808b1d791dd086eee1b22036"}
+	files := []*complexity.FileStat{
+		{
+			Path: "pkg/a.go",
+			Functions: []complexity.FunctionStat{
+				{Name: "A1", Complexity: 2},
+				{Name: "A2", Complexity: 6},
+			},
+		},
+		{
+			Path:      "pkg/b.go",
+			Functions: []complexity.FunctionStat{{Name: "B", Complexity: 1}},
+		},
+		{
+			Path:      "main.go",
+			Functions: []complexity.FunctionStat{{Name: "main", Complexity: 3}},
+		},
+	}
+	covData := []*coverage.FileCoverage{
+		{File: "pkg/a.go", Statements: 10, Covered: 5},
+		{File: "pkg/b.go", Statements: 10, Covered: 10},
+	}
+
+	points := Aggregate(revision, files, covData, []Level{Repo, Package, File}, nil)
+
+	assert.Equal(t, []Point{
+		{
+			Revision: revision, Level: Repo, Name: ".", Files: 3, Functions: 4,
+			TotalComplexity: 12, MaxComplexity: 6, AvgComplexity: 3, Coverage: ptr(75),
+		},
+		{
+			Revision: revision, Level: Package, Name: ".", Files: 1, Functions: 1,
+			TotalComplexity: 3, MaxComplexity: 3, AvgComplexity: 3,
+		},
+		{
+			Revision: revision, Level: Package, Name: "pkg", Files: 2, Functions: 3,
+			TotalComplexity: 9, MaxComplexity: 6, AvgComplexity: 3, Coverage: ptr(75),
+		},
+		{
+			Revision: revision, Level: File, Name: "main.go", Files: 1, Functions: 1,
+			TotalComplexity: 3, MaxComplexity: 3, AvgComplexity: 3,
+		},
+		{
+			Revision: revision, Level: File, Name: "pkg/a.go", Files: 1, Functions: 2,
+			TotalComplexity: 8, MaxComplexity: 6, AvgComplexity: 4, Coverage: ptr(50),
+		},
+		{
+			Revision: revision, Level: File, Name: "pkg/b.go", Files: 1, Functions: 1,
+			TotalComplexity: 1, MaxComplexity: 1, AvgComplexity: 1, Coverage: ptr(100),
+		},
+	}, points)
+
+	for _, point := range Aggregate(revision, files, nil, []Level{Repo}, nil) {
+		assert.Nil(t, point.Coverage)
+	}
+}
+
+func TestAggregateModules(t *testing.T) {
+	revision := git.Revision{Hash: "d4943f6ccc7403f3808b1d791dd086eee1b22036"}
+	modules := module.Modules{{Path: "example.com/root", Dir: "."}, {Path: "example.com/root/tools", Dir: "tools"}}
+	files := []*complexity.FileStat{
+		{Path: "main.go", Functions: []complexity.FunctionStat{{Name: "main", Complexity: 3}}},
+		{Path: "tools/gen.go", Functions: []complexity.FunctionStat{{Name: "gen", Complexity: 5}}},
+		{Path: "tools/cmd/run.go", Functions: []complexity.FunctionStat{{Name: "run", Complexity: 1}}},
+	}
+
+	points := Aggregate(revision, files, nil, []Level{Module}, modules)
+
+	assert.Equal(t, []Point{
+		{
+			Revision: revision, Level: Module, Name: "example.com/root", Files: 1, Functions: 1,
+			TotalComplexity: 3, MaxComplexity: 3, AvgComplexity: 3,
+		},
+		{
+			Revision: revision, Level: Module, Name: "example.com/root/tools", Files: 2, Functions: 2,
+			TotalComplexity: 6, MaxComplexity: 5, AvgComplexity: 3,
+		},
+	}, points)
+}
+
+func TestFindProfile(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"v1.0.0.out", "d4943f6.out", "abc.out"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o600))
+	}
+
+	profile, ok := FindProfile(dir, git.Revision{Hash: "0000000000", Tag: "v1.0.0"})
+	assert.True(t, ok)
+	assert.Equal(t, "v1.0.0.out", profile)
+
+	profile, ok = FindProfile(dir, git.Revision{Hash: "d4943f6ccc7403f3808b1d791dd086eee1b22036"})
+	assert.True(t, ok)
+	assert.Equal(t, "d4943f6.out", profile)
+
+	// Too short prefixes are not considered abbreviated hashes.
+	_, ok = FindProfile(dir, git.Revision{Hash: "abcdef0123456789"})
+	assert.False(t, ok)
+
+	_, ok = FindProfile(filepath.Join(dir, "missing"), git.Revision{Tag: "v1.0.0"})
+	assert.False(t, ok)
+}
+
+func TestPopulateOpts(t *testing.T) {
+	opts := Options{Levels: []Level{Repo, File}}
+	require.NoError(t, PopulateOpts(&opts, "2024-01-01", "2024-06-01"))
+	assert.Equal(t, "2024-01-01", opts.Since.Format(time.DateOnly))
+	assert.Equal(t, "2024-06-01", opts.Until.Format(time.DateOnly))
+
+	opts = Options{}
+	require.NoError(t, PopulateOpts(&opts, "", ""))
+	assert.False(t, opts.Since.IsZero())
+	assert.True(t, opts.Until.IsZero())
+
+	require.ErrorIs(t, PopulateOpts(&Options{Levels: []Level{"function"}}, "", ""), ErrUnsupportedLevel)
+	require.Error(t, PopulateOpts(&Options{}, "yesterday", ""))
+}
@@ -0,0 +1,229 @@
+// Package module discovers Go modules of a repository and maps files and import paths to them.
+package module
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+const (
+	GoMod  = "go.mod"
+	GoWork = "go.work"
+
+	// NoModule groups files that don't belong to any module.
+	NoModule = "(no module)"
+)
+
+// Module is a Go module located in a repository.
+type Module struct {
+	// Path is module path declared in go.mod.
+	Path string
+	// Dir is module root directory relative to the repository, '.' for the root module.
+	Dir string
+}
+
+// Modules are modules of a repository sorted by directory.
+type Modules []Module
+
+// Discover finds modules of repository at root. Modules listed in go.work 'use' directives are returned
+// when root contains go.work, otherwise every go.mod in the tree is a module. Directories ignored by go tool,
+// vendor, testdata and the ones starting with '.' or '_', are not searched.
+func Discover(root string) (Modules, error) {
+	dirs, err := workspaceDirs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if dirs == nil {
+		if dirs, err = goModDirs(root); err != nil {
+			return nil, err
+		}
+	}
+
+	modules := make(Modules, 0, len(dirs))
+
+	for _, dir := range dirs {
+		data, err := os.ReadFile(filepath.Join(root, dir, GoMod))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read module at %s: %w", dir, err)
+		}
+
+		modulePath := modfile.ModulePath(data)
+		if modulePath == "" {
+			return nil, fmt.Errorf("failed to read module at %s: module path is not declared", dir)
+		}
+
+		modules = append(modules, Module{Path: modulePath, Dir: filepath.ToSlash(filepath.Clean(dir))})
+	}
+
+	slices.SortFunc(modules, func(a, b Module) int {
+		return strings.Compare(a.Dir, b.Dir)
+	})
+
+	return modules, nil
+}
+
+// workspaceDirs returns directories of modules used by go.work at root, nil if there is no go.work.
+func workspaceDirs(root string) ([]string, error) {
+	workPath := filepath.Join(root, GoWork)
+
+	data, err := os.ReadFile(workPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", workPath, err)
+	}
+
+	work, err := modfile.ParseWork(workPath, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", workPath, err)
+	}
+
+	dirs := make([]string, 0, len(work.Use))
+	for _, use := range work.Use {
+		dirs = append(dirs, filepath.FromSlash(use.Path))
+	}
+
+	return dirs, nil
+}
+
+func goModDirs(root string) ([]string, error) {
+	dirs := make([]string, 0)
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to walk path: %w", err)
+		}
+
+		if entry.IsDir() {
+			if path != root && IsIgnoredDir(entry.Name()) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if entry.Name() == GoMod {
+			dir, err := filepath.Rel(root, filepath.Dir(path))
+			if err != nil {
+				return fmt.Errorf("failed to get relative path: %w", err)
+			}
+
+			dirs = append(dirs, dir)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover modules: %w", err)
+	}
+
+	return dirs, nil
+}
+
+// IsIgnoredDir reports whether go tool ignores packages of directory with name.
+func IsIgnoredDir(name string) bool {
+	return name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+// Owner returns the innermost module containing file at repository relative path.
+func (m Modules) Owner(file string) (Module, bool) {
+	file = filepath.ToSlash(filepath.Clean(file))
+
+	var owner Module
+
+	depth := -1
+
+	for _, module := range m {
+		switch {
+		case module.Dir == "." && depth < 0:
+			owner, depth = module, 0
+		case (file == module.Dir || strings.HasPrefix(file, module.Dir+"/")) && len(module.Dir) > depth:
+			owner, depth = module, len(module.Dir)
+		}
+	}
+
+	return owner, depth >= 0
+}
+
+// Name returns path of the module containing file or NoModule.
+func (m Modules) Name(file string) string {
+	if owner, ok := m.Owner(file); ok {
+		return owner.Path
+	}
+
+	return NoModule
+}
+
+// Resolve maps file in form 'import/path/file.go', as written to coverage profiles,
+// to its repository relative path using the module with the longest matching path.
+func (m Modules) Resolve(importPath string) (string, bool) {
+	var owner Module
+
+	found := false
+
+	for _, module := range m {
+		if importPath == module.Path || strings.HasPrefix(importPath, module.Path+"/") {
+			if !found || len(module.Path) > len(owner.Path) {
+				owner = module
+				found = true
+			}
+		}
+	}
+
+	if !found {
+		return "", false
+	}
+
+	rel := strings.TrimPrefix(strings.TrimPrefix(importPath, owner.Path), "/")
+
+	return filepath.FromSlash(path.Join(owner.Dir, rel)), true
+}
+
+// ImportPath maps repository relative path of file to the form written to coverage profiles,
+// 'import/path/file.go', it is the inverse of Resolve.
+func (m Modules) ImportPath(file string) (string, bool) {
+	owner, ok := m.Owner(file)
+	if !ok {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(filepath.FromSlash(owner.Dir), file)
+	if err != nil {
+		return "", false
+	}
+
+	return path.Join(owner.Path, filepath.ToSlash(rel)), true
+}
+
+// Group collects items by name of the module containing their file, names are returned sorted.
+func Group[T any](modules Modules, items []T, file func(T) string) (map[string][]T, []string) {
+	return GroupBy(items, func(item T) string { return modules.Name(file(item)) })
+}
+
+// GroupBy collects items by their group name, names are returned sorted.
+func GroupBy[T any](items []T, name func(T) string) (map[string][]T, []string) {
+	groups := make(map[string][]T)
+
+	for _, item := range items {
+		groupName := name(item)
+		groups[groupName] = append(groups[groupName], item)
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return groups, names
+}
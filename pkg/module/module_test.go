@@ -0,0 +1,158 @@
+package module
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vbvictor/grit/internal/testutil"
+)
+
+func TestDiscoverNestedModules(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteFile(t, filepath.Join(root, GoMod), "module example.com/root\n")
+	testutil.WriteFile(t, filepath.Join(root, "tools", GoMod), "module example.com/root/tools\n")
+	testutil.WriteFile(t, filepath.Join(root, "api", "v2", GoMod), "module example.com/api/v2\n")
+	testutil.WriteFile(t, filepath.Join(root, "testdata", "fixture", GoMod), "module example.com/fixture\n")
+	testutil.WriteFile(t, filepath.Join(root, "vendor", "dep", GoMod), "module example.com/dep\n")
+	testutil.WriteFile(t, filepath.Join(root, ".cache", GoMod), "module example.com/cache\n")
+
+	modules, err := Discover(root)
+	require.NoError(t, err)
+
+	assert.Equal(t, Modules{
+		{Path: "example.com/root", Dir: "."},
+		{Path: "example.com/api/v2", Dir: "api/v2"},
+		{Path: "example.com/root/tools", Dir: "tools"},
+	}, modules)
+}
+
+func TestDiscoverWorkspace(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteFile(t, filepath.Join(root, GoWork), "go 1.23\n\nuse (\n\t./app\n\t./lib\n)\n")
+	testutil.WriteFile(t, filepath.Join(root, "app", GoMod), "module example.com/app\n")
+	testutil.WriteFile(t, filepath.Join(root, "lib", GoMod), "module example.com/lib\n")
+	testutil.WriteFile(t, filepath.Join(root, "unused", GoMod), "module example.com/unused\n")
+
+	modules, err := Discover(root)
+	require.NoError(t, err)
+
+	assert.Equal(t, Modules{
+		{Path: "example.com/app", Dir: "app"},
+		{Path: "example.com/lib", Dir: "lib"},
+	}, modules)
+}
+
+func TestDiscoverErrors(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteFile(t, filepath.Join(root, GoWork), "go 1.23\n\nuse ./missing\n")
+
+	_, err := Discover(root)
+	require.Error(t, err)
+
+	root = t.TempDir()
+	testutil.WriteFile(t, filepath.Join(root, GoMod), "go 1.23\n")
+
+	_, err = Discover(root)
+	require.Error(t, err)
+}
+
+func TestDiscoverWithoutModules(t *testing.T) {
+	modules, err := Discover(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, modules)
+}
+
+func TestOwner(t *testing.T) {
+	modules := Modules{
+		{Path: "example.com/root", Dir: "."},
+		{Path: "example.com/root/tools", Dir: "tools"},
+		{Path: "example.com/root/tools/gen", Dir: "tools/gen"},
+	}
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"main.go", "example.com/root"},
+		{"pkg/a.go", "example.com/root"},
+		{"tools/main.go", "example.com/root/tools"},
+		{"tools/gen/gen.go", "example.com/root/tools/gen"},
+		{"toolsx/a.go", "example.com/root"},
+		{filepath.Join("tools", "gen", "sub", "b.go"), "example.com/root/tools/gen"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			assert.Equal(t, tt.want, modules.Name(tt.file))
+		})
+	}
+
+	nested := Modules{{Path: "example.com/lib", Dir: "lib"}}
+	_, ok := nested.Owner("main.go")
+	assert.False(t, ok)
+	assert.Equal(t, NoModule, nested.Name("main.go"))
+}
+
+func TestResolve(t *testing.T) {
+	modules := Modules{
+		{Path: "example.com/root", Dir: "."},
+		{Path: "example.com/root/tools", Dir: "tools"},
+		{Path: "example.com/api/v2", Dir: "api/v2"},
+	}
+
+	tests := []struct {
+		importPath string
+		want       string
+		found      bool
+	}{
+		{"example.com/root/main.go", "main.go", true},
+		{"example.com/root/pkg/a.go", filepath.Join("pkg", "a.go"), true},
+		{"example.com/root/tools/gen.go", filepath.Join("tools", "gen.go"), true},
+		{"example.com/api/v2/client.go", filepath.Join("api", "v2", "client.go"), true},
+		{"example.com/rootx/a.go", "", false},
+		{"github.com/other/repo/a.go", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.importPath, func(t *testing.T) {
+			got, found := modules.Resolve(tt.importPath)
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestImportPath(t *testing.T) {
+	modules := Modules{
+		{Path: "example.com/root", Dir: "."},
+		{Path: "example.com/api/v2", Dir: "api/v2"},
+	}
+
+	for _, file := range []string{"main.go", filepath.Join("pkg", "a.go"), filepath.Join("api", "v2", "client.go")} {
+		importPath, ok := modules.ImportPath(file)
+		require.True(t, ok)
+
+		resolved, ok := modules.Resolve(importPath)
+		require.True(t, ok)
+		assert.Equal(t, file, resolved)
+	}
+
+	importPath, _ := modules.ImportPath(filepath.Join("api", "v2", "client.go"))
+	assert.Equal(t, "example.com/api/v2/client.go", importPath)
+
+	_, ok := Modules{{Path: "example.com/lib", Dir: "lib"}}.ImportPath("main.go")
+	assert.False(t, ok)
+}
+
+func TestGroup(t *testing.T) {
+	modules := Modules{{Path: "example.com/root", Dir: "."}, {Path: "example.com/root/tools", Dir: "tools"}}
+	files := []string{"tools/a.go", "main.go", "pkg/b.go"}
+
+	groups, names := Group(modules, files, func(file string) string { return file })
+
+	assert.Equal(t, []string{"example.com/root", "example.com/root/tools"}, names)
+	assert.Equal(t, []string{"main.go", "pkg/b.go"}, groups["example.com/root"])
+	assert.Equal(t, []string{"tools/a.go"}, groups["example.com/root/tools"])
+}
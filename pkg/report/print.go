@@ -4,10 +4,52 @@ import (
 	"encoding/csv"
 	"fmt"
 	"io"
+	"strconv"
 
 	"github.com/bndr/gotabulate"
 )
 
+// optionalHeaders returns headers of columns shown only when enabled by opts.
+func optionalHeaders(opts *Options) []string {
+	headers := make([]string, 0)
+	if opts.DuplicationFactor != 0 {
+		headers = append(headers, "DUPLICATION")
+	}
+
+	if opts.DocsFactor != 0 {
+		headers = append(headers, "DOCS")
+	}
+
+	if opts.ByPackage {
+		headers = append(headers, "AFFERENT", "EFFERENT", "INSTABILITY", "DISTANCE")
+	}
+
+	return headers
+}
+
+// optionalValues returns values of columns listed by optionalHeaders, percentSign is appended to percentages.
+func optionalValues(result *FileScore, opts *Options, percentSign string) []string {
+	values := make([]string, 0)
+	if opts.DuplicationFactor != 0 {
+		values = append(values, fmt.Sprintf("%.2f%s", result.Duplication, percentSign))
+	}
+
+	if opts.DocsFactor != 0 {
+		values = append(values, fmt.Sprintf("%.2f%s", result.DocCoverage, percentSign))
+	}
+
+	if opts.ByPackage {
+		values = append(values,
+			strconv.Itoa(result.Afferent),
+			strconv.Itoa(result.Efferent),
+			fmt.Sprintf("%.2f", result.Instability),
+			fmt.Sprintf("%.2f", result.Distance),
+		)
+	}
+
+	return values
+}
+
 func PrintTabular(results []*FileScore, out io.Writer, opts *Options) {
 	fmt.Fprintf(out, "\nCode health analysis results (top %d):\n", opts.Top)
 
@@ -20,10 +62,14 @@ func PrintTabular(results []*FileScore, out io.Writer, opts *Options) {
 			fmt.Sprintf("%.2f", result.Complexity),
 			fmt.Sprintf("%.2f%%", result.Coverage),
 		}
+
+		for _, value := range optionalValues(result, opts, "%") {
+			data[i] = append(data[i], value)
+		}
 	}
 
 	table := gotabulate.Create(data)
-	table.SetHeaders([]string{"FILEPATH", "SCORE", "CHURN", "COMPLEXITY", "COVERAGE"})
+	table.SetHeaders(append([]string{"FILEPATH", "SCORE", "CHURN", "COMPLEXITY", "COVERAGE"}, optionalHeaders(opts)...))
 	table.SetAlign("left")
 
 	if _, err := io.WriteString(out, table.Render("grid")); err != nil {
@@ -31,12 +77,13 @@ func PrintTabular(results []*FileScore, out io.Writer, opts *Options) {
 	}
 }
 
-func PrintCSV(results []*FileScore, out io.Writer, _ *Options) {
+func PrintCSV(results []*FileScore, out io.Writer, opts *Options) {
 	writer := csv.NewWriter(out)
 	defer writer.Flush()
 
 	// Write headers
-	if err := writer.Write([]string{"FILEPATH", "SCORE", "CHURN", "COMPLEXITY", "COVERAGE"}); err != nil {
+	headers := append([]string{"FILEPATH", "SCORE", "CHURN", "COMPLEXITY", "COVERAGE"}, optionalHeaders(opts)...)
+	if err := writer.Write(headers); err != nil {
 		return
 	}
 
@@ -49,8 +96,57 @@ func PrintCSV(results []*FileScore, out io.Writer, _ *Options) {
 			fmt.Sprintf("%.2f", result.Complexity),
 			fmt.Sprintf("%.2f", result.Coverage),
 		}
+
+		record = append(record, optionalValues(result, opts, "")...)
+
 		if err := writer.Write(record); err != nil {
 			return
 		}
 	}
 }
+
+func PrintTestsTabular(results []*TestComparison, out io.Writer) {
+	fmt.Fprintf(out, "\nProduction code vs tests comparison:\n")
+
+	data := make([][]any, len(results))
+	for i, result := range results {
+		data[i] = []any{
+			result.File,
+			result.TestFile,
+			fmt.Sprintf("%.2f", result.Complexity),
+			fmt.Sprintf("%.2f", result.TestComplexity),
+			fmt.Sprintf("%.2f", result.ComplexityRatio),
+			fmt.Sprintf("%.2f", result.Churn),
+			fmt.Sprintf("%.2f", result.TestChurn),
+		}
+	}
+
+	table := gotabulate.Create(data)
+	table.SetHeaders([]string{
+		"FILEPATH", "TEST FILEPATH", "COMPLEXITY", "TEST COMPLEXITY", "RATIO", "CHURN", "TEST CHURN",
+	})
+	table.SetAlign("left")
+
+	_, _ = io.WriteString(out, table.Render("grid"))
+}
+
+func PrintTestsCSV(results []*TestComparison, out io.Writer) {
+	writer := csv.NewWriter(out)
+	defer writer.Flush()
+
+	_ = writer.Write([]string{
+		"FILEPATH", "TEST_FILEPATH", "COMPLEXITY", "TEST_COMPLEXITY", "RATIO", "CHURN", "TEST_CHURN",
+	})
+
+	for _, result := range results {
+		_ = writer.Write([]string{
+			result.File,
+			result.TestFile,
+			fmt.Sprintf("%.2f", result.Complexity),
+			fmt.Sprintf("%.2f", result.TestComplexity),
+			fmt.Sprintf("%.2f", result.ComplexityRatio),
+			fmt.Sprintf("%.2f", result.Churn),
+			fmt.Sprintf("%.2f", result.TestChurn),
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -82,6 +83,30 @@ func TestPrintTabular(t *testing.T) {
 	}
 }
 
+func TestPrintCSVOptionalColumns(t *testing.T) {
+	input := []*FileScore{
+		{
+			File: "pkg/store", Coverage: 50, Complexity: 2, Churn: 10, Score: 1200, Duplication: 20,
+			Afferent: 3, Efferent: 1, Instability: 0.25, Distance: 0.75,
+		},
+	}
+
+	var buf bytes.Buffer
+
+	PrintCSV(input, &buf, &Options{DuplicationFactor: 1, ByPackage: true})
+
+	assert.Equal(t,
+		"FILEPATH,SCORE,CHURN,COMPLEXITY,COVERAGE,DUPLICATION,AFFERENT,EFFERENT,INSTABILITY,DISTANCE\n"+
+			"pkg/store,1200.00,10.00,2.00,50.00,20.00,3,1,0.25,0.75\n",
+		buf.String())
+
+	buf.Reset()
+	PrintTabular(input, &buf, &Options{ByPackage: true})
+
+	assert.Contains(t, buf.String(), "INSTABILITY")
+	assert.NotContains(t, buf.String(), "DUPLICATION")
+}
+
 func TestPrintCSV(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -147,3 +172,34 @@ func TestPrintCSV(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintTests(t *testing.T) {
+	results := []*TestComparison{
+		{
+			File:            "pkg/run.go",
+			TestFile:        "pkg/run_test.go",
+			Churn:           10,
+			TestChurn:       25,
+			Complexity:      4,
+			TestComplexity:  8,
+			ComplexityRatio: 2,
+		},
+	}
+
+	var tabular bytes.Buffer
+
+	PrintTestsTabular(results, &tabular)
+
+	for _, exp := range []string{"pkg/run.go", "pkg/run_test.go", "8.00", "2.00", "25.00"} {
+		require.Contains(t, tabular.String(), exp)
+	}
+
+	var csvOut bytes.Buffer
+
+	PrintTestsCSV(results, &csvOut)
+
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	require.Len(t, lines, 2)
+	require.Equal(t, "FILEPATH,TEST_FILEPATH,COMPLEXITY,TEST_COMPLEXITY,RATIO,CHURN,TEST_CHURN", lines[0])
+	require.Equal(t, "pkg/run.go,pkg/run_test.go,4.00,8.00,2.00,10.00,25.00", lines[1])
+}
@@ -5,18 +5,32 @@ import (
 	"slices"
 
 	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/coupling"
 	"github.com/vbvictor/grit/pkg/coverage"
+	"github.com/vbvictor/grit/pkg/docs"
+	"github.com/vbvictor/grit/pkg/duplication"
 	"github.com/vbvictor/grit/pkg/git"
 	"golang.org/x/exp/maps"
 )
 
+const percentMultiplier = 100.0
+
 type FileScore struct {
 	File            string
 	Coverage        float64
 	Churn           float64
 	Complexity      float64
 	ChurnComplexity float64
-	Score           float64
+	// Duplication is percentage of duplicated lines, it affects score only when DuplicationFactor is set.
+	Duplication float64
+	// DocCoverage is percentage of documented exported identifiers, it affects score only when DocsFactor is set.
+	DocCoverage float64
+	// Coupling metrics are set only for packages, see AddCoupling.
+	Afferent    int
+	Efferent    int
+	Instability float64
+	Distance    float64
+	Score       float64
 }
 
 type Options struct {
@@ -24,13 +38,22 @@ type Options struct {
 	ComplexityFactor float64
 	CoverageFactor   float64
 	PerfectCoverage  float64
-	Top              int
-	ExcludePath      string
+	// DuplicationFactor scales score of files with duplicated code, duplication is ignored when it is 0.
+	DuplicationFactor float64
+	// DocsFactor scales score of files with undocumented exported identifiers, documentation is ignored when it is 0.
+	DocsFactor float64
+	// ByPackage reports package directories with their coupling metrics instead of files.
+	ByPackage   bool
+	Top         int
+	ExcludePath string
 }
 
 func CalculateScores(data []*FileScore, opts Options) []*FileScore {
 	for _, file := range data {
 		calculateScore(file, opts.PerfectCoverage)
+
+		file.Score *= 1 + opts.DuplicationFactor*file.Duplication/percentMultiplier
+		file.Score *= 1 + opts.DocsFactor*(percentMultiplier-file.DocCoverage)/percentMultiplier
 	}
 
 	return data
@@ -115,6 +138,57 @@ func CombineMetrics(
 	return maps.Values(fileMap)
 }
 
+// AddDuplication sets duplicated lines percentage of files present in scores.
+func AddDuplication(scores []*FileScore, duplicationData []*duplication.FileDuplication) []*FileScore {
+	percentages := make(map[string]float64, len(duplicationData))
+	for _, file := range duplicationData {
+		percentages[normalizePath(file.File)] = file.Percentage
+	}
+
+	for _, score := range scores {
+		score.Duplication = percentages[score.File]
+	}
+
+	return scores
+}
+
+// AddDocs sets documentation coverage of files present in scores,
+// files without documentation data, e.g. tests, are considered fully documented.
+func AddDocs(scores []*FileScore, docsData []*docs.FileDocs) []*FileScore {
+	coverage := make(map[string]float64, len(docsData))
+	for _, file := range docsData {
+		coverage[normalizePath(file.File)] = file.Coverage
+	}
+
+	for _, score := range scores {
+		score.DocCoverage = percentMultiplier
+		if fileCoverage, ok := coverage[score.File]; ok {
+			score.DocCoverage = fileCoverage
+		}
+	}
+
+	return scores
+}
+
+// AddCoupling sets coupling metrics of packages to scores of their directories.
+func AddCoupling(scores []*FileScore, packages []*coupling.Package) []*FileScore {
+	byDir := make(map[string]*coupling.Package, len(packages))
+	for _, pkg := range packages {
+		byDir[normalizePath(pkg.Dir)] = pkg
+	}
+
+	for _, score := range scores {
+		if pkg, ok := byDir[score.File]; ok {
+			score.Afferent = pkg.Afferent
+			score.Efferent = pkg.Efferent
+			score.Instability = pkg.Instability
+			score.Distance = pkg.Distance
+		}
+	}
+
+	return scores
+}
+
 func normalizePath(path string) string {
 	return filepath.Clean(path)
 }
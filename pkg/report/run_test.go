@@ -6,7 +6,10 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/coupling"
 	"github.com/vbvictor/grit/pkg/coverage"
+	"github.com/vbvictor/grit/pkg/docs"
+	"github.com/vbvictor/grit/pkg/duplication"
 	"github.com/vbvictor/grit/pkg/git"
 )
 
@@ -233,6 +236,71 @@ func TestCombineMetrics(t *testing.T) {
 	assert.ElementsMatch(t, result, expected)
 }
 
+func TestAddDuplication(t *testing.T) {
+	scores := []*FileScore{
+		{File: "file1.go", Churn: 10, Complexity: 2, Coverage: 100},
+		{File: filepath.Join("path", "file2.go"), Churn: 10, Complexity: 2, Coverage: 100},
+	}
+	duplicationData := []*duplication.FileDuplication{
+		{File: filepath.Join(".", "path", "file2.go"), Percentage: 50},
+		{File: "file3.go", Percentage: 100},
+	}
+
+	result := CalculateScores(AddDuplication(scores, duplicationData), Options{
+		PerfectCoverage:   100,
+		DuplicationFactor: 2,
+	})
+
+	assert.InDelta(t, 0.0, result[0].Duplication, 0.0001)
+	assert.InDelta(t, 20.0, result[0].Score, 0.0001)
+	assert.InDelta(t, 50.0, result[1].Duplication, 0.0001)
+	// Score is multiplied by 1 + factor * duplication / 100.
+	assert.InDelta(t, 40.0, result[1].Score, 0.0001)
+
+	// Duplication is ignored without factor.
+	result = CalculateScores(result, Options{PerfectCoverage: 100})
+	assert.InDelta(t, 20.0, result[1].Score, 0.0001)
+}
+
+func TestAddDocs(t *testing.T) {
+	scores := []*FileScore{
+		{File: "file1.go", Churn: 10, Complexity: 2, Coverage: 100},
+		{File: "file1_test.go", Churn: 10, Complexity: 2, Coverage: 100},
+	}
+
+	result := CalculateScores(AddDocs(scores, []*docs.FileDocs{{File: "file1.go", Coverage: 25}}), Options{
+		PerfectCoverage: 100,
+		DocsFactor:      2,
+	})
+
+	assert.InDelta(t, 25.0, result[0].DocCoverage, 0.0001)
+	// Score is multiplied by 1 + factor * (100 - documentation coverage) / 100.
+	assert.InDelta(t, 50.0, result[0].Score, 0.0001)
+	// Files without documentation data are not penalized.
+	assert.InDelta(t, 100.0, result[1].DocCoverage, 0.0001)
+	assert.InDelta(t, 20.0, result[1].Score, 0.0001)
+}
+
+func TestAddCoupling(t *testing.T) {
+	scores := []*FileScore{
+		{File: "."},
+		{File: filepath.Join("pkg", "store")},
+		{File: filepath.Join("pkg", "missing")},
+	}
+	packages := []*coupling.Package{
+		{Path: "example.com/app", Dir: ".", Efferent: 2, Instability: 1},
+		{Path: "example.com/app/pkg/store", Dir: filepath.Join("pkg", "store"), Afferent: 3, Distance: 0.5},
+	}
+
+	result := AddCoupling(scores, packages)
+
+	assert.Equal(t, []*FileScore{
+		{File: ".", Efferent: 2, Instability: 1},
+		{File: filepath.Join("pkg", "store"), Afferent: 3, Distance: 0.5},
+		{File: filepath.Join("pkg", "missing")},
+	}, result)
+}
+
 func TestCalculateScore(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -0,0 +1,54 @@
+package report
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/vbvictor/grit/pkg/sarif"
+)
+
+// ScoreRuleID identifies hotspot files in SARIF output.
+const ScoreRuleID = "maintainability-score"
+
+// DefaultSARIFLevels are maintainability scores starting from which hotspots become warnings and errors.
+var DefaultSARIFLevels = sarif.Levels{Warning: 1000, Error: 5000} //nolint:mnd // score bands
+
+var scoreRule = sarif.Rule{
+	ID:               ScoreRuleID,
+	Name:             "MaintainabilityScore",
+	ShortDescription: sarif.Message{Text: "File is a maintainability hotspot"},
+	FullDescription: &sarif.Message{
+		Text: "Maintainability score combines churn, complexity and missing coverage of a file, " +
+			"files with high score are the best candidates for refactoring and testing",
+	},
+}
+
+// PrintSARIF reports every hotspot file as a SARIF result with level mapped from its score.
+func PrintSARIF(results []*FileScore, out io.Writer, root string, levels sarif.Levels) error {
+	sarifResults := make([]sarif.Result, 0, len(results))
+
+	for _, result := range results {
+		sarifResults = append(sarifResults, sarif.Result{
+			RuleID: ScoreRuleID,
+			Level:  levels.Level(result.Score),
+			Message: sarif.Message{
+				Text: fmt.Sprintf("File has maintainability score %.2f: churn %.2f, complexity %.2f, coverage %.2f%%",
+					result.Score, result.Churn, result.Complexity, result.Coverage),
+			},
+			Locations: []sarif.Location{sarif.NewLocation(result.File, 0, 0)},
+			Properties: map[string]float64{
+				"score":      result.Score,
+				"churn":      result.Churn,
+				"complexity": result.Complexity,
+				"coverage":   result.Coverage,
+			},
+		})
+	}
+
+	log, err := sarif.NewLog(root, []sarif.Rule{scoreRule}, sarifResults)
+	if err != nil {
+		return err
+	}
+
+	return sarif.Write(log, out)
+}
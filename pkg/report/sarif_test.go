@@ -0,0 +1,32 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vbvictor/grit/pkg/sarif"
+)
+
+func TestPrintSARIF(t *testing.T) {
+	var buf bytes.Buffer
+
+	results := []*FileScore{
+		{File: "hot.go", Churn: 10, Complexity: 5, Coverage: 20, Score: 4000},
+		{File: "warm.go", Churn: 4, Complexity: 3, Coverage: 50, Score: 600},
+		{File: "cold.go", Churn: 1, Complexity: 1, Coverage: 90, Score: 10},
+	}
+
+	require.NoError(t, PrintSARIF(results, &buf, ".", sarif.Levels{Warning: 500, Error: 1000}))
+
+	output := buf.String()
+	assert.Contains(t, output, `"id": "maintainability-score"`)
+	assert.Contains(t, output,
+		`"text": "File has maintainability score 4000.00: churn 10.00, complexity 5.00, coverage 20.00%"`)
+	assert.Equal(t, 3, bytes.Count(buf.Bytes(), []byte(`"ruleId": "maintainability-score"`)))
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte(`"level": "error"`)))
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte(`"level": "warning"`)))
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte(`"level": "note"`)))
+	assert.NotContains(t, output, "region")
+}
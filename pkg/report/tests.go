@@ -0,0 +1,108 @@
+package report
+
+import (
+	"slices"
+
+	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/git"
+	"github.com/vbvictor/grit/pkg/testfiles"
+)
+
+// TestComparison compares production file with its Go test file, e.g. 'run.go' with 'run_test.go'.
+type TestComparison struct {
+	File            string
+	TestFile        string
+	Churn           float64
+	TestChurn       float64
+	Complexity      float64
+	TestComplexity  float64
+	ComplexityRatio float64
+}
+
+type fileMetrics struct {
+	churn      float64
+	complexity float64
+}
+
+// CompareTests pairs production files with their tests using churn and complexity data
+// collected for both production and test code.
+func CompareTests(
+	churnData []*git.ChurnChunk,
+	complexityData []*complexity.FileStat,
+	tests *testfiles.Filter,
+) []*TestComparison {
+	metrics := make(map[string]*fileMetrics)
+
+	get := func(path string) *fileMetrics {
+		path = normalizePath(path)
+		if _, exists := metrics[path]; !exists {
+			metrics[path] = &fileMetrics{}
+		}
+
+		return metrics[path]
+	}
+
+	for _, chunk := range churnData {
+		get(chunk.File).churn = float64(chunk.Churn)
+	}
+
+	for _, stat := range complexityData {
+		get(stat.Path).complexity = stat.AvgComplexity
+	}
+
+	result := make([]*TestComparison, 0)
+
+	for path, testMetrics := range metrics {
+		if !tests.IsTest(path) {
+			continue
+		}
+
+		prodPath := testfiles.ProductionFile(path)
+
+		prodMetrics, exists := metrics[prodPath]
+		if !exists {
+			continue
+		}
+
+		comparison := &TestComparison{
+			File:           prodPath,
+			TestFile:       path,
+			Churn:          prodMetrics.churn,
+			TestChurn:      testMetrics.churn,
+			Complexity:     prodMetrics.complexity,
+			TestComplexity: testMetrics.complexity,
+		}
+
+		if comparison.Complexity > 0 {
+			comparison.ComplexityRatio = comparison.TestComplexity / comparison.Complexity
+		}
+
+		result = append(result, comparison)
+	}
+
+	return result
+}
+
+// SortTestComparisons puts files whose tests are the most complex relative to the code first.
+func SortTestComparisons(comparisons []*TestComparison, top int) []*TestComparison {
+	slices.SortFunc(comparisons, func(lhs, rhs *TestComparison) int {
+		switch {
+		case lhs.ComplexityRatio < rhs.ComplexityRatio:
+			return 1
+		case lhs.ComplexityRatio > rhs.ComplexityRatio:
+			return -1
+		case lhs.File < rhs.File:
+			return -1
+		case lhs.File > rhs.File:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	if top > 0 && top < len(comparisons) {
+		comparisons = comparisons[:top]
+	}
+
+	return comparisons
+}
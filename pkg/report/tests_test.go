@@ -0,0 +1,54 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vbvictor/grit/pkg/complexity"
+	"github.com/vbvictor/grit/pkg/git"
+	"github.com/vbvictor/grit/pkg/testfiles"
+)
+
+func TestCompareTests(t *testing.T) {
+	churnData := []*git.ChurnChunk{
+		{File: "pkg/run.go", Churn: 10},
+		{File: "pkg/run_test.go", Churn: 25},
+		{File: "pkg/print.go", Churn: 4},
+		{File: "pkg/orphan_test.go", Churn: 3},
+	}
+
+	complexityData := []*complexity.FileStat{
+		{Path: "pkg/run.go", AvgComplexity: 4},
+		{Path: "pkg/run_test.go", AvgComplexity: 8},
+		{Path: "pkg/print.go", AvgComplexity: 2},
+		{Path: "pkg/print_test.go", AvgComplexity: 1},
+	}
+
+	filter := &testfiles.Filter{Mode: testfiles.Include}
+	require.NoError(t, filter.Compile())
+
+	got := SortTestComparisons(CompareTests(churnData, complexityData, filter), 0)
+
+	assert.Equal(t, []*TestComparison{
+		{
+			File:            "pkg/run.go",
+			TestFile:        "pkg/run_test.go",
+			Churn:           10,
+			TestChurn:       25,
+			Complexity:      4,
+			TestComplexity:  8,
+			ComplexityRatio: 2,
+		},
+		{
+			File:            "pkg/print.go",
+			TestFile:        "pkg/print_test.go",
+			Churn:           4,
+			Complexity:      2,
+			TestComplexity:  1,
+			ComplexityRatio: 0.5,
+		},
+	}, got)
+
+	assert.Len(t, SortTestComparisons(got, 1), 1)
+}
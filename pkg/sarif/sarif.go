@@ -0,0 +1,184 @@
+// Package sarif writes analysis results in SARIF 2.1.0 format
+// understood by code scanning services and editors.
+package sarif
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	Version = "2.1.0"
+	Schema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	ToolName = "grit"
+	ToolURI  = "https://github.com/vbvictor/grit"
+
+	// SrcRoot is base of relative artifact locations, resolved to analyzed path by the run.
+	SrcRoot = "SRCROOT"
+)
+
+// Level is severity of a result.
+type Level = string
+
+const (
+	Note    Level = "note"
+	Warning Level = "warning"
+	Error   Level = "error"
+)
+
+var ErrInvalidLevels = errors.New("invalid SARIF levels")
+
+// Levels are metric values starting from which results are reported as warnings and errors,
+// results with smaller values are reported as notes.
+type Levels struct {
+	Warning float64
+	Error   float64
+}
+
+// ParseLevels creates Levels from 'warning,error' pair of values.
+func ParseLevels(values []float64) (Levels, error) {
+	if len(values) != 2 { //nolint:mnd // warning and error
+		return Levels{}, fmt.Errorf("%w: expected 'warning,error' values, got %v", ErrInvalidLevels, values)
+	}
+
+	if values[0] > values[1] {
+		return Levels{}, fmt.Errorf("%w: warning level %.2f must not exceed error level %.2f",
+			ErrInvalidLevels, values[0], values[1])
+	}
+
+	return Levels{Warning: values[0], Error: values[1]}, nil
+}
+
+// Level returns severity of a result with given metric value.
+func (l Levels) Level(value float64) Level {
+	switch {
+	case value >= l.Error:
+		return Error
+	case value >= l.Warning:
+		return Warning
+	default:
+		return Note
+	}
+}
+
+type Log struct {
+	Version string `json:"version"`
+	Schema  string `json:"$schema"`
+	Runs    []Run  `json:"runs"`
+}
+
+type Run struct {
+	Tool               Tool                        `json:"tool"`
+	OriginalURIBaseIDs map[string]ArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Results            []Result                    `json:"results"`
+}
+
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+type Driver struct {
+	Name           string `json:"name"`
+	InformationURI string `json:"informationUri"`
+	Rules          []Rule `json:"rules"`
+}
+
+type Rule struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name,omitempty"`
+	ShortDescription Message  `json:"shortDescription"`
+	FullDescription  *Message `json:"fullDescription,omitempty"`
+}
+
+type Message struct {
+	Text string `json:"text,omitempty"`
+}
+
+type Result struct {
+	RuleID     string             `json:"ruleId"`
+	Level      Level              `json:"level"`
+	Message    Message            `json:"message"`
+	Locations  []Location         `json:"locations"`
+	Properties map[string]float64 `json:"properties,omitempty"`
+}
+
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           *Region          `json:"region,omitempty"`
+}
+
+type ArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type Region struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine,omitempty"`
+}
+
+// NewLog creates log with a single grit run, relative locations of results are resolved against root.
+func NewLog(root string, rules []Rule, results []Result) (*Log, error) {
+	if results == nil {
+		results = []Result{}
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path of %s: %w", root, err)
+	}
+
+	rootURI := url.URL{Scheme: "file", Path: strings.TrimSuffix(filepath.ToSlash(absRoot), "/") + "/"}
+
+	return &Log{
+		Version: Version,
+		Schema:  Schema,
+		Runs: []Run{{
+			Tool: Tool{Driver: Driver{
+				Name:           ToolName,
+				InformationURI: ToolURI,
+				Rules:          rules,
+			}},
+			OriginalURIBaseIDs: map[string]ArtifactLocation{SrcRoot: {URI: rootURI.String()}},
+			Results:            results,
+		}},
+	}, nil
+}
+
+// NewLocation creates location of a file region relative to SrcRoot, region is omitted when line is unknown.
+func NewLocation(file string, line int, length int) Location {
+	location := Location{PhysicalLocation: PhysicalLocation{
+		ArtifactLocation: ArtifactLocation{URI: filepath.ToSlash(file), URIBaseID: SrcRoot},
+	}}
+
+	if line > 0 {
+		location.PhysicalLocation.Region = &Region{StartLine: line}
+		if length > 0 {
+			location.PhysicalLocation.Region.EndLine = line + length - 1
+		}
+	}
+
+	return location
+}
+
+// Write encodes log as indented JSON.
+func Write(log *Log, out io.Writer) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(log); err != nil {
+		return fmt.Errorf("failed to write SARIF log: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package sarif
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLevels(t *testing.T) {
+	levels, err := ParseLevels([]float64{10, 20})
+	require.NoError(t, err)
+	assert.Equal(t, Levels{Warning: 10, Error: 20}, levels)
+
+	_, err = ParseLevels([]float64{10})
+	require.ErrorIs(t, err, ErrInvalidLevels)
+
+	_, err = ParseLevels([]float64{20, 10})
+	require.ErrorIs(t, err, ErrInvalidLevels)
+}
+
+func TestLevel(t *testing.T) {
+	levels := Levels{Warning: 10, Error: 20}
+
+	assert.Equal(t, Note, levels.Level(9.5))
+	assert.Equal(t, Warning, levels.Level(10))
+	assert.Equal(t, Warning, levels.Level(19))
+	assert.Equal(t, Error, levels.Level(20))
+}
+
+func TestNewLocation(t *testing.T) {
+	location := NewLocation("pkg/run.go", 10, 5)
+	assert.Equal(t, "pkg/run.go", location.PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, SrcRoot, location.PhysicalLocation.ArtifactLocation.URIBaseID)
+	assert.Equal(t, &Region{StartLine: 10, EndLine: 14}, location.PhysicalLocation.Region)
+
+	assert.Nil(t, NewLocation("run.go", 0, 0).PhysicalLocation.Region)
+	assert.Equal(t, &Region{StartLine: 3}, NewLocation("run.go", 3, 0).PhysicalLocation.Region)
+}
+
+func TestWrite(t *testing.T) {
+	rule := Rule{ID: "rule", ShortDescription: Message{Text: "description"}}
+
+	log, err := NewLog("testdata", []Rule{rule}, nil)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(log, &buf))
+
+	var decoded Log
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+
+	assert.Equal(t, Version, decoded.Version)
+	assert.Equal(t, Schema, decoded.Schema)
+	require.Len(t, decoded.Runs, 1)
+
+	run := decoded.Runs[0]
+	assert.Equal(t, ToolName, run.Tool.Driver.Name)
+	assert.Equal(t, []Rule{rule}, run.Tool.Driver.Rules)
+	assert.Empty(t, run.Results)
+	assert.Contains(t, buf.String(), `"results": []`)
+	assert.Regexp(t, `^file:///.*/testdata/$`, run.OriginalURIBaseIDs[SrcRoot].URI)
+}
@@ -0,0 +1,91 @@
+package testfiles
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Mode tells how test code is treated by metrics.
+type Mode = string
+
+const (
+	Include Mode = "include"
+	Exclude Mode = "exclude"
+	Only    Mode = "only"
+)
+
+const goTestSuffix = "_test.go"
+
+var ErrUnsupportedMode = errors.New("unsupported tests mode")
+
+// Filter separates test code from production code.
+// Files ending with '_test.go' are always test files, Patterns add more test paths, e.g. 'testutil/'.
+type Filter struct {
+	Mode     Mode
+	Patterns []string
+
+	regexps []*regexp.Regexp
+}
+
+// Compile validates mode and compiles patterns, must be called before filter is used.
+func (f *Filter) Compile() error {
+	switch f.Mode {
+	case "", Include, Exclude, Only:
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedMode, f.Mode)
+	}
+
+	f.regexps = make([]*regexp.Regexp, 0, len(f.Patterns))
+
+	for _, pattern := range f.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid test pattern: %w", err)
+		}
+
+		f.regexps = append(f.regexps, re)
+	}
+
+	return nil
+}
+
+// IsTest reports whether path belongs to test code.
+func (f *Filter) IsTest(path string) bool {
+	path = filepath.ToSlash(path)
+	if strings.HasSuffix(path, goTestSuffix) {
+		return true
+	}
+
+	for _, re := range f.regexps {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Skip reports whether path must be left out of metrics according to filter mode.
+func (f *Filter) Skip(path string) bool {
+	switch f.Mode {
+	case Exclude:
+		return f.IsTest(path)
+	case Only:
+		return !f.IsTest(path)
+	default:
+		return false
+	}
+}
+
+// ProductionFile returns file tested by given Go test file, e.g. 'run.go' for 'run_test.go'.
+// Returns empty string for files that do not follow Go test naming.
+func ProductionFile(testPath string) string {
+	if !strings.HasSuffix(testPath, goTestSuffix) {
+		return ""
+	}
+
+	return strings.TrimSuffix(testPath, goTestSuffix) + ".go"
+}
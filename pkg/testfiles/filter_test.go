@@ -0,0 +1,71 @@
+package testfiles
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterSkip(t *testing.T) {
+	files := []string{"pkg/run.go", "pkg/run_test.go", "internal/testutil/helpers.go"}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		wantKept []string
+	}{
+		{
+			name:     "default mode includes everything",
+			filter:   Filter{},
+			wantKept: files,
+		},
+		{
+			name:     "include",
+			filter:   Filter{Mode: Include, Patterns: []string{"testutil/"}},
+			wantKept: files,
+		},
+		{
+			name:     "exclude go tests",
+			filter:   Filter{Mode: Exclude},
+			wantKept: []string{"pkg/run.go", "internal/testutil/helpers.go"},
+		},
+		{
+			name:     "exclude tests and helpers",
+			filter:   Filter{Mode: Exclude, Patterns: []string{"testutil/"}},
+			wantKept: []string{"pkg/run.go"},
+		},
+		{
+			name:     "only tests and helpers",
+			filter:   Filter{Mode: Only, Patterns: []string{"testutil/"}},
+			wantKept: []string{"pkg/run_test.go", "internal/testutil/helpers.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.filter.Compile())
+
+			kept := make([]string, 0)
+
+			for _, file := range files {
+				if !tt.filter.Skip(file) {
+					kept = append(kept, file)
+				}
+			}
+
+			assert.Equal(t, tt.wantKept, kept)
+		})
+	}
+}
+
+func TestFilterCompile(t *testing.T) {
+	require.ErrorIs(t, (&Filter{Mode: "some"}).Compile(), ErrUnsupportedMode)
+	require.Error(t, (&Filter{Mode: Only, Patterns: []string{"("}}).Compile())
+}
+
+func TestProductionFile(t *testing.T) {
+	assert.Equal(t, "pkg/run.go", ProductionFile("pkg/run_test.go"))
+	assert.Empty(t, ProductionFile("pkg/run.go"))
+	assert.Empty(t, ProductionFile("testutil/helpers.go"))
+}
@@ -4,6 +4,10 @@
 A script to run the Lizard code complexity analyzer and output results in CSV format.
 Usage: 
   python lizard_complexity.py -l cpp -l c -t 8 path/to/code --output complexity.csv
+
+Use '--output -' to print results to stdout, e.g. to run the script as GRIT external engine:
+  grit stat complexity --external-engine "lizard=python3 lizard_complexity.py --output - ." \
+    --complexity-engine lizard path/to/code
 """
 
 import argparse
@@ -42,7 +46,7 @@ def parse_arguments() -> argparse.Namespace:
     )
     parser.add_argument(
         "--output", default="complexity.csv", 
-        help="Output CSV file path"
+        help="Output CSV file path, '-' for stdout"
     )
     parser.add_argument(
         "--exclude", action="append", default=[], 
@@ -72,8 +76,8 @@ def analyze_code(args: argparse.Namespace) -> List[Dict[str, Any]]:
     lizard_args.append(args.path)
     
     if args.verbose:
-        print(f"Analyzing code in {args.path}...")
-        print(f"Lizard arguments: {lizard_args}")
+        print(f"Analyzing code in {args.path}...", file=sys.stderr)
+        print(f"Lizard arguments: {lizard_args}", file=sys.stderr)
     
     analyzer = lizard.analyze(lizard_args)
     results = []
@@ -93,23 +97,30 @@ def analyze_code(args: argparse.Namespace) -> List[Dict[str, Any]]:
     return results
 
 
+def write_rows(results: List[Dict[str, Any]], csv_file) -> None:
+    """Write analysis results as CSV rows."""
+    writer = csv.writer(csv_file)
+
+    for result in results:
+        writer.writerow([
+            result["file"],
+            result["function"],
+            result["length"],
+            result["complexity"],
+            result["line"]
+        ])
+
+
 def write_csv(results: List[Dict[str, Any]], output_path: str, verbose: bool) -> None:
-    """Write analysis results to CSV file."""
-    with open(output_path, 'w', newline='') as csv_file:
-        writer = csv.writer(csv_file)
-        
-        # Write data rows
-        for result in results:
-            writer.writerow([
-                result["file"],
-                result["function"],
-                result["length"],
-                result["complexity"],
-                result["line"]
-            ])
+    """Write analysis results to CSV file or stdout."""
+    if output_path == "-":
+        write_rows(results, sys.stdout)
+    else:
+        with open(output_path, 'w', newline='') as csv_file:
+            write_rows(results, csv_file)
     
     if verbose:
-        print(f"Wrote {len(results)} functions to {output_path}")
+        print(f"Wrote {len(results)} functions to {output_path}", file=sys.stderr)
 
 
 def main() -> None:
@@ -123,7 +134,7 @@ def main() -> None:
     write_csv(results, args.output, args.verbose)
     
     if args.verbose:
-        print(f"Analysis complete. Found {len(results)} functions.")
+        print(f"Analysis complete. Found {len(results)} functions.", file=sys.stderr)
 
 
 if __name__ == "__main__":